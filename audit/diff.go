@@ -0,0 +1,66 @@
+// Package audit computes the field-level changes recorded in the audit trail.
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Change holds the before and after value of one field
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff compares the JSON representations of two snapshots field by field and
+// returns only the fields whose values differ. A nil before yields every field
+// of after (a creation); a nil after yields every field of before (a deletion).
+func Diff(before, after interface{}) (map[string]Change, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for key, beforeValue := range beforeFields {
+		afterValue, ok := afterFields[key]
+		if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[key] = Change{Before: beforeValue, After: afterValue}
+		}
+	}
+	for key, afterValue := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = Change{After: afterValue}
+		}
+	}
+
+	return changes, nil
+}
+
+// toFields decodes a snapshot into a generic field map
+func toFields(snapshot interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if snapshot == nil {
+		return fields, nil
+	}
+
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Non-object snapshots are recorded under a single key
+	if err := json.Unmarshal(body, &fields); err != nil {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"value": value}, nil
+	}
+
+	return fields, nil
+}
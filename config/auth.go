@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// SessionConfig holds session lifetime settings
+type SessionConfig struct {
+	IdleTimeout time.Duration
+	RefreshTTL  time.Duration
+}
+
+// GetSessionConfig returns the session configuration from conf/app.conf
+func GetSessionConfig() *SessionConfig {
+	return &SessionConfig{
+		IdleTimeout: time.Duration(web.AppConfig.DefaultInt("session_idle_minutes", 480)) * time.Minute,
+		RefreshTTL:  time.Duration(web.AppConfig.DefaultInt("session_refresh_days", 30)) * 24 * time.Hour,
+	}
+}
+
+// LoginPolicy holds the brute-force protection settings for staff login
+type LoginPolicy struct {
+	MaxFailuresPerNIK int
+	MaxFailuresPerIP  int
+	FailureWindow     time.Duration
+	LockoutDuration   time.Duration
+	DelayStep         time.Duration
+	MaxDelay          time.Duration
+}
+
+// GetLoginPolicy returns the login lockout policy from conf/app.conf
+func GetLoginPolicy() *LoginPolicy {
+	return &LoginPolicy{
+		MaxFailuresPerNIK: web.AppConfig.DefaultInt("login_max_failures_per_nik", 5),
+		MaxFailuresPerIP:  web.AppConfig.DefaultInt("login_max_failures_per_ip", 20),
+		FailureWindow:     time.Duration(web.AppConfig.DefaultInt("login_failure_window_minutes", 15)) * time.Minute,
+		LockoutDuration:   time.Duration(web.AppConfig.DefaultInt("login_lockout_minutes", 15)) * time.Minute,
+		DelayStep:         time.Duration(web.AppConfig.DefaultInt("login_delay_step_ms", 500)) * time.Millisecond,
+		MaxDelay:          time.Duration(web.AppConfig.DefaultInt("login_max_delay_ms", 5000)) * time.Millisecond,
+	}
+}
+
+// Delay returns how long to stall a login attempt after the given number of recent failures
+func (p *LoginPolicy) Delay(failures int) time.Duration {
+	delay := time.Duration(failures) * p.DelayStep
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// PasswordPolicy holds the strength rules for staff passwords
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	ResetCodeTTL  time.Duration
+}
+
+// GetPasswordPolicy returns the password policy from conf/app.conf
+func GetPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:     web.AppConfig.DefaultInt("password_min_length", 8),
+		RequireUpper:  web.AppConfig.DefaultBool("password_require_upper", true),
+		RequireLower:  web.AppConfig.DefaultBool("password_require_lower", true),
+		RequireDigit:  web.AppConfig.DefaultBool("password_require_digit", true),
+		RequireSymbol: web.AppConfig.DefaultBool("password_require_symbol", false),
+		ResetCodeTTL:  time.Duration(web.AppConfig.DefaultInt("password_reset_code_minutes", 30)) * time.Minute,
+	}
+}
+
+// Validate returns an error describing the first rule the password breaks
+func (p *PasswordPolicy) Validate(password string) error {
+	if len(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !hasUpper:
+		return fmt.Errorf("password must contain an uppercase letter")
+	case p.RequireLower && !hasLower:
+		return fmt.Errorf("password must contain a lowercase letter")
+	case p.RequireDigit && !hasDigit:
+		return fmt.Errorf("password must contain a digit")
+	case p.RequireSymbol && !hasSymbol:
+		return fmt.Errorf("password must contain a symbol")
+	}
+
+	return nil
+}
+
+// TwoFactorPolicy holds the TOTP two-factor authentication settings
+type TwoFactorPolicy struct {
+	Issuer            string
+	RequiredForAdmins bool
+	ChallengeTTL      time.Duration
+	Skew              int
+	RecoveryCodes     int
+}
+
+// GetTwoFactorPolicy returns the two-factor policy from conf/app.conf
+func GetTwoFactorPolicy() *TwoFactorPolicy {
+	return &TwoFactorPolicy{
+		Issuer:            web.AppConfig.DefaultString("totp_issuer", "go-pos"),
+		RequiredForAdmins: web.AppConfig.DefaultBool("totp_required_for_admins", false),
+		ChallengeTTL:      time.Duration(web.AppConfig.DefaultInt("totp_challenge_minutes", 5)) * time.Minute,
+		Skew:              web.AppConfig.DefaultInt("totp_skew_steps", 1),
+		RecoveryCodes:     web.AppConfig.DefaultInt("totp_recovery_codes", 10),
+	}
+}
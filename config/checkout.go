@@ -0,0 +1,33 @@
+package config
+
+import "github.com/beego/beego/v2/server/web"
+
+// CheckoutPolicy holds the rules applied when a sale is recorded
+type CheckoutPolicy struct {
+	// RejectClientTotals refuses sales whose client-supplied totals differ from the server prices.
+	// When false, client totals are ignored and replaced.
+	RejectClientTotals bool
+
+	// StockStrategy is "fefo" (first expiry, first out) or "fifo" (first in, first out)
+	StockStrategy string
+
+	// AllowNegativeStock lets a sale go through when the batches do not hold enough stock
+	AllowNegativeStock bool
+
+	// TaxMode is "INCLUSIVE" when catalogue prices include tax, or "EXCLUSIVE" when tax is added on top
+	TaxMode string
+
+	// RequireShift refuses payments and refunds from a cashier without an open shift
+	RequireShift bool
+}
+
+// GetCheckoutPolicy returns the checkout policy from conf/app.conf
+func GetCheckoutPolicy() *CheckoutPolicy {
+	return &CheckoutPolicy{
+		RejectClientTotals: web.AppConfig.DefaultBool("checkout_reject_client_totals", true),
+		StockStrategy:      web.AppConfig.DefaultString("checkout_stock_strategy", "fefo"),
+		AllowNegativeStock: web.AppConfig.DefaultBool("checkout_allow_negative_stock", false),
+		TaxMode:            web.AppConfig.DefaultString("checkout_tax_mode", "INCLUSIVE"),
+		RequireShift:       web.AppConfig.DefaultBool("checkout_require_shift", true),
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// IdempotencyConfig holds how long responses to requests with an Idempotency-Key are kept
+type IdempotencyConfig struct {
+	// KeyTTL is how long a key replays its response; after that it may be used again
+	KeyTTL time.Duration
+}
+
+// GetIdempotencyConfig returns the idempotency key settings from conf/app.conf
+func GetIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		KeyTTL: time.Duration(web.AppConfig.DefaultInt("idempotency_key_hours", 24)) * time.Hour,
+	}
+}
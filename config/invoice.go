@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// InvoicePolicy holds how completed sales are numbered
+type InvoicePolicy struct {
+	// Format lays out an invoice number: {store}, {register}, {date} (YYYYMMDD) and {seq} are
+	// replaced. Numbers sharing everything but {seq} form one gap-free series.
+	Format string
+
+	// StoreCode identifies the store in invoice numbers
+	StoreCode string
+
+	// DefaultRegister stands in for sales rung up without a register
+	DefaultRegister string
+
+	// Digits is the width {seq} is zero-padded to
+	Digits int
+}
+
+// GetInvoicePolicy returns the invoice numbering policy from conf/app.conf
+func GetInvoicePolicy() *InvoicePolicy {
+	return &InvoicePolicy{
+		Format:          web.AppConfig.DefaultString("invoice_format", "{store}-{register}-{date}-{seq}"),
+		StoreCode:       web.AppConfig.DefaultString("invoice_store_code", "STORE"),
+		DefaultRegister: web.AppConfig.DefaultString("invoice_default_register", "00"),
+		Digits:          web.AppConfig.DefaultInt("invoice_sequence_digits", 4),
+	}
+}
+
+// Series returns the series the invoice of a sale on register at day belongs to: the invoice
+// number with its sequence left out. Each series is numbered from 1.
+func (p *InvoicePolicy) Series(register string, day time.Time) string {
+	return p.fill(register, day, "")
+}
+
+// Number returns the seq'th invoice number of the series of register at day
+func (p *InvoicePolicy) Number(register string, day time.Time, seq int) string {
+	return p.fill(register, day, fmt.Sprintf("%0*d", p.Digits, seq))
+}
+
+// fill lays the invoice number out with seq in place of {seq}. A format without {seq} gets it
+// appended, so numbers within a series stay unique.
+func (p *InvoicePolicy) fill(register string, day time.Time, seq string) string {
+	format := p.Format
+	if !strings.Contains(format, "{seq}") {
+		format += "-{seq}"
+	}
+	if register == "" {
+		register = p.DefaultRegister
+	}
+
+	return strings.NewReplacer(
+		"{store}", p.StoreCode,
+		"{register}", register,
+		"{date}", day.Format("20060102"),
+		"{seq}", seq,
+	).Replace(format)
+}
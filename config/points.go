@@ -0,0 +1,82 @@
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// PointsPolicy holds the rules by which members earn loyalty points on completed sales and
+// spend them as a tender
+type PointsPolicy struct {
+	// RupiahPerPoint is the spend, before tax, that earns one point; 0 turns earning off
+	RupiahPerPoint int
+
+	// CategoryMultipliers scale the spend in a category before points are worked out, in
+	// percent keyed by category ID; categories without one count at 100
+	CategoryMultipliers map[int]int
+
+	// ExcludedItems never earn points
+	ExcludedItems map[int]bool
+
+	// RedeemRupiahPerPoint is what one point pays when spent on a sale; 0 turns redemption off
+	RedeemRupiahPerPoint int
+
+	// RedeemMinimum is the fewest points a sale may be paid with
+	RedeemMinimum int
+
+	// RedeemMaxShare is the largest part of a sale total points may pay, in percent
+	RedeemMaxShare int
+}
+
+// GetPointsPolicy returns the point earning and redemption rules from conf/app.conf. Multipliers are written as
+// "category:multiplier" pairs, e.g. "3:2,7:1.5", and excluded items as a list of item IDs.
+// Malformed entries are skipped.
+func GetPointsPolicy() *PointsPolicy {
+	policy := &PointsPolicy{
+		RupiahPerPoint:      web.AppConfig.DefaultInt("points_rupiah_per_point", 10000),
+		CategoryMultipliers: make(map[int]int),
+		ExcludedItems:       make(map[int]bool),
+
+		RedeemRupiahPerPoint: web.AppConfig.DefaultInt("points_redeem_rupiah_per_point", 100),
+		RedeemMinimum:        web.AppConfig.DefaultInt("points_redeem_minimum", 0),
+		RedeemMaxShare:       web.AppConfig.DefaultInt("points_redeem_max_share", 100),
+	}
+
+	for _, entry := range splitList(web.AppConfig.DefaultString("points_category_multipliers", "")) {
+		categoryStr, multiplierStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		categoryID, err := strconv.Atoi(strings.TrimSpace(categoryStr))
+		if err != nil {
+			continue
+		}
+		multiplier, err := strconv.ParseFloat(strings.TrimSpace(multiplierStr), 64)
+		if err != nil || multiplier < 0 {
+			continue
+		}
+		policy.CategoryMultipliers[categoryID] = int(math.Round(multiplier * 100))
+	}
+
+	for _, entry := range splitList(web.AppConfig.DefaultString("points_excluded_items", "")) {
+		if itemID, err := strconv.Atoi(entry); err == nil {
+			policy.ExcludedItems[itemID] = true
+		}
+	}
+
+	return policy
+}
+
+// splitList splits a comma-separated setting into its trimmed, non-empty entries
+func splitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,23 @@
+package config
+
+import "github.com/beego/beego/v2/server/web"
+
+// ReceiptConfig holds the store details printed on receipts
+type ReceiptConfig struct {
+	StoreName string
+	Address   string
+	Phone     string
+	TaxID     string // Tax registration number (NPWP), printed when set
+	Footer    string
+}
+
+// GetReceiptConfig returns the receipt configuration from conf/app.conf
+func GetReceiptConfig() *ReceiptConfig {
+	return &ReceiptConfig{
+		StoreName: web.AppConfig.DefaultString("receipt_store_name", "go-pos"),
+		Address:   web.AppConfig.DefaultString("receipt_store_address", ""),
+		Phone:     web.AppConfig.DefaultString("receipt_store_phone", ""),
+		TaxID:     web.AppConfig.DefaultString("receipt_store_tax_id", ""),
+		Footer:    web.AppConfig.DefaultString("receipt_footer", "Thank you for shopping with us"),
+	}
+}
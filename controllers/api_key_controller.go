@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyController lets admins create, list and revoke API keys for integrations
+type APIKeyController struct {
+	BaseController
+	repo *repository.APIKeyRepository
+}
+
+// CreateAPIKeyRequest represents the body for creating an API key.
+// The key acts as id_user, which defaults to the admin creating it.
+type CreateAPIKeyRequest struct {
+	Name        string                 `json:"key_name"`
+	UserID      int                    `json:"id_user"`
+	Permissions []model.PermissionCode `json:"permissions"`
+	Routes      []model.APIKeyRoute    `json:"routes"`
+	ExpiresAt   *time.Time             `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse carries a new key; the plain key is only returned here
+type CreateAPIKeyResponse struct {
+	Key    string             `json:"key"`
+	APIKey dto.APIKeyResponse `json:"api_key"`
+}
+
+// Prepare initializes the controller and restricts it to admins signed in with a session
+func (c *APIKeyController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewAPIKeyRepository()
+
+	user := c.CurrentUser()
+	if user == nil || !user.IsAdmin || c.CurrentAPIKey() != nil {
+		c.JSONResponse(http.StatusForbidden, "Admin access required", nil)
+		c.StopRun()
+	}
+}
+
+// Create issues a new API key
+func (c *APIKeyController) Create() {
+	var req CreateAPIKeyRequest
+
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSONResponse(http.StatusBadRequest, "Key name is required", nil)
+		return
+	}
+
+	if len(req.Routes) == 0 {
+		c.JSONResponse(http.StatusBadRequest, "At least one route is required", nil)
+		return
+	}
+
+	for i := range req.Routes {
+		route := &req.Routes[i]
+		route.Method = strings.ToUpper(strings.TrimSpace(route.Method))
+		if route.Method == "" {
+			route.Method = "*"
+		}
+		if !strings.HasPrefix(route.Path, "/api/") || strings.HasPrefix(route.Path, "/api/auth") {
+			c.JSONResponse(http.StatusBadRequest, "Invalid route path: "+route.Path, nil)
+			return
+		}
+	}
+
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		c.JSONResponse(http.StatusBadRequest, "Expiry must be in the future", nil)
+		return
+	}
+
+	if req.UserID == 0 {
+		req.UserID = c.CurrentUser().ID
+	}
+
+	// Check if user exists
+	_, err := repository.NewUserRepository().GetUser(req.UserID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return
+	}
+
+	key := security.NewAPIKey()
+	apiKey := &model.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    key[:len(security.APIKeyPrefix)+8],
+		KeyHash:   security.HashToken(key),
+		UserID:    req.UserID,
+		CreatedBy: c.CurrentUser().ID,
+		CreatedAt: now,
+		Routes:    req.Routes,
+	}
+	if req.ExpiresAt != nil {
+		apiKey.ExpiresAt = *req.ExpiresAt
+	}
+	for _, code := range req.Permissions {
+		apiKey.Permissions = append(apiKey.Permissions, model.Permission{Code: code})
+	}
+
+	newAPIKey, err := c.repo.CreateAPIKey(apiKey)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Failed to create API key: "+err.Error(), nil)
+		return
+	}
+
+	// Reload to return the stored permissions with their descriptions
+	newAPIKey, err = c.repo.GetAPIKey(newAPIKey.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve API key: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("api_key", newAPIKey.ID, nil, dto.NewAPIKeyResponse(newAPIKey))
+
+	c.JSONResponse(http.StatusCreated, "API key created successfully, store the key safely", CreateAPIKeyResponse{
+		Key:    key,
+		APIKey: dto.NewAPIKeyResponse(newAPIKey),
+	})
+}
+
+// Get retrieves an API key by ID
+func (c *APIKeyController) Get() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	apiKey, err := c.repo.GetAPIKey(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "API key not found", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "API key retrieved successfully", dto.NewAPIKeyResponse(apiKey))
+}
+
+// GetAll retrieves all API keys, including revoked ones
+func (c *APIKeyController) GetAll() {
+	apiKeys, err := c.repo.GetAllAPIKeys()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve API keys: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "API keys retrieved successfully", dto.NewAPIKeyResponses(apiKeys))
+}
+
+// Delete revokes an API key
+func (c *APIKeyController) Delete() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	// Check if key exists
+	existingAPIKey, err := c.repo.GetAPIKey(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "API key not found", nil)
+		return
+	}
+
+	if !existingAPIKey.RevokedAt.IsZero() {
+		c.JSONResponse(http.StatusConflict, "API key is already revoked", nil)
+		return
+	}
+
+	err = c.repo.RevokeAPIKey(id, time.Now())
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to revoke API key: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("REVOKE", "api_key", id, dto.NewAPIKeyResponse(existingAPIKey), nil)
+
+	c.JSONResponse(http.StatusOK, "API key revoked successfully", nil)
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+)
+
+// AuditController serves the audit trail of mutating API calls
+type AuditController struct {
+	BaseController
+}
+
+// GetAll retrieves audit records filtered by user, entity and date range
+func (c *AuditController) GetAll() {
+	if !c.RequirePermission(model.PermissionReportsView) {
+		return
+	}
+
+	filter := model.AuditLogFilter{
+		EntityType: c.GetString("entity"),
+		EntityID:   c.GetString("entity_id"),
+	}
+
+	var err error
+	if userIDStr := c.GetString("user_id"); userIDStr != "" {
+		filter.UserID, err = strconv.Atoi(userIDStr)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid user ID format", nil)
+			return
+		}
+	}
+
+	if limitStr := c.GetString("limit"); limitStr != "" {
+		filter.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid limit format", nil)
+			return
+		}
+	}
+
+	var ok bool
+	if filter.From, filter.To, ok = c.queryPeriod(); !ok {
+		return
+	}
+
+	auditLogs, err := repository.NewAuditLogRepository().GetAuditLogs(filter)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve audit logs: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Audit logs retrieved successfully", dto.NewAuditLogResponses(auditLogs))
+}
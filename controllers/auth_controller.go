@@ -2,9 +2,14 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
+	"fmt"
+	"go-pos/config"
 	"go-pos/model"
 	"go-pos/repository"
+	"go-pos/security"
 	"net/http"
+	"strconv"
 	"time"
 	"golang.org/x/crypto/bcrypt"
 	"github.com/google/uuid"
@@ -19,15 +24,39 @@ type AuthController struct {
 type LoginRequest struct {
 	NIK      int    `json:"nik"`
 	Password string `json:"password"`
+	Device   string `json:"device"`
 }
 
 // LoginResponse represents the login response
 type LoginResponse struct {
-	User  model.User `json:"user"`
-	Token string     `json:"token"`
+	User         dto.UserResponse `json:"user"`
+	SessionID    string           `json:"session_id"`
+	Token        string           `json:"token"`
+	RefreshToken string           `json:"refresh_token"`
+	ExpiresAt    time.Time        `json:"expires_at"`
 }
 
-// Login authenticates a user and returns a token
+// TwoFactorLoginRequest represents the second login step of a user enrolled in two-factor authentication.
+// Either a current TOTP code or an unused recovery code is accepted.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// TwoFactorChallengeResponse is returned by Login when a second factor is required
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// RefreshRequest represents the token refresh request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Login authenticates a user and opens a new session for the calling device
 func (c *AuthController) Login() {
 	var loginReq LoginRequest
 	
@@ -38,11 +67,19 @@ func (c *AuthController) Login() {
 	
 	// Create repository instances
 	userRepo := repository.NewUserRepository()
-	userLogRepo := repository.NewUserLogRepository()
+	attemptRepo := repository.NewLoginAttemptRepository()
+	policy := config.GetLoginPolicy()
+	
+	// Refuse locked NIKs and addresses and slow down repeated guesses
+	nikAttempt, ipAttempt, ok := c.checkLoginAttempts(policy, loginReq.NIK)
+	if !ok {
+		return
+	}
 	
 	// Find user by NIK
 	user, err := userRepo.GetUserByNIK(loginReq.NIK)
 	if err != nil {
+		c.recordLoginFailure(policy, nil, nikAttempt, ipAttempt)
 		c.JSONResponse(http.StatusUnauthorized, "Invalid credentials", nil)
 		return
 	}
@@ -50,18 +87,118 @@ func (c *AuthController) Login() {
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(loginReq.Password))
 	if err != nil {
+		c.recordLoginFailure(policy, user, nikAttempt, ipAttempt)
 		c.JSONResponse(http.StatusUnauthorized, "Invalid credentials", nil)
 		return
 	}
 	
-	// Generate a new token
-	token := uuid.New().String()
+	// Enrolled users must prove a second factor before a session is opened
+	totpEnabled, err := repository.NewUserTOTPRepository().IsTOTPEnabled(user.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check two-factor enrollment", nil)
+		return
+	}
+	
+	if totpEnabled {
+		c.issueLoginChallenge(user, loginReq.Device)
+		return
+	}
+	
+	// A successful login clears the NIK counter; the address counter only decays with time
+	attemptRepo.DeleteLoginAttempt(nikAttempt.Key)
+	
+	c.completeLogin(user, loginReq.Device)
+}
+
+// VerifyLogin completes a two-factor login with a TOTP code or a recovery code
+func (c *AuthController) VerifyLogin() {
+	var verifyReq TwoFactorLoginRequest
+	
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &verifyReq); err != nil || verifyReq.ChallengeToken == "" {
+		c.JSONResponse(http.StatusBadRequest, "Challenge token is required", nil)
+		return
+	}
+	
+	challengeRepo := repository.NewLoginChallengeRepository()
+	
+	challenge, err := challengeRepo.GetLoginChallengeByTokenHash(security.HashToken(verifyReq.ChallengeToken))
+	if err != nil {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid login challenge", nil)
+		return
+	}
+	
+	if time.Now().After(challenge.ExpiresAt) {
+		challengeRepo.DeleteLoginChallenge(challenge.ID)
+		c.JSONResponse(http.StatusUnauthorized, "Login challenge expired, please log in again", nil)
+		return
+	}
+	
+	user, err := repository.NewUserRepository().GetUser(challenge.UserID)
+	if err != nil {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid login challenge", nil)
+		return
+	}
+	
+	// Second-factor guesses count towards the same lockout as passwords
+	policy := config.GetLoginPolicy()
+	nikAttempt, ipAttempt, ok := c.checkLoginAttempts(policy, user.NIK)
+	if !ok {
+		return
+	}
 	
-	// Update the user's token
-	user.Token = token
-	_, err = userRepo.UpdateUser(user)
+	verified, err := verifySecondFactor(user.ID, verifyReq.Code, verifyReq.RecoveryCode)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to update user token", nil)
+		c.JSONResponse(http.StatusInternalServerError, "Failed to verify two-factor code", nil)
+		return
+	}
+	
+	if !verified {
+		c.recordLoginFailure(policy, user, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusUnauthorized, "Invalid two-factor code", nil)
+		return
+	}
+	
+	// The challenge is single use
+	challengeRepo.DeleteLoginChallenge(challenge.ID)
+	repository.NewLoginAttemptRepository().DeleteLoginAttempt(nikAttempt.Key)
+	
+	c.completeLogin(user, challenge.Device)
+}
+
+// issueLoginChallenge responds with a short-lived challenge token that VerifyLogin redeems
+func (c *AuthController) issueLoginChallenge(user *model.User, device string) {
+	now := time.Now()
+	token := security.NewToken()
+	
+	challenge := &model.LoginChallenge{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		Device:    device,
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.GetTwoFactorPolicy().ChallengeTTL),
+	}
+	
+	if _, err := repository.NewLoginChallengeRepository().CreateLoginChallenge(challenge); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create login challenge", nil)
+		return
+	}
+	
+	response := TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         challenge.ExpiresAt,
+	}
+	
+	c.JSONResponse(http.StatusAccepted, "Two-factor code required", response)
+}
+
+// completeLogin opens a session for the device, logs the login and writes the login response
+func (c *AuthController) completeLogin(user *model.User, device string) {
+	// Open a session for this device; other devices stay logged in
+	session, token, refreshToken, err := c.openSession(user.ID, device)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create session", nil)
 		return
 	}
 	
@@ -71,53 +208,199 @@ func (c *AuthController) Login() {
 		Date:            time.Now(),
 		IP:              c.Ctx.Input.IP(),
 		PlatformBrowser: c.Ctx.Request.UserAgent(),
+		Action:          "LOGIN",
 	}
 	
 	// Save the user log
-	_, err = userLogRepo.CreateUserLog(userLog)
+	_, err = repository.NewUserLogRepository().CreateUserLog(userLog)
 	if err != nil {
 		// Log the error but continue - non-critical operation
 		// In a production environment, you might want to log this error properly
 		// fmt.Printf("Error logging user login: %v\n", err)
 	}
 	
-	// Don't include password hash in response
-	user.PasswordHash = ""
-	
 	response := LoginResponse{
-		User:  *user,
-		Token: token,
+		User:         dto.NewUserResponse(user),
+		SessionID:    session.ID,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    session.ExpiresAt,
 	}
 	
 	c.JSONResponse(http.StatusOK, "Login successful", response)
 }
 
-// Logout logs out a user
-func (c *AuthController) Logout() {
-	// Get the authorization token
-	token := c.Ctx.Input.Header("Authorization")
-	if token == "" {
-		c.JSONResponse(http.StatusBadRequest, "No authorization token provided", nil)
+// checkLoginAttempts loads the failure counters for a NIK and the caller's address.
+// It responds with 423 and returns false when either is locked; otherwise it applies
+// the progressive delay for recent failures before returning the counters.
+func (c *BaseController) checkLoginAttempts(policy *config.LoginPolicy, nik int) (*model.LoginAttempt, *model.LoginAttempt, bool) {
+	attemptRepo := repository.NewLoginAttemptRepository()
+	now := time.Now()
+	
+	nikAttempt, err := attemptRepo.GetLoginAttempt(nikAttemptKey(nik))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check login attempts", nil)
+		return nil, nil, false
+	}
+	ipAttempt, err := attemptRepo.GetLoginAttempt(ipAttemptKey(c.Ctx.Input.IP()))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check login attempts", nil)
+		return nil, nil, false
+	}
+	
+	for _, attempt := range []*model.LoginAttempt{nikAttempt, ipAttempt} {
+		if attempt.IsLocked(now) {
+			retryAfter := int(attempt.LockedUntil.Sub(now).Seconds()) + 1
+			c.Ctx.Output.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSONResponse(http.StatusLocked, "Too many failed login attempts, try again later", nil)
+			return nil, nil, false
+		}
+	}
+	
+	// Each recent failure slows the next guess down
+	failures := nikAttempt.RecentFailures(now, policy.FailureWindow)
+	if ipFailures := ipAttempt.RecentFailures(now, policy.FailureWindow); ipFailures > failures {
+		failures = ipFailures
+	}
+	time.Sleep(policy.Delay(failures))
+	
+	return nikAttempt, ipAttempt, true
+}
+
+// recordLoginFailure counts a failed login against the NIK and the address,
+// and writes a LOGIN_FAILED user log entry when the NIK belongs to a user
+func (c *BaseController) recordLoginFailure(policy *config.LoginPolicy, user *model.User, nikAttempt, ipAttempt *model.LoginAttempt) {
+	now := time.Now()
+	attemptRepo := repository.NewLoginAttemptRepository()
+	
+	nikAttempt.RegisterFailure(now, policy.FailureWindow, policy.MaxFailuresPerNIK, policy.LockoutDuration)
+	attemptRepo.SaveLoginAttempt(nikAttempt)
+	
+	ipAttempt.RegisterFailure(now, policy.FailureWindow, policy.MaxFailuresPerIP, policy.LockoutDuration)
+	attemptRepo.SaveLoginAttempt(ipAttempt)
+	
+	// Unknown NIKs have no user row to attach the log entry to
+	if user == nil {
 		return
 	}
 	
-	// Create repository instance
-	userRepo := repository.NewUserRepository()
+	userLog := &model.UserLog{
+		UserID:          user.ID,
+		Date:            now,
+		IP:              c.Ctx.Input.IP(),
+		PlatformBrowser: c.Ctx.Request.UserAgent(),
+		Action:          "LOGIN_FAILED",
+	}
+	repository.NewUserLogRepository().CreateUserLog(userLog)
+}
+
+// nikAttemptKey returns the login_attempt key that counts failures for a NIK
+func nikAttemptKey(nik int) string {
+	return fmt.Sprintf("nik:%d", nik)
+}
+
+// ipAttemptKey returns the login_attempt key that counts failures for a client address
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// openSession creates a session and returns it with its plain access and refresh tokens
+func (c *AuthController) openSession(userID int, device string) (*model.UserSession, string, string, error) {
+	sessionConfig := config.GetSessionConfig()
+	now := time.Now()
+	token := security.NewToken()
+	refreshToken := security.NewToken()
+	
+	session := &model.UserSession{
+		ID:               uuid.New().String(),
+		UserID:           userID,
+		Device:           device,
+		TokenHash:        security.HashToken(token),
+		RefreshTokenHash: security.HashToken(refreshToken),
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(sessionConfig.IdleTimeout),
+		RefreshExpiresAt: now.Add(sessionConfig.RefreshTTL),
+	}
+	
+	if _, err := repository.NewUserSessionRepository().CreateSession(session); err != nil {
+		return nil, "", "", err
+	}
+	
+	return session, token, refreshToken, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair on the same session
+func (c *AuthController) Refresh() {
+	var refreshReq RefreshRequest
+	
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &refreshReq); err != nil || refreshReq.RefreshToken == "" {
+		c.JSONResponse(http.StatusBadRequest, "Refresh token is required", nil)
+		return
+	}
 	
-	// Find the user by token
-	user, err := userRepo.GetUserByToken(token)
+	sessionRepo := repository.NewUserSessionRepository()
+	
+	session, err := sessionRepo.GetSessionByRefreshTokenHash(security.HashToken(refreshReq.RefreshToken))
 	if err != nil {
-		c.JSONResponse(http.StatusUnauthorized, "Invalid token", nil)
+		c.JSONResponse(http.StatusUnauthorized, "Invalid refresh token", nil)
+		return
+	}
+	
+	now := time.Now()
+	if now.After(session.RefreshExpiresAt) {
+		sessionRepo.DeleteSession(session.ID)
+		c.JSONResponse(http.StatusUnauthorized, "Refresh token expired", nil)
 		return
 	}
 	
-	// Invalidate the token by generating a new one
-	user.Token = uuid.New().String() // Generate a new token that won't match what the client has
+	user, err := repository.NewUserRepository().GetUser(session.UserID)
+	if err != nil {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid refresh token", nil)
+		return
+	}
+	
+	// Rotate both tokens so a leaked refresh token can only be used once
+	sessionConfig := config.GetSessionConfig()
+	token := security.NewToken()
+	refreshToken := security.NewToken()
+	session.TokenHash = security.HashToken(token)
+	session.RefreshTokenHash = security.HashToken(refreshToken)
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(sessionConfig.IdleTimeout)
+	session.RefreshExpiresAt = now.Add(sessionConfig.RefreshTTL)
+	
+	if _, err := sessionRepo.RotateTokens(session); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to refresh session", nil)
+		return
+	}
+	
+	response := LoginResponse{
+		User:         dto.NewUserResponse(user),
+		SessionID:    session.ID,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    session.ExpiresAt,
+	}
+	
+	c.JSONResponse(http.StatusOK, "Session refreshed successfully", response)
+}
+
+// Logout ends the session the request was made with
+func (c *AuthController) Logout() {
+	// The auth filter has already resolved the token to a user and session; API keys have no
+	// session to end
+	user := c.CurrentUser()
+	session := c.CurrentSession()
+	if session == nil {
+		c.JSONResponse(http.StatusUnauthorized, "No session to log out of", nil)
+		return
+	}
 	
-	// Update the user
-	_, err = userRepo.UpdateUser(user)
+	// Revoke the session
+	err := repository.NewUserSessionRepository().DeleteSession(session.ID)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to update user token", nil)
+		c.JSONResponse(http.StatusInternalServerError, "Failed to end session", nil)
 		return
 	}
 	
@@ -137,5 +420,210 @@ func (c *AuthController) Logout() {
 		// Log the error but continue - non-critical operation
 	}
 	
+	c.AuditAs("LOGOUT", "session", session.ID, nil, nil)
+	
 	c.JSONResponse(http.StatusOK, "Logout successful", nil)
 }
+
+// GetSessions lists the active sessions of the current user
+func (c *AuthController) GetSessions() {
+	current := c.CurrentSession()
+	
+	sessions, err := repository.NewUserSessionRepository().GetSessionsByUser(c.CurrentUser().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sessions: "+err.Error(), nil)
+		return
+	}
+	
+	for i := range sessions {
+		sessions[i].Current = current != nil && sessions[i].ID == current.ID
+	}
+	
+	c.JSONResponse(http.StatusOK, "Sessions retrieved successfully", dto.NewSessionResponses(sessions))
+}
+
+// RevokeSession ends one of the current user's sessions
+func (c *AuthController) RevokeSession() {
+	id := c.Ctx.Input.Param(":id")
+	
+	sessionRepo := repository.NewUserSessionRepository()
+	
+	// Only the owner may revoke a session
+	session, err := sessionRepo.GetSession(id)
+	if err != nil || session.UserID != c.CurrentUser().ID {
+		c.JSONResponse(http.StatusNotFound, "Session not found", nil)
+		return
+	}
+	
+	if err := sessionRepo.DeleteSession(id); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to revoke session: "+err.Error(), nil)
+		return
+	}
+	
+	c.AuditAs("REVOKE", "session", id, dto.NewSessionResponse(session), nil)
+	
+	c.JSONResponse(http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeAllSessions ends the current user's sessions on all devices.
+// Pass keep_current=true to stay logged in on the calling device.
+func (c *AuthController) RevokeAllSessions() {
+	exceptID := ""
+	if keepCurrent, _ := c.GetBool("keep_current"); keepCurrent && c.CurrentSession() != nil {
+		exceptID = c.CurrentSession().ID
+	}
+	
+	err := repository.NewUserSessionRepository().DeleteSessionsByUser(c.CurrentUser().ID, exceptID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to revoke sessions: "+err.Error(), nil)
+		return
+	}
+	
+	c.AuditAs("REVOKE", "session", "", nil, nil)
+	
+	c.JSONResponse(http.StatusOK, "Sessions revoked successfully", nil)
+}
+
+// ChangePasswordRequest represents the change-password request body
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ResetPasswordRequest represents the body for redeeming an admin-issued reset code
+type ResetPasswordRequest struct {
+	NIK         int    `json:"nik"`
+	Code        string `json:"code"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword lets the current user replace their own password.
+// All of the user's sessions, including the current one, are ended afterwards.
+func (c *AuthController) ChangePassword() {
+	var req ChangePasswordRequest
+	
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+	
+	userRepo := repository.NewUserRepository()
+	
+	// Reload the user to get the current password hash
+	user, err := userRepo.GetUser(c.CurrentUser().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return
+	}
+	
+	// Wrong old passwords count towards the login lockout, or a stolen session could guess them
+	policy := config.GetLoginPolicy()
+	nikAttempt, ipAttempt, ok := c.checkLoginAttempts(policy, user.NIK)
+	if !ok {
+		return
+	}
+	
+	// Verify the old password
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword))
+	if err != nil {
+		c.recordLoginFailure(policy, user, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusUnauthorized, "Old password is incorrect", nil)
+		return
+	}
+	
+	repository.NewLoginAttemptRepository().DeleteLoginAttempt(nikAttempt.Key)
+	
+	if req.NewPassword == req.OldPassword {
+		c.JSONResponse(http.StatusBadRequest, "New password must differ from the old password", nil)
+		return
+	}
+	
+	if !c.setPassword(user, req.NewPassword, "PASSWORD_CHANGED") {
+		return
+	}
+	
+	c.AuditAs("PASSWORD_CHANGE", "user", user.ID, nil, nil)
+	
+	c.JSONResponse(http.StatusOK, "Password changed successfully, please log in again", nil)
+}
+
+// ResetPassword sets a new password using a one-time code issued by an administrator
+func (c *AuthController) ResetPassword() {
+	var req ResetPasswordRequest
+	
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+	
+	// Reset codes are guessable credentials too, so they share the login lockout
+	policy := config.GetLoginPolicy()
+	nikAttempt, ipAttempt, ok := c.checkLoginAttempts(policy, req.NIK)
+	if !ok {
+		return
+	}
+	
+	user, err := repository.NewUserRepository().GetUserByNIK(req.NIK)
+	if err != nil {
+		c.recordLoginFailure(policy, nil, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusUnauthorized, "Invalid or expired reset code", nil)
+		return
+	}
+	
+	resetRepo := repository.NewPasswordResetRepository()
+	reset, err := resetRepo.GetActivePasswordReset(user.ID, security.HashToken(req.Code), time.Now())
+	if err != nil {
+		c.recordLoginFailure(policy, user, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusUnauthorized, "Invalid or expired reset code", nil)
+		return
+	}
+	
+	if !c.setPassword(user, req.NewPassword, "PASSWORD_RESET") {
+		return
+	}
+	
+	// The code is single use, and a successful reset clears any lockout on the NIK
+	resetRepo.MarkPasswordResetUsed(reset.ID, time.Now())
+	repository.NewLoginAttemptRepository().DeleteLoginAttempt(nikAttempt.Key)
+	
+	c.JSONResponse(http.StatusOK, "Password reset successfully, please log in", nil)
+}
+
+// setPassword validates a new password against the policy, stores it, ends every session
+// of the user and logs the action. It writes the error response and returns false on failure.
+func (c *AuthController) setPassword(user *model.User, password string, action string) bool {
+	if err := config.GetPasswordPolicy().Validate(password); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Password rejected: "+err.Error(), nil)
+		return false
+	}
+	
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to hash password", nil)
+		return false
+	}
+	
+	err = repository.NewUserRepository().UpdatePassword(user.ID, string(hashedPassword))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update password: "+err.Error(), nil)
+		return false
+	}
+	
+	// Tokens issued under the old password must stop working
+	err = repository.NewUserSessionRepository().DeleteSessionsByUser(user.ID, "")
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to end sessions: "+err.Error(), nil)
+		return false
+	}
+	
+	userLog := &model.UserLog{
+		UserID:          user.ID,
+		Date:            time.Now(),
+		IP:              c.Ctx.Input.IP(),
+		PlatformBrowser: c.Ctx.Request.UserAgent(),
+		Action:          action,
+	}
+	repository.NewUserLogRepository().CreateUserLog(userLog)
+	
+	return true
+}
@@ -1,9 +1,37 @@
 package controllers
 
 import (
+	"fmt"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strings"
+	"time"
+
 	beego "github.com/beego/beego/v2/server/web"
 )
 
+// Context data keys under which the auth filter stores the authenticated caller
+const (
+	CurrentUserKey    = "currentUser"
+	CurrentSessionKey = "currentSession"
+	CurrentMemberKey  = "currentMember"
+	CurrentAPIKeyKey  = "currentAPIKey"
+)
+
+// AuditKey is the context data key under which handlers leave the audit entry for the audit filter
+const AuditKey = "auditEntry"
+
+// AuditEntry describes the change a mutating request made, recorded by the audit filter
+// once the response has been written successfully
+type AuditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+}
+
 // BaseController defines common methods for all controllers
 type BaseController struct {
 	beego.Controller
@@ -16,13 +44,120 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// JSONResponse returns a standardized JSON response
+// JSONResponse returns a standardized JSON response. A response to a request with an
+// Idempotency-Key is kept for retries of the request.
 func (c *BaseController) JSONResponse(status int, message string, data interface{}) {
-	c.Ctx.Output.SetStatus(status)
-	c.Data["json"] = Response{
+	response := Response{
 		Status:  status,
 		Message: message,
 		Data:    data,
 	}
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = response
 	c.ServeJSON()
+	c.finishIdempotent(status, response)
+}
+
+// CurrentUser returns the user resolved by the auth filter, or nil on public routes
+func (c *BaseController) CurrentUser() *model.User {
+	user, ok := c.Ctx.Input.GetData(CurrentUserKey).(*model.User)
+	if !ok {
+		return nil
+	}
+	return user
+}
+
+// CurrentSession returns the session the current request was authenticated with
+func (c *BaseController) CurrentSession() *model.UserSession {
+	session, ok := c.Ctx.Input.GetData(CurrentSessionKey).(*model.UserSession)
+	if !ok {
+		return nil
+	}
+	return session
+}
+
+// CurrentMember returns the member resolved by the member auth filter on /api/me routes
+func (c *BaseController) CurrentMember() *model.Member {
+	member, ok := c.Ctx.Input.GetData(CurrentMemberKey).(*model.Member)
+	if !ok {
+		return nil
+	}
+	return member
+}
+
+// CurrentAPIKey returns the API key the current request was authenticated with, or nil for user sessions
+func (c *BaseController) CurrentAPIKey() *model.APIKey {
+	apiKey, ok := c.Ctx.Input.GetData(CurrentAPIKeyKey).(*model.APIKey)
+	if !ok {
+		return nil
+	}
+	return apiKey
+}
+
+// recordMemberAccess writes a user_member access log entry for a member self-service call
+func (c *BaseController) recordMemberAccess(memberID int) {
+	userMember := &model.UserMember{
+		MemberID:        memberID,
+		Date:            time.Now(),
+		IP:              c.Ctx.Input.IP(),
+		PlatformBrowser: c.Ctx.Request.UserAgent(),
+	}
+
+	// Non-critical: a failed access log must not block the member
+	repository.NewUserMemberRepository().CreateUserMember(userMember)
+}
+
+// HasPermission reports whether the current user holds the permission through one of their roles.
+// Admin users are granted every permission. Requests made with an API key are limited to the key's permissions.
+func (c *BaseController) HasPermission(code model.PermissionCode) bool {
+	user := c.CurrentUser()
+	if user == nil {
+		return false
+	}
+	if apiKey := c.CurrentAPIKey(); apiKey != nil {
+		return apiKey.HasPermission(code)
+	}
+	if user.IsAdmin {
+		return true
+	}
+
+	granted, err := repository.NewRoleRepository().UserHasPermission(user.ID, code)
+	return err == nil && granted
+}
+
+// RequirePermission responds with 403 and returns false when the current user lacks the permission
+func (c *BaseController) RequirePermission(code model.PermissionCode) bool {
+	if c.HasPermission(code) {
+		return true
+	}
+
+	c.JSONResponse(http.StatusForbidden, "Missing permission: "+string(code), nil)
+	return false
+}
+
+// Audit records the before and after snapshot of the entity changed by the current request.
+// The action is derived from the HTTP method by the audit filter.
+func (c *BaseController) Audit(entityType string, entityID interface{}, before, after interface{}) {
+	c.AuditAs("", entityType, entityID, before, after)
+}
+
+// AuditAs records a change under an explicit action such as UNLOCK or PASSWORD_RESET
+func (c *BaseController) AuditAs(action string, entityType string, entityID interface{}, before, after interface{}) {
+	c.Ctx.Input.SetData(AuditKey, &AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     before,
+		After:      after,
+	})
+}
+
+// BearerToken extracts the token from an Authorization header value.
+// Both "Bearer <token>" and a bare token are accepted.
+func BearerToken(header string) string {
+	header = strings.TrimSpace(header)
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return header
 }
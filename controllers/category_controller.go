@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -36,7 +37,9 @@ func (c *CategoryController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Category created successfully", newCategory)
+	c.Audit("category", newCategory.ID, nil, dto.NewCategoryResponse(newCategory))
+	
+	c.JSONResponse(http.StatusCreated, "Category created successfully", dto.NewCategoryResponse(newCategory))
 }
 
 // Get retrieves a category by ID
@@ -54,7 +57,7 @@ func (c *CategoryController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Category retrieved successfully", category)
+	c.JSONResponse(http.StatusOK, "Category retrieved successfully", dto.NewCategoryResponse(category))
 }
 
 // GetAll retrieves all categories
@@ -65,7 +68,7 @@ func (c *CategoryController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Categories retrieved successfully", categories)
+	c.JSONResponse(http.StatusOK, "Categories retrieved successfully", dto.NewCategoryResponses(categories))
 }
 
 // Update updates a category
@@ -86,7 +89,7 @@ func (c *CategoryController) Update() {
 	category.ID = id
 	
 	// Check if category exists
-	_, err = c.repo.GetCategory(id)
+	existingCategory, err := c.repo.GetCategory(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Category not found", nil)
 		return
@@ -99,7 +102,9 @@ func (c *CategoryController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Category updated successfully", updatedCategory)
+	c.Audit("category", id, dto.NewCategoryResponse(existingCategory), dto.NewCategoryResponse(updatedCategory))
+	
+	c.JSONResponse(http.StatusOK, "Category updated successfully", dto.NewCategoryResponse(updatedCategory))
 }
 
 // Delete deletes a category
@@ -112,7 +117,7 @@ func (c *CategoryController) Delete() {
 	}
 	
 	// Check if category exists
-	_, err = c.repo.GetCategory(id)
+	existingCategory, err := c.repo.GetCategory(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Category not found", nil)
 		return
@@ -137,5 +142,7 @@ func (c *CategoryController) Delete() {
 		return
 	}
 	
+	c.Audit("category", id, dto.NewCategoryResponse(existingCategory), nil)
+	
 	c.JSONResponse(http.StatusOK, "Category deleted successfully", nil)
 }
\ No newline at end of file
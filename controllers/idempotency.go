@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/config"
+	"go-pos/model"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header a client sets to make a money-moving POST safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader marks a response replayed from an earlier request with the same key
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+// IdempotencyKeyData is the context data key under which the key reserved by the current request is kept
+const IdempotencyKeyData = "idempotencyKey"
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// Idempotent honours the Idempotency-Key header of the current request. The first request with a
+// key reserves it and its successful response is stored for the key; a retry of the same request
+// gets that response back instead of running again. Requests without the header run as usual.
+// It writes the replayed or error response and returns false when the request must not run.
+func (c *BaseController) Idempotent() bool {
+	key := c.Ctx.Input.Header(IdempotencyKeyHeader)
+	if key == "" {
+		return true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		c.JSONResponse(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters", nil)
+		return false
+	}
+
+	userID := 0
+	if user := c.CurrentUser(); user != nil {
+		userID = user.ID
+	}
+
+	now := time.Now()
+	idempotencyKey := &model.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Route:       c.Ctx.Input.Method() + " " + c.Ctx.Input.URL(),
+		RequestHash: security.HashToken(string(c.Ctx.Input.RequestBody)),
+		CreatedAt:   now,
+	}
+
+	repo := repository.NewIdempotencyKeyRepository()
+
+	// Expired keys may be used again
+	if err := repo.DeleteIdempotencyKeysBefore(now.Add(-config.GetIdempotencyConfig().KeyTTL)); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to expire idempotency keys: "+err.Error(), nil)
+		return false
+	}
+
+	reserved, err := repo.ReserveIdempotencyKey(idempotencyKey)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to reserve idempotency key: "+err.Error(), nil)
+		return false
+	}
+	if reserved {
+		c.Ctx.Input.SetData(IdempotencyKeyData, idempotencyKey)
+		return true
+	}
+
+	stored, err := repo.GetIdempotencyKey(userID, key)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve idempotency key: "+err.Error(), nil)
+		return false
+	}
+	if !stored.Matches(idempotencyKey.Route, idempotencyKey.RequestHash) {
+		c.JSONResponse(http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request", nil)
+		return false
+	}
+	if stored.IsPending() {
+		c.JSONResponse(http.StatusConflict, "A request with this Idempotency-Key is still being processed", nil)
+		return false
+	}
+
+	c.EnableRender = false
+	c.Ctx.Output.Header("Content-Type", "application/json; charset=utf-8")
+	c.Ctx.Output.Header(IdempotentReplayHeader, "true")
+	c.Ctx.Output.SetStatus(stored.StatusCode)
+	c.Ctx.Output.Body([]byte(stored.Response))
+	return false
+}
+
+// finishIdempotent settles the key reserved by the current request once it is answered. A
+// successful response is stored for retries to replay; on failure nothing was changed, so the
+// key is released and a retry runs again.
+func (c *BaseController) finishIdempotent(status int, response interface{}) {
+	idempotencyKey, ok := c.Ctx.Input.GetData(IdempotencyKeyData).(*model.IdempotencyKey)
+	if !ok || idempotencyKey == nil {
+		return
+	}
+	c.Ctx.Input.SetData(IdempotencyKeyData, nil)
+
+	repo := repository.NewIdempotencyKeyRepository()
+	body, err := json.Marshal(response)
+	if status >= http.StatusBadRequest || err != nil {
+		repo.DeleteIdempotencyKey(idempotencyKey.UserID, idempotencyKey.Key)
+		return
+	}
+
+	// Non-critical: the change has been committed; an unsaved response leaves the key pending until it expires
+	repo.SaveIdempotencyResponse(idempotencyKey.UserID, idempotencyKey.Key, status, string(body))
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -53,7 +54,9 @@ func (c *ItemBatchController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Item batch created successfully", newItemBatch)
+	c.Audit("item_batch", newItemBatch.ID, nil, dto.NewItemBatchResponse(newItemBatch))
+	
+	c.JSONResponse(http.StatusCreated, "Item batch created successfully", dto.NewItemBatchResponse(newItemBatch))
 }
 
 // Get retrieves an item batch by ID
@@ -71,7 +74,7 @@ func (c *ItemBatchController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Item batch retrieved successfully", itemBatch)
+	c.JSONResponse(http.StatusOK, "Item batch retrieved successfully", dto.NewItemBatchResponse(itemBatch))
 }
 
 // GetAll retrieves all item batches
@@ -99,7 +102,7 @@ func (c *ItemBatchController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Item batches retrieved successfully", itemBatches)
+	c.JSONResponse(http.StatusOK, "Item batches retrieved successfully", dto.NewItemBatchResponses(itemBatches))
 }
 
 // Update updates an item batch
@@ -120,7 +123,7 @@ func (c *ItemBatchController) Update() {
 	itemBatch.ID = id
 	
 	// Check if item batch exists
-	_, err = c.repo.GetItemBatch(id)
+	existingItemBatch, err := c.repo.GetItemBatch(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Item batch not found", nil)
 		return
@@ -133,7 +136,9 @@ func (c *ItemBatchController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Item batch updated successfully", updatedItemBatch)
+	c.Audit("item_batch", id, dto.NewItemBatchResponse(existingItemBatch), dto.NewItemBatchResponse(updatedItemBatch))
+	
+	c.JSONResponse(http.StatusOK, "Item batch updated successfully", dto.NewItemBatchResponse(updatedItemBatch))
 }
 
 // Delete deletes an item batch
@@ -146,7 +151,7 @@ func (c *ItemBatchController) Delete() {
 	}
 	
 	// Check if item batch exists
-	_, err = c.repo.GetItemBatch(id)
+	existingItemBatch, err := c.repo.GetItemBatch(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Item batch not found", nil)
 		return
@@ -159,5 +164,7 @@ func (c *ItemBatchController) Delete() {
 		return
 	}
 	
+	c.Audit("item_batch", id, dto.NewItemBatchResponse(existingItemBatch), nil)
+	
 	c.JSONResponse(http.StatusOK, "Item batch deleted successfully", nil)
 }
\ No newline at end of file
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -14,6 +16,32 @@ type ItemController struct {
 	repo *repository.ItemRepository
 }
 
+// ItemRequest is the body of an item create or update. On update, an SKU, tax rate or barcode
+// list left out of the request keeps the saved one, while an empty one (a tax rate of 0)
+// clears it.
+type ItemRequest struct {
+	model.Item
+	SKU       *string `json:"sku"`
+	TaxRateID *int    `json:"id_tax_rate"`
+}
+
+// ToItem returns the item the request describes, taking the SKU and tax rate it leaves out
+// from the saved item, if any. Barcodes it leaves out stay nil, which UpdateItem keeps as saved.
+func (r *ItemRequest) ToItem(saved *model.Item) model.Item {
+	item := r.Item
+	if r.SKU != nil {
+		item.SKU = *r.SKU
+	} else if saved != nil {
+		item.SKU = saved.SKU
+	}
+	if r.TaxRateID != nil {
+		item.TaxRateID = *r.TaxRateID
+	} else if saved != nil {
+		item.TaxRateID = saved.TaxRateID
+	}
+	return item
+}
+
 // Prepare initializes the controller
 func (c *ItemController) Prepare() {
 	// Initialize the repository
@@ -22,21 +50,29 @@ func (c *ItemController) Prepare() {
 
 // Create adds a new item
 func (c *ItemController) Create() {
-	var item model.Item
+	var request ItemRequest
 	
-	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &item); err != nil {
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
 		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
 		return
 	}
 	
+	item := request.ToItem(nil)
+	if err := item.ValidateCodes(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	
 	// Save the item to database
 	newItem, err := c.repo.CreateItem(&item)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to create item: "+err.Error(), nil)
+		c.itemSaveFailed("create", err)
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Item created successfully", newItem)
+	c.Audit("item", newItem.ID, nil, dto.NewItemResponse(newItem))
+	
+	c.JSONResponse(http.StatusCreated, "Item created successfully", dto.NewItemResponse(newItem))
 }
 
 // Get retrieves an item by ID
@@ -54,7 +90,27 @@ func (c *ItemController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Item retrieved successfully", item)
+	c.JSONResponse(http.StatusOK, "Item retrieved successfully", dto.NewItemResponse(item))
+}
+
+// Scan resolves a scanned barcode or SKU to its item, with the current price and the quantity on hand.
+// The code is the rest of the path, so Code128 labels containing '/' can be looked up too.
+func (c *ItemController) Scan() {
+	code := c.Ctx.Input.Param(":splat")
+	
+	item, err := c.repo.GetItemByCode(code)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "No item found for code "+code, nil)
+		return
+	}
+	
+	stock, err := repository.NewItemBatchRepository().GetStockByItem(item.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve stock: "+err.Error(), nil)
+		return
+	}
+	
+	c.JSONResponse(http.StatusOK, "Item retrieved successfully", dto.NewItemScanResponse(item, stock))
 }
 
 // GetAll retrieves all items
@@ -83,7 +139,7 @@ func (c *ItemController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Items retrieved successfully", items)
+	c.JSONResponse(http.StatusOK, "Items retrieved successfully", dto.NewItemResponses(items))
 }
 
 // Update updates an item
@@ -95,29 +151,42 @@ func (c *ItemController) Update() {
 		return
 	}
 	
-	var item model.Item
-	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &item); err != nil {
+	var request ItemRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
 		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
 		return
 	}
 	
-	item.ID = id
-	
 	// Check if item exists
-	_, err = c.repo.GetItem(id)
+	existingItem, err := c.repo.GetItem(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Item not found", nil)
 		return
 	}
 	
+	item := request.ToItem(existingItem)
+	item.ID = id
+	
+	if err := item.ValidateCodes(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	
+	// Changing the price needs its own permission
+	if item.Price != existingItem.Price && !c.RequirePermission(model.PermissionItemsPriceEdit) {
+		return
+	}
+	
 	// Update the item
 	updatedItem, err := c.repo.UpdateItem(&item)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to update item: "+err.Error(), nil)
+		c.itemSaveFailed("update", err)
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Item updated successfully", updatedItem)
+	c.Audit("item", id, dto.NewItemResponse(existingItem), dto.NewItemResponse(updatedItem))
+	
+	c.JSONResponse(http.StatusOK, "Item updated successfully", dto.NewItemResponse(updatedItem))
 }
 
 // Delete deletes an item
@@ -130,7 +199,7 @@ func (c *ItemController) Delete() {
 	}
 	
 	// Check if item exists
-	_, err = c.repo.GetItem(id)
+	existingItem, err := c.repo.GetItem(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Item not found", nil)
 		return
@@ -143,5 +212,18 @@ func (c *ItemController) Delete() {
 		return
 	}
 	
+	c.Audit("item", id, dto.NewItemResponse(existingItem), nil)
+	
 	c.JSONResponse(http.StatusOK, "Item deleted successfully", nil)
-}
\ No newline at end of file
+}
+
+// itemSaveFailed writes the response for an item that could not be saved: a conflict when
+// its SKU or a barcode belongs to another item, otherwise a server error
+func (c *ItemController) itemSaveFailed(action string, err error) {
+	var duplicate *repository.DuplicateCodeError
+	if errors.As(err, &duplicate) {
+		c.JSONResponse(http.StatusConflict, err.Error(), nil)
+		return
+	}
+	c.JSONResponse(http.StatusInternalServerError, "Failed to "+action+" item: "+err.Error(), nil)
+}
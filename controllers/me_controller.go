@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+)
+
+// MeController serves the member self-service account API
+type MeController struct {
+	BaseController
+	memberRepo *repository.MemberRepository
+}
+
+// Prepare initializes the controller and records the member's access
+func (c *MeController) Prepare() {
+	// Initialize the repository
+	c.memberRepo = repository.NewMemberRepository()
+
+	c.recordMemberAccess(c.CurrentMember().ID)
+}
+
+// Get retrieves the current member's profile and points balance
+func (c *MeController) Get() {
+	member, err := c.memberRepo.GetMember(c.CurrentMember().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Member not found", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Member retrieved successfully", dto.NewMemberResponse(member))
+}
+
+// GetPoints retrieves the current member's point history
+func (c *MeController) GetPoints() {
+	points, err := repository.NewMemberPointRepository().GetMemberPointsByMember(c.CurrentMember().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member points: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Member points retrieved successfully", dto.NewMemberPointResponses(points))
+}
+
+// GetSales retrieves the current member's past sales baskets with their items
+func (c *MeController) GetSales() {
+	salesBaskets, err := repository.NewSalesBasketRepository().GetSalesBasketsByMember(c.CurrentMember().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales baskets: "+err.Error(), nil)
+		return
+	}
+
+	itemRepo := repository.NewSalesItemRepository()
+	for i := range salesBaskets {
+		var items []model.SalesItem
+		items, err = itemRepo.GetSalesItemsBySales(salesBaskets[i].ID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales items: "+err.Error(), nil)
+			return
+		}
+		salesBaskets[i].Items = items
+	}
+
+	c.JSONResponse(http.StatusOK, "Sales baskets retrieved successfully", dto.NewSalesBasketResponses(salesBaskets))
+}
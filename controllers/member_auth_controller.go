@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/dto"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MemberAuthController handles member self-service authentication
+type MemberAuthController struct {
+	BaseController
+	repo *repository.MemberRepository
+}
+
+// MemberLoginRequest represents the member login request body
+type MemberLoginRequest struct {
+	Phone    int    `json:"phone_member"`
+	Password string `json:"password"`
+}
+
+// MemberLoginResponse represents the member login response
+type MemberLoginResponse struct {
+	Member dto.MemberResponse `json:"member"`
+	Token  string             `json:"token"`
+}
+
+// Prepare initializes the controller
+func (c *MemberAuthController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewMemberRepository()
+}
+
+// Login authenticates a member by phone number and password and returns a token
+func (c *MemberAuthController) Login() {
+	var loginReq MemberLoginRequest
+
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &loginReq); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	// Find member by phone
+	member, err := c.repo.GetMemberCredentialsByPhone(loginReq.Phone)
+	if err != nil || member.PasswordHash == "" {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid credentials", nil)
+		return
+	}
+
+	// Verify password
+	err = bcrypt.CompareHashAndPassword([]byte(member.PasswordHash), []byte(loginReq.Password))
+	if err != nil {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid credentials", nil)
+		return
+	}
+
+	// Issue a new token; only its digest is stored
+	token := security.NewToken()
+	err = c.repo.UpdateMemberToken(member.ID, security.HashToken(token))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update member token", nil)
+		return
+	}
+
+	// Record the access
+	c.recordMemberAccess(member.ID)
+
+	response := MemberLoginResponse{
+		Member: dto.NewMemberResponse(member),
+		Token:  token,
+	}
+
+	c.JSONResponse(http.StatusOK, "Login successful", response)
+}
+
+// Logout invalidates the current member token
+func (c *MemberAuthController) Logout() {
+	member := c.CurrentMember()
+
+	err := c.repo.UpdateMemberToken(member.ID, "")
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update member token", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Logout successful", nil)
+}
@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
 	"strconv"
 	"time"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MemberController handles Member CRUD operations
@@ -15,6 +17,13 @@ type MemberController struct {
 	repo *repository.MemberRepository
 }
 
+// MemberRequest is the body of a member create or update. The self-service password is
+// plaintext here and only ever stored hashed.
+type MemberRequest struct {
+	model.Member
+	Password string `json:"password"`
+}
+
 // Prepare initializes the controller
 func (c *MemberController) Prepare() {
 	// Initialize the repository
@@ -23,12 +32,13 @@ func (c *MemberController) Prepare() {
 
 // Create adds a new member
 func (c *MemberController) Create() {
-	var member model.Member
+	var request MemberRequest
 	
-	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &member); err != nil {
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
 		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
 		return
 	}
+	member := request.Member
 	
 	// Validate required fields
 	if member.Name == "" {
@@ -45,6 +55,16 @@ func (c *MemberController) Create() {
 	member.JoinDate = time.Now()
 	member.Points = 0
 	
+	// Hash the self-service password if one was provided
+	if request.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to hash password", nil)
+			return
+		}
+		member.PasswordHash = string(hashedPassword)
+	}
+	
 	// Save the member to database
 	newMember, err := c.repo.CreateMember(&member)
 	if err != nil {
@@ -52,7 +72,9 @@ func (c *MemberController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Member created successfully", newMember)
+	c.Audit("member", newMember.ID, nil, dto.NewMemberResponse(newMember))
+	
+	c.JSONResponse(http.StatusCreated, "Member created successfully", dto.NewMemberResponse(newMember))
 }
 
 // Get retrieves a member by ID
@@ -70,7 +92,7 @@ func (c *MemberController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member retrieved successfully", member)
+	c.JSONResponse(http.StatusOK, "Member retrieved successfully", dto.NewMemberResponse(member))
 }
 
 // GetAll retrieves all members
@@ -99,7 +121,7 @@ func (c *MemberController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Members retrieved successfully", members)
+	c.JSONResponse(http.StatusOK, "Members retrieved successfully", dto.NewMemberResponses(members))
 }
 
 // Update updates a member
@@ -111,16 +133,17 @@ func (c *MemberController) Update() {
 		return
 	}
 	
-	var member model.Member
-	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &member); err != nil {
+	var request MemberRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
 		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
 		return
 	}
 	
+	member := request.Member
 	member.ID = id
 	
 	// Check if member exists
-	_, err = c.repo.GetMember(id)
+	existingMember, err := c.repo.GetMember(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Member not found", nil)
 		return
@@ -133,7 +156,24 @@ func (c *MemberController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member updated successfully", updatedMember)
+	// If the self-service password is being set, hash and store it
+	if request.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to hash password", nil)
+			return
+		}
+		
+		err = c.repo.UpdateMemberPassword(id, string(hashedPassword))
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to update member password: "+err.Error(), nil)
+			return
+		}
+	}
+	
+	c.Audit("member", id, dto.NewMemberResponse(existingMember), dto.NewMemberResponse(updatedMember))
+	
+	c.JSONResponse(http.StatusOK, "Member updated successfully", dto.NewMemberResponse(updatedMember))
 }
 
 // Delete deletes a member
@@ -146,7 +186,7 @@ func (c *MemberController) Delete() {
 	}
 	
 	// Check if member exists
-	_, err = c.repo.GetMember(id)
+	existingMember, err := c.repo.GetMember(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Member not found", nil)
 		return
@@ -171,6 +211,8 @@ func (c *MemberController) Delete() {
 		return
 	}
 	
+	c.Audit("member", id, dto.NewMemberResponse(existingMember), nil)
+	
 	c.JSONResponse(http.StatusOK, "Member deleted successfully", nil)
 }
 
@@ -185,7 +227,7 @@ func (c *MemberController) GetAllPoints() {
         return
     }
     
-    c.JSONResponse(http.StatusOK, "Member points retrieved successfully", points)
+    c.JSONResponse(http.StatusOK, "Member points retrieved successfully", dto.NewMemberPointResponses(points))
 }
 
 // GetPoint retrieves a member point by ID
@@ -204,7 +246,7 @@ func (c *MemberController) GetPoint() {
         return
     }
     
-    c.JSONResponse(http.StatusOK, "Member point retrieved successfully", memberPoint)
+    c.JSONResponse(http.StatusOK, "Member point retrieved successfully", dto.NewMemberPointResponse(memberPoint))
 }
 
 // UpdatePoint updates a member point
@@ -224,38 +266,12 @@ func (c *MemberController) UpdatePoint() {
     
     memberPoint.ID = id
     
-    // Check if point exists
-    memberPointRepo := repository.NewMemberPointRepository()
-    existingPoint, err := memberPointRepo.GetMemberPoint(id)
-    if err != nil {
-        c.JSONResponse(http.StatusNotFound, "Member point not found", nil)
+    existingPoint, ok := c.updateMemberPoint(&memberPoint)
+    if !ok {
         return
     }
     
-    // If points are changing, update the member's total points
-    if existingPoint.Points != memberPoint.Points {
-        // Get the member
-        member, err := c.repo.GetMember(memberPoint.MemberID)
-        if err != nil {
-            c.JSONResponse(http.StatusNotFound, "Member not found", nil)
-            return
-        }
-        
-        // Update member's total points (subtract old points, add new points)
-        member.Points = member.Points - existingPoint.Points + memberPoint.Points
-        _, err = c.repo.UpdateMember(member)
-        if err != nil {
-            c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
-            return
-        }
-    }
-    
-    // Update the point record
-    updatedPoint, err := memberPointRepo.UpdateMemberPoint(&memberPoint)
-    if err != nil {
-        c.JSONResponse(http.StatusInternalServerError, "Failed to update member point: "+err.Error(), nil)
-        return
-    }
+    c.Audit("member_point", id, dto.NewMemberPointResponse(existingPoint), dto.NewMemberPointResponse(&memberPoint))
     
-    c.JSONResponse(http.StatusOK, "Member point updated successfully", updatedPoint)
+    c.JSONResponse(http.StatusOK, "Member point updated successfully", dto.NewMemberPointResponse(&memberPoint))
 }
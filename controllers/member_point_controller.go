@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -25,6 +26,10 @@ func (c *MemberPointController) Prepare() {
 
 // Create adds a new member point transaction
 func (c *MemberPointController) Create() {
+	if !c.Idempotent() {
+		return
+	}
+	
 	var memberPoint model.MemberPoint
 	
 	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &memberPoint); err != nil {
@@ -48,27 +53,30 @@ func (c *MemberPointController) Create() {
 		return
 	}
 	
-	// Check if member exists
-	member, err := c.memberRepo.GetMember(memberPoint.MemberID)
+	// Create transaction
+	tx, err := database.DB.Begin()
 	if err != nil {
-		c.JSONResponse(http.StatusNotFound, "Member not found", nil)
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
 		return
 	}
 	
-	// For REDEEMED points, check if member has sufficient points
-	if memberPoint.Type == model.PointTypeRedeemed && member.Points < memberPoint.Points {
-		c.JSONResponse(http.StatusBadRequest, "Member does not have sufficient points to redeem", nil)
+	// Lock the member's balance so concurrent changes cannot overwrite each other
+	balance, err := c.memberRepo.GetPointsForUpdateTx(tx, memberPoint.MemberID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Member not found", nil)
 		return
 	}
 	
-	// Create transaction
-	tx, err := database.DB.Begin()
-	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+	// For REDEEMED points, check if member has sufficient points
+	if balance+memberPoint.Delta() < 0 {
+		tx.Rollback()
+		c.JSONResponse(http.StatusBadRequest, "Member does not have sufficient points to redeem", nil)
 		return
 	}
 	
-	// Save the point transaction
+	// Save the point transaction; manual ones are never tied to a sale
+	memberPoint.SalesID = 0
 	newMemberPoint, err := c.repo.CreateMemberPointTx(tx, &memberPoint)
 	if err != nil {
 		tx.Rollback()
@@ -77,15 +85,7 @@ func (c *MemberPointController) Create() {
 	}
 	
 	// Update member's points balance
-	if memberPoint.Type == model.PointTypeEarned {
-		member.Points += memberPoint.Points
-	} else {
-		member.Points -= memberPoint.Points
-	}
-	
-	// Update the member record
-	_, err = c.memberRepo.UpdateMemberTx(tx, member)
-	if err != nil {
+	if err := c.memberRepo.AdjustPointsTx(tx, memberPoint.MemberID, memberPoint.Delta()); err != nil {
 		tx.Rollback()
 		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
 		return
@@ -98,7 +98,9 @@ func (c *MemberPointController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Member point transaction created successfully", newMemberPoint)
+	c.Audit("member_point", newMemberPoint.ID, nil, dto.NewMemberPointResponse(newMemberPoint))
+	
+	c.JSONResponse(http.StatusCreated, "Member point transaction created successfully", dto.NewMemberPointResponse(newMemberPoint))
 }
 
 // Get retrieves a member point transaction by ID
@@ -116,7 +118,7 @@ func (c *MemberPointController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member point transaction retrieved successfully", memberPoint)
+	c.JSONResponse(http.StatusOK, "Member point transaction retrieved successfully", dto.NewMemberPointResponse(memberPoint))
 }
 
 // GetAll retrieves all member point transactions
@@ -137,7 +139,7 @@ func (c *MemberPointController) GetAll() {
 		
 		if typeFilter != "" {
 			// Validate type
-			if typeFilter != string(model.PointTypeEarned) && typeFilter != string(model.PointTypeRedeemed) {
+			if !model.PointType(typeFilter).IsValid() {
 				c.JSONResponse(http.StatusBadRequest, "Invalid point type filter", nil)
 				return
 			}
@@ -149,7 +151,7 @@ func (c *MemberPointController) GetAll() {
 	} else {
 		if typeFilter != "" {
 			// Validate type
-			if typeFilter != string(model.PointTypeEarned) && typeFilter != string(model.PointTypeRedeemed) {
+			if !model.PointType(typeFilter).IsValid() {
 				c.JSONResponse(http.StatusBadRequest, "Invalid point type filter", nil)
 				return
 			}
@@ -165,7 +167,7 @@ func (c *MemberPointController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member point transactions retrieved successfully", memberPoints)
+	c.JSONResponse(http.StatusOK, "Member point transactions retrieved successfully", dto.NewMemberPointResponses(memberPoints))
 }
 
 // Update updates a member point transaction
@@ -185,64 +187,70 @@ func (c *MemberPointController) Update() {
 	
 	memberPoint.ID = id
 	
-	// Get the original point transaction
-	originalPoint, err := c.repo.GetMemberPoint(id)
-	if err != nil {
-		c.JSONResponse(http.StatusNotFound, "Member point transaction not found", err)
+	originalPoint, ok := c.updateMemberPoint(&memberPoint)
+	if !ok {
 		return
 	}
+	
+	c.Audit("member_point", id, dto.NewMemberPointResponse(originalPoint), dto.NewMemberPointResponse(&memberPoint))
+	
+	c.JSONResponse(http.StatusOK, "Member point transaction updated successfully", dto.NewMemberPointResponse(&memberPoint))
+}
 
-	// Get the member
-	member, err := c.memberRepo.GetMember(originalPoint.MemberID)
+// Delete deletes a member point transaction
+func (c *MemberPointController) Delete() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member", err)
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
 		return
 	}
 	
-	// Calculate the points adjustment needed
-	var pointsAdjustment int
-	
-	// Reverse the original transaction effect
-	if originalPoint.Type == model.PointTypeEarned {
-		pointsAdjustment -= originalPoint.Points
-	} else {
-		pointsAdjustment += originalPoint.Points
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
 	}
 	
-	// Apply the new transaction effect
-	if memberPoint.Type == model.PointTypeEarned {
-		pointsAdjustment += memberPoint.Points
-	} else {
-		pointsAdjustment -= memberPoint.Points
+	// Lock the point transaction, then the member's balance
+	memberPoint, err := c.repo.GetMemberPointForUpdateTx(tx, id)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Member point transaction not found", nil)
+		return
 	}
 	
-	// Check if the adjustment would result in negative points
-	if member.Points + pointsAdjustment < 0 {
-		c.JSONResponse(http.StatusBadRequest, "Update would result in negative member points", nil)
+	if memberPoint.SalesID != 0 {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, "Points of a sale can only change through a void or return", nil)
 		return
 	}
 	
-	// Create transaction
-	tx, err := database.DB.Begin()
+	balance, err := c.memberRepo.GetPointsForUpdateTx(tx, memberPoint.MemberID)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member: "+err.Error(), nil)
 		return
 	}
 	
-	// Update the point transaction
-	updatedMemberPoint, err := c.repo.UpdateMemberPointTx(tx, &memberPoint)
+	// Check if reversing the transaction would result in negative points
+	if balance-memberPoint.Delta() < 0 {
+		tx.Rollback()
+		c.JSONResponse(http.StatusBadRequest, "Deletion would result in negative member points", nil)
+		return
+	}
+	
+	// Delete the point transaction
+	err = c.repo.DeleteMemberPointTx(tx, id)
 	if err != nil {
 		tx.Rollback()
-		c.JSONResponse(http.StatusInternalServerError, "Failed to update point transaction: "+err.Error(), nil)
+		c.JSONResponse(http.StatusInternalServerError, "Failed to delete point transaction: "+err.Error(), nil)
 		return
 	}
 	
 	// Update member's points balance
-	member.Points += pointsAdjustment
-	
-	// Update the member record
-	_, err = c.memberRepo.UpdateMemberTx(tx, member)
-	if err != nil {
+	if err := c.memberRepo.AdjustPointsTx(tx, memberPoint.MemberID, -memberPoint.Delta()); err != nil {
 		tx.Rollback()
 		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
 		return
@@ -255,81 +263,82 @@ func (c *MemberPointController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member point transaction updated successfully", updatedMemberPoint)
+	c.Audit("member_point", id, dto.NewMemberPointResponse(memberPoint), nil)
+	
+	c.JSONResponse(http.StatusOK, "Member point transaction deleted successfully", nil)
 }
 
-// Delete deletes a member point transaction
-func (c *MemberPointController) Delete() {
-	idStr := c.Ctx.Input.Param(":id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
-		return
+// updateMemberPoint changes the type and points of a manual point transaction and moves the
+// member's balance by the difference, with both rows locked. The member and sale stay as saved.
+// It writes the error response and returns false on failure; on success it returns the
+// transaction as it was before the change.
+func (c *BaseController) updateMemberPoint(memberPoint *model.MemberPoint) (*model.MemberPoint, bool) {
+	if memberPoint.Points <= 0 {
+		c.JSONResponse(http.StatusBadRequest, "Points must be greater than zero", nil)
+		return nil, false
 	}
 	
-	// Get the point transaction
-	memberPoint, err := c.repo.GetMemberPoint(id)
-	if err != nil {
-		c.JSONResponse(http.StatusNotFound, "Member point transaction not found", nil)
-		return
+	if memberPoint.Type != model.PointTypeEarned && memberPoint.Type != model.PointTypeRedeemed {
+		c.JSONResponse(http.StatusBadRequest, "Point type must be either EARNED or REDEEMED", nil)
+		return nil, false
 	}
 	
-	// Get the member
-	member, err := c.memberRepo.GetMember(memberPoint.MemberID)
+	tx, err := database.DB.Begin()
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member: "+err.Error(), nil)
-		return
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return nil, false
 	}
 	
-	// Calculate the points adjustment needed
-	var pointsAdjustment int
-	
-	// Reverse the transaction effect
-	if memberPoint.Type == model.PointTypeEarned {
-		pointsAdjustment = -memberPoint.Points
-	} else {
-		pointsAdjustment = memberPoint.Points
+	// Lock the point transaction, then the member's balance
+	pointRepo := repository.NewMemberPointRepository()
+	originalPoint, err := pointRepo.GetMemberPointForUpdateTx(tx, memberPoint.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Member point transaction not found", nil)
+		return nil, false
 	}
 	
-	// Check if the adjustment would result in negative points
-	if member.Points + pointsAdjustment < 0 {
-		c.JSONResponse(http.StatusBadRequest, "Deletion would result in negative member points", nil)
-		return
+	if originalPoint.SalesID != 0 {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, "Points of a sale can only change through a void or return", nil)
+		return nil, false
 	}
 	
-	// Create transaction
-	tx, err := database.DB.Begin()
+	memberRepo := repository.NewMemberRepository()
+	balance, err := memberRepo.GetPointsForUpdateTx(tx, originalPoint.MemberID)
 	if err != nil {
-		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
-		return
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member: "+err.Error(), nil)
+		return nil, false
 	}
 	
-	// Delete the point transaction
-	err = c.repo.DeleteMemberPointTx(tx, id)
-	if err != nil {
+	memberPoint.MemberID = originalPoint.MemberID
+	memberPoint.SalesID = 0
+	pointsAdjustment := memberPoint.Delta() - originalPoint.Delta()
+	
+	if balance+pointsAdjustment < 0 {
 		tx.Rollback()
-		c.JSONResponse(http.StatusInternalServerError, "Failed to delete point transaction: "+err.Error(), nil)
-		return
+		c.JSONResponse(http.StatusBadRequest, "Update would result in negative member points", nil)
+		return nil, false
 	}
 	
-	// Update member's points balance
-	member.Points += pointsAdjustment
+	if _, err := pointRepo.UpdateMemberPointTx(tx, memberPoint); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update point transaction: "+err.Error(), nil)
+		return nil, false
+	}
 	
-	// Update the member record
-	_, err = c.memberRepo.UpdateMemberTx(tx, member)
-	if err != nil {
+	if err := memberRepo.AdjustPointsTx(tx, originalPoint.MemberID, pointsAdjustment); err != nil {
 		tx.Rollback()
 		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
-		return
+		return nil, false
 	}
 	
-	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
-		return
+		return nil, false
 	}
 	
-	c.JSONResponse(http.StatusOK, "Member point transaction deleted successfully", nil)
+	return originalPoint, true
 }
-
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+)
+
+// PromotionController handles Promotion CRUD operations and the promotion usage report
+type PromotionController struct {
+	BaseController
+	repo *repository.PromotionRepository
+}
+
+// Prepare initializes the controller
+func (c *PromotionController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewPromotionRepository()
+}
+
+// Create adds a new promotion
+func (c *PromotionController) Create() {
+	if !c.RequirePermission(model.PermissionPromotionsManage) {
+		return
+	}
+
+	var promotion model.Promotion
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &promotion); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := promotion.Validate(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid promotion: "+err.Error(), nil)
+		return
+	}
+
+	newPromotion, err := c.repo.CreatePromotion(&promotion)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create promotion: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("promotion", newPromotion.ID, nil, dto.NewPromotionResponse(newPromotion))
+
+	c.JSONResponse(http.StatusCreated, "Promotion created successfully", dto.NewPromotionResponse(newPromotion))
+}
+
+// Get retrieves a promotion by ID
+func (c *PromotionController) Get() {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	promotion, err := c.repo.GetPromotion(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Promotion not found", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Promotion retrieved successfully", dto.NewPromotionResponse(promotion))
+}
+
+// GetAll retrieves all promotions, or only the switched-on ones with ?active=true
+func (c *PromotionController) GetAll() {
+	var promotions []model.Promotion
+	var err error
+
+	if active, _ := c.GetBool("active"); active {
+		promotions, err = c.repo.GetActivePromotions()
+	} else {
+		promotions, err = c.repo.GetAllPromotions()
+	}
+
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve promotions: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Promotions retrieved successfully", dto.NewPromotionResponses(promotions))
+}
+
+// Update updates a promotion. Sales already discounted by it keep their discounts.
+func (c *PromotionController) Update() {
+	if !c.RequirePermission(model.PermissionPromotionsManage) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var promotion model.Promotion
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &promotion); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	promotion.ID = id
+
+	if err := promotion.Validate(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid promotion: "+err.Error(), nil)
+		return
+	}
+
+	// Check if promotion exists
+	existingPromotion, err := c.repo.GetPromotion(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Promotion not found", nil)
+		return
+	}
+
+	updatedPromotion, err := c.repo.UpdatePromotion(&promotion)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update promotion: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("promotion", id, dto.NewPromotionResponse(existingPromotion), dto.NewPromotionResponse(updatedPromotion))
+
+	c.JSONResponse(http.StatusOK, "Promotion updated successfully", dto.NewPromotionResponse(updatedPromotion))
+}
+
+// Delete deletes a promotion that was never applied; used promotions are switched off instead
+// so the usage report keeps them
+func (c *PromotionController) Delete() {
+	if !c.RequirePermission(model.PermissionPromotionsManage) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	// Check if promotion exists
+	existingPromotion, err := c.repo.GetPromotion(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Promotion not found", nil)
+		return
+	}
+
+	used, err := c.repo.PromotionHasSales(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check promotion usage: "+err.Error(), nil)
+		return
+	}
+	if used {
+		c.JSONResponse(http.StatusConflict, "Promotion has been applied to sales; switch it off instead", nil)
+		return
+	}
+
+	if err := c.repo.DeletePromotion(id); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to delete promotion: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("promotion", id, dto.NewPromotionResponse(existingPromotion), nil)
+
+	c.JSONResponse(http.StatusOK, "Promotion deleted successfully", nil)
+}
+
+// GetUsage reports the discounts every promotion gave on paid sales, optionally between the
+// from and to dates
+func (c *PromotionController) GetUsage() {
+	if !c.RequirePermission(model.PermissionReportsView) {
+		return
+	}
+
+	from, to, ok := c.reportPeriod()
+	if !ok {
+		return
+	}
+
+	usage, err := c.repo.GetPromotionUsage(from, to)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve promotion usage: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Promotion usage retrieved successfully", dto.NewPromotionUsageResponses(usage))
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+)
+
+// parsePeriodTime accepts either a date (2006-01-02) or an RFC 3339 timestamp
+func parsePeriodTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// queryPeriod reads the optional from and to query parameters, each zero when not given;
+// a plain to date includes the whole day. It writes the error response and returns false when
+// a date is malformed.
+func (c *BaseController) queryPeriod() (time.Time, time.Time, bool) {
+	var from, to time.Time
+	var err error
+
+	if fromStr := c.GetString("from"); fromStr != "" {
+		from, _, err = parsePeriodTime(fromStr)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid from date format", nil)
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if toStr := c.GetString("to"); toStr != "" {
+		var dateOnly bool
+		to, dateOnly, err = parsePeriodTime(toStr)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid to date format", nil)
+			return time.Time{}, time.Time{}, false
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	return from, to, true
+}
+
+// reportPeriod reads the optional from and to dates of a report as Unix timestamps, zero when
+// not given, for reports over sales dates. It writes the error response and returns false when
+// a date is malformed.
+func (c *BaseController) reportPeriod() (int, int, bool) {
+	from, to, ok := c.queryPeriod()
+	if !ok {
+		return 0, 0, false
+	}
+	return unixOrZero(from), unixOrZero(to), true
+}
+
+// unixOrZero returns the Unix timestamp of t, or zero for the zero time
+func unixOrZero(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return int(t.Unix())
+}
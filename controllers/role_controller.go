@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+)
+
+// RoleController handles Role CRUD operations and permission assignment
+type RoleController struct {
+	BaseController
+	repo *repository.RoleRepository
+}
+
+// RolePermissionsRequest represents the body for replacing a role's permissions
+type RolePermissionsRequest struct {
+	Permissions []model.PermissionCode `json:"permissions"`
+}
+
+// UserRolesRequest represents the body for replacing a user's roles
+type UserRolesRequest struct {
+	RoleIDs []int `json:"role_ids"`
+}
+
+// Prepare initializes the controller and restricts it to user managers
+func (c *RoleController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewRoleRepository()
+
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		c.StopRun()
+	}
+}
+
+// Create adds a new role
+func (c *RoleController) Create() {
+	var role model.Role
+
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &role); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if role.Name == "" {
+		c.JSONResponse(http.StatusBadRequest, "Role name is required", nil)
+		return
+	}
+
+	newRole, err := c.repo.CreateRole(&role)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create role: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("role", newRole.ID, nil, dto.NewRoleResponse(newRole))
+
+	c.JSONResponse(http.StatusCreated, "Role created successfully", dto.NewRoleResponse(newRole))
+}
+
+// Get retrieves a role by ID together with its permissions
+func (c *RoleController) Get() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	role, err := c.repo.GetRole(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	role.Permissions, err = c.repo.GetRolePermissions(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve role permissions: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Role retrieved successfully", dto.NewRoleResponse(role))
+}
+
+// GetAll retrieves all roles
+func (c *RoleController) GetAll() {
+	roles, err := c.repo.GetAllRoles()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve roles: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Roles retrieved successfully", dto.NewRoleResponses(roles))
+}
+
+// Update updates a role
+func (c *RoleController) Update() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var role model.Role
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &role); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	role.ID = id
+
+	// Check if role exists
+	existingRole, err := c.repo.GetRole(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	updatedRole, err := c.repo.UpdateRole(&role)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update role: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("role", id, dto.NewRoleResponse(existingRole), dto.NewRoleResponse(updatedRole))
+
+	c.JSONResponse(http.StatusOK, "Role updated successfully", dto.NewRoleResponse(updatedRole))
+}
+
+// Delete deletes a role
+func (c *RoleController) Delete() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	// Check if role exists
+	existingRole, err := c.repo.GetRole(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	err = c.repo.DeleteRole(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to delete role: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("role", id, dto.NewRoleResponse(existingRole), nil)
+
+	c.JSONResponse(http.StatusOK, "Role deleted successfully", nil)
+}
+
+// GetAllPermissions retrieves every permission that can be granted
+func (c *RoleController) GetAllPermissions() {
+	permissions, err := c.repo.GetAllPermissions()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve permissions: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Permissions retrieved successfully", dto.NewPermissionResponses(permissions))
+}
+
+// SetPermissions replaces the permissions granted to a role
+func (c *RoleController) SetPermissions() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var req RolePermissionsRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	// Check if role exists
+	role, err := c.repo.GetRole(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Role not found", nil)
+		return
+	}
+
+	role.Permissions, err = c.repo.GetRolePermissions(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve role permissions: "+err.Error(), nil)
+		return
+	}
+	before := dto.NewRoleResponse(role)
+
+	if err := c.repo.SetRolePermissions(id, req.Permissions); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Failed to update role permissions: "+err.Error(), nil)
+		return
+	}
+
+	role.Permissions, err = c.repo.GetRolePermissions(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve role permissions: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("role", id, before, dto.NewRoleResponse(role))
+
+	c.JSONResponse(http.StatusOK, "Role permissions updated successfully", dto.NewRoleResponse(role))
+}
+
+// GetUserRoles retrieves the roles assigned to a user
+func (c *RoleController) GetUserRoles() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	roles, err := c.repo.GetUserRoles(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve user roles: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "User roles retrieved successfully", dto.NewRoleResponses(roles))
+}
+
+// SetUserRoles replaces the roles assigned to a user
+func (c *RoleController) SetUserRoles() {
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var req UserRolesRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	// Check if user exists
+	_, err = repository.NewUserRepository().GetUser(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return
+	}
+
+	previousRoles, err := c.repo.GetUserRoles(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve user roles: "+err.Error(), nil)
+		return
+	}
+
+	if err := c.repo.SetUserRoles(id, req.RoleIDs); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Failed to update user roles: "+err.Error(), nil)
+		return
+	}
+
+	roles, err := c.repo.GetUserRoles(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve user roles: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("ASSIGN_ROLES", "user", id, dto.NewRoleResponses(previousRoles), dto.NewRoleResponses(roles))
+
+	c.JSONResponse(http.StatusOK, "User roles updated successfully", dto.NewRoleResponses(roles))
+}
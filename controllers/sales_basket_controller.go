@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/database" // Add this import
 	"go-pos/model"
 	"go-pos/repository"
@@ -26,6 +27,11 @@ func (c *SalesBasketController) Prepare() {
 
 // Create adds a new sales basket
 func (c *SalesBasketController) Create() {
+	// A retry carrying the same Idempotency-Key gets the first sale back instead of a second one
+	if !c.Idempotent() {
+		return
+	}
+	
 	var salesBasket model.SalesBasket
 	
 	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &salesBasket); err != nil {
@@ -33,28 +39,42 @@ func (c *SalesBasketController) Create() {
 		return
 	}
 	
-	// Validate required fields
-	if salesBasket.UserID <= 0 {
-		c.JSONResponse(http.StatusBadRequest, "User ID is required", nil)
-		return
-	}
+	// The cashier is always the authenticated user, never the client-supplied ID
+	salesBasket.UserID = c.CurrentUser().ID
+	
+	// Invoice numbers are only ever handed out at completion
+	salesBasket.InvoiceNo = ""
 	
 	// Set current time for sales date if not provided
 	if salesBasket.SalesDate == 0 {
 		salesBasket.SalesDate = int(time.Now().Unix())
 	}
 	
+	// A basket is either rung up and paid in one call, or opened to be completed later
+	if salesBasket.Status == "" {
+		salesBasket.Status = model.SalesStatusCompleted
+	}
+	if salesBasket.Status != model.SalesStatusCompleted && salesBasket.Status != model.SalesStatusOpen {
+		c.JSONResponse(http.StatusBadRequest, "New sales must be OPEN or COMPLETED", nil)
+		return
+	}
+	
 	// Check that we have at least one item
 	if len(salesBasket.Items) == 0 {
 		c.JSONResponse(http.StatusBadRequest, "At least one item is required", nil)
 		return
 	}
 	
-	// Calculate total if not provided
-	if salesBasket.Total == 0 {
-		for _, item := range salesBasket.Items {
-			salesBasket.Total += item.TotalAmount
-		}
+	// Prices and totals always come from the catalogue, never from the client
+	if !c.priceBasket(&salesBasket) {
+		return
+	}
+	
+	// A paid sale must be covered by its tenders; an open one is paid when it is completed
+	if salesBasket.Status == model.SalesStatusOpen {
+		salesBasket.Payments = nil
+	} else if !c.settlePayments(&salesBasket) {
+		return
 	}
 	
 	// Create transaction
@@ -64,6 +84,17 @@ func (c *SalesBasketController) Create() {
 		return
 	}
 	
+	// A paid sale goes on the cashier's shift and is numbered in the same transaction that saves it
+	if salesBasket.Status == model.SalesStatusCompleted {
+		var ok bool
+		if salesBasket.ShiftID, ok = c.currentShiftTx(tx); !ok || !c.numberInvoice(tx, &salesBasket) {
+			tx.Rollback()
+			return
+		}
+	} else {
+		salesBasket.ShiftID = 0
+	}
+	
 	// Save sales basket first
 	newSalesBasket, err := c.repo.CreateSalesBasketTx(tx, &salesBasket)
 	if err != nil {
@@ -72,6 +103,12 @@ func (c *SalesBasketController) Create() {
 		return
 	}
 	
+	// Record how the sale was paid, taking any points spent from the member
+	if !c.savePayments(tx, newSalesBasket) || !c.redeemPoints(tx, newSalesBasket) {
+		tx.Rollback()
+		return
+	}
+	
 	// Save each sales item with the new sales basket ID
 	var savedItems []model.SalesItem
 	for _, item := range salesBasket.Items {
@@ -82,9 +119,21 @@ func (c *SalesBasketController) Create() {
 			c.JSONResponse(http.StatusInternalServerError, "Failed to create sales item: "+err.Error(), nil)
 			return
 		}
+		if !c.savePromotions(tx, newItem) {
+			tx.Rollback()
+			return
+		}
 		savedItems = append(savedItems, *newItem)
 	}
 	
+	// Take the sold quantities out of the item batches and credit the member once the sale is paid
+	if salesBasket.Status == model.SalesStatusCompleted {
+		if !c.deductStock(tx, savedItems) || !c.awardPoints(tx, newSalesBasket, savedItems) {
+			tx.Rollback()
+			return
+		}
+	}
+	
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
@@ -95,7 +144,9 @@ func (c *SalesBasketController) Create() {
 	// Add the items to the response
 	newSalesBasket.Items = savedItems
 	
-	c.JSONResponse(http.StatusCreated, "Sales basket created successfully", newSalesBasket)
+	c.Audit("sales", newSalesBasket.ID, nil, dto.NewSalesBasketResponse(newSalesBasket))
+	
+	c.JSONResponse(http.StatusCreated, "Sales basket created successfully", dto.NewSalesBasketResponse(newSalesBasket))
 }
 
 // Get retrieves a sales basket by ID
@@ -120,9 +171,44 @@ func (c *SalesBasketController) Get() {
 		return
 	}
 	
+	// Attach the batches each line drew its stock from
+	allocations, err := c.itemRepo.GetSalesItemBatchesBySales(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve stock allocations: "+err.Error(), nil)
+		return
+	}
+	for _, allocation := range allocations {
+		for i := range items {
+			if items[i].ID == allocation.SalesItemID {
+				items[i].Batches = append(items[i].Batches, allocation)
+			}
+		}
+	}
+	
+	// Attach the promotions each line was discounted by
+	promotions, err := c.itemRepo.GetSalesItemPromotionsBySales(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve applied promotions: "+err.Error(), nil)
+		return
+	}
+	for _, promotion := range promotions {
+		for i := range items {
+			if items[i].ID == promotion.SalesItemID {
+				items[i].Promotions = append(items[i].Promotions, promotion)
+			}
+		}
+	}
+	
 	salesBasket.Items = items
 	
-	c.JSONResponse(http.StatusOK, "Sales basket retrieved successfully", salesBasket)
+	// Attach the tenders the sale was paid with
+	salesBasket.Payments, err = repository.NewSalesPaymentRepository().GetSalesPaymentsBySales(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve payments: "+err.Error(), nil)
+		return
+	}
+	
+	c.JSONResponse(http.StatusOK, "Sales basket retrieved successfully", dto.NewSalesBasketResponse(salesBasket))
 }
 
 // GetAll retrieves all sales baskets
@@ -131,11 +217,11 @@ func (c *SalesBasketController) GetAll() {
 	userIDStr := c.GetString("user_id")
 	memberIDStr := c.GetString("member_id")
 	
-	var userID, memberID int
+	var filter model.SalesBasketFilter
 	var err error
 	
 	if userIDStr != "" {
-		userID, err = strconv.Atoi(userIDStr)
+		filter.UserID, err = strconv.Atoi(userIDStr)
 		if err != nil {
 			c.JSONResponse(http.StatusBadRequest, "Invalid user ID format", nil)
 			return
@@ -143,31 +229,32 @@ func (c *SalesBasketController) GetAll() {
 	}
 	
 	if memberIDStr != "" {
-		memberID, err = strconv.Atoi(memberIDStr)
+		filter.MemberID, err = strconv.Atoi(memberIDStr)
 		if err != nil {
 			c.JSONResponse(http.StatusBadRequest, "Invalid member ID format", nil)
 			return
 		}
 	}
 	
-	var salesBaskets []model.SalesBasket
-	
-	if userIDStr != "" && memberIDStr != "" {
-		salesBaskets, err = c.repo.GetSalesBasketsByUserAndMember(userID, memberID)
-	} else if userIDStr != "" {
-		salesBaskets, err = c.repo.GetSalesBasketsByUser(userID)
-	} else if memberIDStr != "" {
-		salesBaskets, err = c.repo.GetSalesBasketsByMember(memberID)
-	} else {
-		salesBaskets, err = c.repo.GetAllSalesBaskets()
+	if status := c.GetString("status"); status != "" {
+		filter.Status = model.SalesStatus(status)
+		if !filter.Status.IsValid() {
+			c.JSONResponse(http.StatusBadRequest, "Invalid status filter", nil)
+			return
+		}
 	}
 	
+	// An invoice number, or the start of one such as a register's series for a day
+	filter.Invoice = c.GetString("invoice")
+	
+	salesBaskets, err := c.repo.GetSalesBaskets(filter)
+	
 	if err != nil {
 		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales baskets: "+err.Error(), nil)
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Sales baskets retrieved successfully", salesBaskets)
+	c.JSONResponse(http.StatusOK, "Sales baskets retrieved successfully", dto.NewSalesBasketResponses(salesBaskets))
 }
 
 // Update updates a sales basket
@@ -188,12 +275,26 @@ func (c *SalesBasketController) Update() {
 	salesBasket.ID = id
 	
 	// Check if sales basket exists
-	_, err = c.repo.GetSalesBasket(id)
+	existingSalesBasket, err := c.repo.GetSalesBasket(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
 		return
 	}
 	
+	// Completed sales are corrected with voids and returns, never edited in place
+	if !existingSalesBasket.Status.IsDraft() {
+		c.JSONResponse(http.StatusConflict, "Only open or held sales can be edited; void or return completed sales instead", nil)
+		return
+	}
+	
+	// The total always follows the stored lines, never the client
+	salesBasket.Total = existingSalesBasket.Total
+	
+	// Status, register and cashier only change through hold, resume, complete and void
+	salesBasket.Status = existingSalesBasket.Status
+	salesBasket.Register = existingSalesBasket.Register
+	salesBasket.UserID = existingSalesBasket.UserID
+	
 	// Update sales basket
 	updatedSalesBasket, err := c.repo.UpdateSalesBasket(&salesBasket)
 	if err != nil {
@@ -201,11 +302,17 @@ func (c *SalesBasketController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Sales basket updated successfully", updatedSalesBasket)
+	c.Audit("sales", id, dto.NewSalesBasketResponse(existingSalesBasket), dto.NewSalesBasketResponse(updatedSalesBasket))
+	
+	c.JSONResponse(http.StatusOK, "Sales basket updated successfully", dto.NewSalesBasketResponse(updatedSalesBasket))
 }
 
 // Delete deletes a sales basket
 func (c *SalesBasketController) Delete() {
+	if !c.RequirePermission(model.PermissionSalesVoid) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -214,12 +321,18 @@ func (c *SalesBasketController) Delete() {
 	}
 	
 	// Check if sales basket exists
-	_, err = c.repo.GetSalesBasket(id)
+	existingSalesBasket, err := c.repo.GetSalesBasket(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
 		return
 	}
 	
+	// Only unpaid baskets can be discarded; paid sales keep their history and are voided instead
+	if !existingSalesBasket.Status.IsDraft() {
+		c.JSONResponse(http.StatusConflict, "Only open or held sales can be deleted; void completed sales instead", nil)
+		return
+	}
+	
 	// Create transaction
 	tx, err := database.DB.Begin()
 	if err != nil {
@@ -250,5 +363,7 @@ func (c *SalesBasketController) Delete() {
 		return
 	}
 	
+	c.Audit("sales", id, dto.NewSalesBasketResponse(existingSalesBasket), nil)
+	
 	c.JSONResponse(http.StatusOK, "Sales basket deleted successfully", nil)
 }
\ No newline at end of file
@@ -0,0 +1,538 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-pos/config"
+	"go-pos/inventory"
+	"go-pos/model"
+	"go-pos/pricing"
+	"go-pos/repository"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// currentPrices looks up the catalogue price of every item on the given lines, keyed by item ID.
+// It writes the error response and returns false when an item does not exist.
+func (c *BaseController) currentPrices(lines []model.SalesItem) (map[int]int, bool) {
+	itemRepo := repository.NewItemRepository()
+	prices := make(map[int]int)
+
+	for _, line := range lines {
+		if _, ok := prices[line.ItemID]; ok {
+			continue
+		}
+
+		item, err := itemRepo.GetItem(line.ItemID)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, fmt.Sprintf("Item %d not found", line.ItemID), nil)
+			return nil, false
+		}
+		prices[item.ID] = item.Price
+	}
+
+	return prices, true
+}
+
+// currentOffer collects the promotions running now and, when any of them targets a category,
+// the category of every item on the given lines. It writes the error response and returns
+// false on failure.
+func (c *BaseController) currentOffer(lines []model.SalesItem) (*pricing.Offer, bool) {
+	promotions, err := repository.NewPromotionRepository().GetActivePromotions()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve promotions: "+err.Error(), nil)
+		return nil, false
+	}
+
+	offer := &pricing.Offer{Promotions: promotions, Categories: make(map[int]int), At: time.Now()}
+
+	var ok bool
+
+	for _, promotion := range promotions {
+		if promotion.Scope == model.PromotionScopeCategory {
+			offer.Categories, ok = c.itemCategories(lines)
+			return offer, ok
+		}
+	}
+
+	return offer, true
+}
+
+// itemCategories looks up the category of every item on the given lines, keyed by item ID.
+// It writes the error response and returns false when an item does not exist.
+func (c *BaseController) itemCategories(lines []model.SalesItem) (map[int]int, bool) {
+	itemRepo := repository.NewItemRepository()
+	categories := make(map[int]int)
+
+	for _, line := range lines {
+		if _, ok := categories[line.ItemID]; ok {
+			continue
+		}
+
+		item, err := itemRepo.GetItem(line.ItemID)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, fmt.Sprintf("Item %d not found", line.ItemID), nil)
+			return nil, false
+		}
+		categories[item.ID] = item.CategoryID
+	}
+
+	return categories, true
+}
+
+// currentTaxes looks up the tax rate of every item on the given lines, for prices in the given
+// mode. It writes the error response and returns false on failure.
+func (c *BaseController) currentTaxes(lines []model.SalesItem, mode model.TaxMode) (*pricing.Taxes, bool) {
+	itemIDs := make([]int, 0, len(lines))
+	for _, line := range lines {
+		itemIDs = append(itemIDs, line.ItemID)
+	}
+
+	rates, err := repository.NewTaxRateRepository().GetTaxRatesByItems(itemIDs)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve tax rates: "+err.Error(), nil)
+		return nil, false
+	}
+
+	return &pricing.Taxes{Mode: mode, Rates: rates}, true
+}
+
+// checkoutTaxMode returns whether catalogue prices include tax, as configured; an unknown
+// setting is taken as inclusive
+func checkoutTaxMode() model.TaxMode {
+	mode := model.TaxMode(strings.ToUpper(config.GetCheckoutPolicy().TaxMode))
+	if !mode.IsValid() {
+		return model.TaxInclusive
+	}
+	return mode
+}
+
+// priceBasket prices every line of a basket from the catalogue, applies the running promotions
+// and taxes, and sets the basket total and tax. It writes the error response and returns false
+// when an item is unknown or a client total does not match.
+func (c *SalesBasketController) priceBasket(basket *model.SalesBasket) bool {
+	prices, ok := c.currentPrices(basket.Items)
+	if !ok {
+		return false
+	}
+
+	offer, ok := c.currentOffer(basket.Items)
+	if !ok {
+		return false
+	}
+
+	taxes, ok := c.currentTaxes(basket.Items, checkoutTaxMode())
+	if !ok {
+		return false
+	}
+
+	if err := pricing.PriceBasket(basket, prices, offer, taxes, config.GetCheckoutPolicy().RejectClientTotals); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Price check failed: "+err.Error(), nil)
+		return false
+	}
+
+	return true
+}
+
+// repriceLinesTx applies the promotions running now and the current tax rates to the saved
+// lines of a sale, at the unit prices they were rung up at and in the sale's tax mode, and saves
+// their discounts, taxes and applied promotions within the transaction. It updates the total
+// and tax of the basket, or writes the error response and returns false on failure.
+func (c *BaseController) repriceLinesTx(tx *sql.Tx, basket *model.SalesBasket, lines []model.SalesItem) bool {
+	offer, ok := c.currentOffer(lines)
+	if !ok {
+		return false
+	}
+
+	if !basket.TaxMode.IsValid() {
+		basket.TaxMode = checkoutTaxMode()
+	}
+	taxes, ok := c.currentTaxes(lines, basket.TaxMode)
+	if !ok {
+		return false
+	}
+
+	pricing.ApplyPromotions(lines, offer)
+	basket.TaxAmount = pricing.ApplyTax(lines, taxes)
+
+	itemRepo := repository.NewSalesItemRepository()
+	if err := itemRepo.DeleteSalesItemPromotionsBySalesTx(tx, basket.ID); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to clear applied promotions: "+err.Error(), nil)
+		return false
+	}
+
+	basket.Total = 0
+	for i := range lines {
+		if err := itemRepo.UpdateSalesItemPricingTx(tx, &lines[i]); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to update sales item: "+err.Error(), nil)
+			return false
+		}
+		if !c.savePromotions(tx, &lines[i]) {
+			return false
+		}
+		basket.Total += lines[i].TotalAmount
+	}
+
+	if err := repository.NewSalesBasketRepository().RecalculateTotalTx(tx, basket.ID); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update sales total: "+err.Error(), nil)
+		return false
+	}
+
+	return true
+}
+
+// savePromotions records the promotions applied to a saved sales line within the transaction.
+// It writes the error response and returns false on failure.
+func (c *BaseController) savePromotions(tx *sql.Tx, line *model.SalesItem) bool {
+	itemRepo := repository.NewSalesItemRepository()
+
+	for i := range line.Promotions {
+		line.Promotions[i].SalesItemID = line.ID
+
+		if err := itemRepo.CreateSalesItemPromotionTx(tx, &line.Promotions[i]); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to record applied promotion: "+err.Error(), nil)
+			return false
+		}
+	}
+
+	return true
+}
+
+// settlePayments checks that the tenders on a basket cover its priced total and works out the
+// change; the basket's payment method becomes the tender that paid the most. Points tenders are
+// valued under the configured redemption rule and need a member. Without tenders the whole total
+// is taken as paid with the basket's payment method. It writes the error response and returns
+// false when the payments do not settle the sale.
+func (c *BaseController) settlePayments(basket *model.SalesBasket) bool {
+	if len(basket.Payments) == 0 {
+		if basket.Total == 0 {
+			return true
+		}
+		if !basket.PaymentMethod.IsValid() {
+			c.JSONResponse(http.StatusBadRequest, "Payment is required to complete the sale", nil)
+			return false
+		}
+		if basket.PaymentMethod == model.PaymentMethodPoints {
+			c.JSONResponse(http.StatusBadRequest, "Points must be given as a payment with the number of points to spend", nil)
+			return false
+		}
+		basket.Payments = []model.SalesPayment{{Method: basket.PaymentMethod, Amount: basket.Total, Tendered: basket.Total}}
+		return true
+	}
+
+	policy := config.GetPointsPolicy()
+	rule := pricing.RedemptionRule{
+		RupiahPerPoint: policy.RedeemRupiahPerPoint,
+		MinimumPoints:  policy.RedeemMinimum,
+		MaxShare:       policy.RedeemMaxShare,
+	}
+	points, err := pricing.RedeemPoints(basket.Total, basket.Payments, rule)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Payment rejected: "+err.Error(), nil)
+		return false
+	}
+	if points > 0 && basket.MemberID == 0 {
+		c.JSONResponse(http.StatusBadRequest, "Payment rejected: only members can pay with points", nil)
+		return false
+	}
+
+	if err := pricing.SettleTenders(basket.Total, basket.Payments); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Payment rejected: "+err.Error(), nil)
+		return false
+	}
+
+	basket.PaymentMethod = pricing.PrimaryMethod(basket.Payments)
+	return true
+}
+
+// savePayments records the settled tenders of a basket within the checkout transaction.
+// It writes the error response and returns false on failure.
+func (c *BaseController) savePayments(tx *sql.Tx, basket *model.SalesBasket) bool {
+	paymentRepo := repository.NewSalesPaymentRepository()
+	now := time.Now()
+
+	for i := range basket.Payments {
+		basket.Payments[i].SalesID = basket.ID
+		basket.Payments[i].CreatedAt = now
+
+		if _, err := paymentRepo.CreateSalesPaymentTx(tx, &basket.Payments[i]); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to record payment: "+err.Error(), nil)
+			return false
+		}
+	}
+
+	return true
+}
+
+// redeemPoints takes the points a basket's points tenders spend from its member within the
+// checkout transaction, recording them as redeemed against the sale. It writes the error
+// response and returns false when the member does not hold enough points or on failure.
+func (c *BaseController) redeemPoints(tx *sql.Tx, basket *model.SalesBasket) bool {
+	points := 0
+	for _, payment := range basket.Payments {
+		if payment.Method == model.PaymentMethodPoints {
+			points += payment.Points
+		}
+	}
+	if points == 0 {
+		return true
+	}
+
+	// Lock the balance so a concurrent sale cannot spend the same points
+	memberRepo := repository.NewMemberRepository()
+	balance, err := memberRepo.GetPointsForUpdateTx(tx, basket.MemberID)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Member not found", nil)
+		return false
+	}
+	if balance < points {
+		c.JSONResponse(http.StatusBadRequest, fmt.Sprintf("Member does not have sufficient points to redeem: %d held, %d given", balance, points), nil)
+		return false
+	}
+
+	memberPoint := &model.MemberPoint{
+		MemberID: basket.MemberID,
+		SalesID:  basket.ID,
+		Type:     model.PointTypeRedeemed,
+		Points:   points,
+	}
+	if _, err := repository.NewMemberPointRepository().CreateMemberPointTx(tx, memberPoint); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to record redeemed points: "+err.Error(), nil)
+		return false
+	}
+
+	if err := memberRepo.AdjustPointsTx(tx, basket.MemberID, -points); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
+		return false
+	}
+
+	return true
+}
+
+// currentShiftTx returns the ID of the open shift of the current user, whose drawer a payment
+// or refund goes through, and keeps the shift from being closed until the transaction ends. It is
+// 0 when the user has no open shift and the checkout policy does not require one. It writes
+// the error response and returns false when a required shift is missing or on failure.
+func (c *BaseController) currentShiftTx(tx *sql.Tx) (int, bool) {
+	shift, err := repository.NewShiftRepository().GetOpenShiftByUserForShareTx(tx, c.CurrentUser().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve open shift: "+err.Error(), nil)
+		return 0, false
+	}
+
+	if shift == nil {
+		if config.GetCheckoutPolicy().RequireShift {
+			c.JSONResponse(http.StatusConflict, "Open a shift before taking or refunding payments", nil)
+			return 0, false
+		}
+		return 0, true
+	}
+
+	return shift.ID, true
+}
+
+// numberInvoice gives a sale being completed the next invoice number of its register's series
+// for the day, within the checkout transaction, so the number is only used once the sale is.
+// It writes the error response and returns false on failure.
+func (c *BaseController) numberInvoice(tx *sql.Tx, basket *model.SalesBasket) bool {
+	policy := config.GetInvoicePolicy()
+	day := time.Now()
+
+	seq, err := repository.NewInvoiceSequenceRepository().NextNumberTx(tx, policy.Series(basket.Register, day))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to number invoice: "+err.Error(), nil)
+		return false
+	}
+
+	basket.InvoiceNo = policy.Number(basket.Register, day, seq)
+	return true
+}
+
+// priceSalesItem prices a single sales line at the given unit price.
+// It writes the error response and returns false when the client total does not match.
+func (c *BaseController) priceSalesItem(item *model.SalesItem, unitPrice int) bool {
+	if err := pricing.PriceItem(item, unitPrice, config.GetCheckoutPolicy().RejectClientTotals); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Price check failed: "+err.Error(), nil)
+		return false
+	}
+
+	return true
+}
+
+// deductStock draws the quantity of every saved sales line from the item's batches within the
+// checkout transaction and records the allocations on the lines. It writes the error response
+// and returns false on failure; the caller rolls the transaction back.
+func (c *SalesBasketController) deductStock(tx *sql.Tx, lines []model.SalesItem) bool {
+	policy := config.GetCheckoutPolicy()
+	batchRepo := repository.NewItemBatchRepository()
+
+	// Lock batches in item order so concurrent sales cannot deadlock each other
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lines[order[a]].ItemID < lines[order[b]].ItemID
+	})
+
+	for _, i := range order {
+		line := &lines[i]
+
+		batches, err := batchRepo.GetItemBatchesForUpdateTx(tx, line.ItemID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to read item stock: "+err.Error(), nil)
+			return false
+		}
+
+		inventory.SortBatches(batches, inventory.Strategy(policy.StockStrategy))
+		allocations, err := inventory.Allocate(line.ItemID, batches, line.Qty, policy.AllowNegativeStock)
+		if err != nil {
+			var stockErr *inventory.InsufficientStockError
+			if errors.As(err, &stockErr) {
+				c.JSONResponse(http.StatusConflict, "Insufficient stock: "+err.Error(), nil)
+				return false
+			}
+			c.JSONResponse(http.StatusInternalServerError, "Failed to allocate stock: "+err.Error(), nil)
+			return false
+		}
+
+		for j := range allocations {
+			allocations[j].SalesItemID = line.ID
+
+			if err := batchRepo.AdjustQtyTx(tx, allocations[j].BatchID, -allocations[j].Qty); err != nil {
+				c.JSONResponse(http.StatusInternalServerError, "Failed to deduct stock: "+err.Error(), nil)
+				return false
+			}
+			if err := c.itemRepo.CreateSalesItemBatchTx(tx, &allocations[j]); err != nil {
+				c.JSONResponse(http.StatusInternalServerError, "Failed to record stock allocation: "+err.Error(), nil)
+				return false
+			}
+		}
+		line.Batches = allocations
+	}
+
+	return true
+}
+
+// allowLineChanges refuses to add, change or delete the lines of a sale unless it is open;
+// held sales must be resumed first, and paid sales are corrected with voids and returns.
+// It writes the error response and returns false when the lines must not change.
+func (c *BaseController) allowLineChanges(salesID int) bool {
+	basket, err := repository.NewSalesBasketRepository().GetSalesBasket(salesID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return false
+	}
+
+	if basket.Status != model.SalesStatusOpen {
+		c.JSONResponse(http.StatusConflict, fmt.Sprintf("Lines of a %s sale cannot change", basket.Status), nil)
+		return false
+	}
+
+	return true
+}
+
+// awardPoints credits the member of a completed sale with the points its lines earn under the
+// configured rule, within the checkout transaction, and records them on the basket. Sales
+// without a member earn nothing, nor does the part of a sale paid with points.
+// It writes the error response and returns false on failure.
+func (c *BaseController) awardPoints(tx *sql.Tx, basket *model.SalesBasket, lines []model.SalesItem) bool {
+	if basket.MemberID == 0 {
+		return true
+	}
+
+	policy := config.GetPointsPolicy()
+	rule := pricing.EarningRule{
+		RupiahPerPoint:      policy.RupiahPerPoint,
+		CategoryMultipliers: policy.CategoryMultipliers,
+		ExcludedItems:       policy.ExcludedItems,
+	}
+	for _, payment := range basket.Payments {
+		if payment.Method == model.PaymentMethodPoints {
+			rule.PointsPaid += payment.Amount
+		}
+	}
+	if len(policy.CategoryMultipliers) > 0 {
+		var ok bool
+		if rule.Categories, ok = c.itemCategories(lines); !ok {
+			return false
+		}
+	}
+
+	points := pricing.EarnPoints(lines, rule)
+	if points <= 0 {
+		return true
+	}
+
+	// Lock the balance so concurrent point changes to the member add up
+	memberRepo := repository.NewMemberRepository()
+	if _, err := memberRepo.GetPointsForUpdateTx(tx, basket.MemberID); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Member not found", nil)
+		return false
+	}
+
+	memberPoint := &model.MemberPoint{
+		MemberID: basket.MemberID,
+		SalesID:  basket.ID,
+		Type:     model.PointTypeEarned,
+		Points:   points,
+	}
+	if _, err := repository.NewMemberPointRepository().CreateMemberPointTx(tx, memberPoint); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to record earned points: "+err.Error(), nil)
+		return false
+	}
+
+	if err := memberRepo.AdjustPointsTx(tx, basket.MemberID, points); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
+		return false
+	}
+
+	basket.PointsEarned = points
+	return true
+}
+
+// reverseSalePoints gives a member back the points they spent on a sale and takes back up to
+// earned of the points it earned, for a void or refund. Spent points come back first, so earned
+// points the member already spent are taken from them; only what the balance still cannot cover
+// is left with the member. It returns the points reversed, or writes the error response and
+// returns false on failure.
+func (c *BaseController) reverseSalePoints(tx *sql.Tx, memberID, salesID, spent, earned int) (int, bool) {
+	if spent <= 0 && earned <= 0 {
+		return 0, true
+	}
+
+	memberRepo := repository.NewMemberRepository()
+	balance, err := memberRepo.GetPointsForUpdateTx(tx, memberID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member points: "+err.Error(), nil)
+		return 0, false
+	}
+
+	reversed, _ := pricing.ReverseSalePoints(balance, spent, earned)
+
+	pointRepo := repository.NewMemberPointRepository()
+	if spent > 0 {
+		restored := &model.MemberPoint{MemberID: memberID, SalesID: salesID, Type: model.PointTypeRestored, Points: spent}
+		if _, err := pointRepo.CreateMemberPointTx(tx, restored); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to record restored points: "+err.Error(), nil)
+			return 0, false
+		}
+	}
+	if reversed > 0 {
+		reversal := &model.MemberPoint{MemberID: memberID, SalesID: salesID, Type: model.PointTypeReversed, Points: reversed}
+		if _, err := pointRepo.CreateMemberPointTx(tx, reversal); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to record point reversal: "+err.Error(), nil)
+			return 0, false
+		}
+	}
+
+	if err := memberRepo.AdjustPointsTx(tx, memberID, spent-reversed); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update member points: "+err.Error(), nil)
+		return 0, false
+	}
+
+	return reversed, true
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/database"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -45,6 +47,20 @@ func (c *SalesItemController) Create() {
 		return
 	}
 	
+	// Lines can only be added while the sale is open
+	if !c.allowLineChanges(salesItem.SalesID) {
+		return
+	}
+	
+	// Price the line from the catalogue; promotions are applied across the basket once it is saved
+	prices, ok := c.currentPrices([]model.SalesItem{salesItem})
+	if !ok {
+		return
+	}
+	if !c.priceSalesItem(&salesItem, prices[salesItem.ItemID]) {
+		return
+	}
+	
 	// Save the sales item to database
 	newSalesItem, err := c.repo.CreateSalesItem(&salesItem)
 	if err != nil {
@@ -52,7 +68,19 @@ func (c *SalesItemController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "Sales item created successfully", newSalesItem)
+	// Keep the basket total in line with its items
+	if !c.recalculateTotals(newSalesItem.SalesID) {
+		return
+	}
+	
+	// Promotions may have discounted the new line
+	if repriced, err := c.repo.GetSalesItem(newSalesItem.ID); err == nil {
+		newSalesItem = repriced
+	}
+	
+	c.Audit("sales_item", newSalesItem.ID, nil, dto.NewSalesItemResponse(newSalesItem))
+	
+	c.JSONResponse(http.StatusCreated, "Sales item created successfully", dto.NewSalesItemResponse(newSalesItem))
 }
 
 // Get retrieves a sales item by ID
@@ -70,7 +98,7 @@ func (c *SalesItemController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Sales item retrieved successfully", salesItem)
+	c.JSONResponse(http.StatusOK, "Sales item retrieved successfully", dto.NewSalesItemResponse(salesItem))
 }
 
 // GetAll retrieves all sales items
@@ -98,7 +126,7 @@ func (c *SalesItemController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Sales items retrieved successfully", salesItems)
+	c.JSONResponse(http.StatusOK, "Sales items retrieved successfully", dto.NewSalesItemResponses(salesItems))
 }
 
 // Update updates a sales item
@@ -119,12 +147,37 @@ func (c *SalesItemController) Update() {
 	salesItem.ID = id
 	
 	// Check if sales item exists
-	_, err = c.repo.GetSalesItem(id)
+	existingSalesItem, err := c.repo.GetSalesItem(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Sales item not found", nil)
 		return
 	}
 	
+	// Lines only change while their sale is open, including the sale a line is moved to
+	if !c.allowLineChanges(existingSalesItem.SalesID) {
+		return
+	}
+	if salesItem.SalesID != existingSalesItem.SalesID && !c.allowLineChanges(salesItem.SalesID) {
+		return
+	}
+	
+	// Keep the price the line was sold at unless the item itself changes
+	unitPrice := existingSalesItem.UnitPrice
+	if salesItem.ItemID != existingSalesItem.ItemID {
+		prices, ok := c.currentPrices([]model.SalesItem{salesItem})
+		if !ok {
+			return
+		}
+		unitPrice = prices[salesItem.ItemID]
+	}
+	// A client echoing the stored line sends back its total after promotions and tax, which are reapplied below
+	if salesItem.TotalAmount == existingSalesItem.TotalAmount {
+		salesItem.TotalAmount = 0
+	}
+	if !c.priceSalesItem(&salesItem, unitPrice) {
+		return
+	}
+	
 	// Update sales item
 	updatedSalesItem, err := c.repo.UpdateSalesItem(&salesItem)
 	if err != nil {
@@ -132,7 +185,19 @@ func (c *SalesItemController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "Sales item updated successfully", updatedSalesItem)
+	// Keep the basket totals in line with their items
+	if !c.recalculateTotals(existingSalesItem.SalesID, updatedSalesItem.SalesID) {
+		return
+	}
+	
+	// Promotions may have discounted the changed line
+	if repriced, err := c.repo.GetSalesItem(id); err == nil {
+		updatedSalesItem = repriced
+	}
+	
+	c.Audit("sales_item", id, dto.NewSalesItemResponse(existingSalesItem), dto.NewSalesItemResponse(updatedSalesItem))
+	
+	c.JSONResponse(http.StatusOK, "Sales item updated successfully", dto.NewSalesItemResponse(updatedSalesItem))
 }
 
 // Delete deletes a sales item
@@ -145,12 +210,17 @@ func (c *SalesItemController) Delete() {
 	}
 	
 	// Check if sales item exists
-	_, err = c.repo.GetSalesItem(id)
+	existingSalesItem, err := c.repo.GetSalesItem(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "Sales item not found", nil)
 		return
 	}
 	
+	// Lines only change while their sale is open
+	if !c.allowLineChanges(existingSalesItem.SalesID) {
+		return
+	}
+	
 	// Delete sales item
 	err = c.repo.DeleteSalesItem(id)
 	if err != nil {
@@ -158,5 +228,53 @@ func (c *SalesItemController) Delete() {
 		return
 	}
 	
+	// Keep the basket total in line with its items
+	if !c.recalculateTotals(existingSalesItem.SalesID) {
+		return
+	}
+	
+	c.Audit("sales_item", id, dto.NewSalesItemResponse(existingSalesItem), nil)
+	
 	c.JSONResponse(http.StatusOK, "Sales item deleted successfully", nil)
 }
+
+// recalculateTotals reapplies the running promotions and current taxes to every given sales
+// basket and updates its total and tax from its items.
+// It writes the error response and returns false on failure.
+func (c *SalesItemController) recalculateTotals(salesIDs ...int) bool {
+	basketRepo := repository.NewSalesBasketRepository()
+	for _, salesID := range salesIDs {
+		tx, err := database.DB.Begin()
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+			return false
+		}
+		
+		// Lock the basket so concurrent line changes are repriced one after the other
+		basket, err := basketRepo.GetSalesBasketForUpdateTx(tx, salesID)
+		if err != nil {
+			tx.Rollback()
+			c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+			return false
+		}
+		
+		lines, err := c.repo.GetSalesItemsBySales(salesID)
+		if err != nil {
+			tx.Rollback()
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales items: "+err.Error(), nil)
+			return false
+		}
+		
+		if !c.repriceLinesTx(tx, basket, lines) {
+			tx.Rollback()
+			return false
+		}
+		
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+			return false
+		}
+	}
+	return true
+}
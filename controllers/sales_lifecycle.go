@@ -0,0 +1,369 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-pos/config"
+	"go-pos/database"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ResumeRequest names the register a held basket is picked up on
+type ResumeRequest struct {
+	Register string `json:"register"`
+}
+
+// CompleteRequest lists the tenders an open basket is paid with. Without tenders the whole
+// total is taken as paid with the basket's payment method.
+type CompleteRequest struct {
+	PaymentMethod model.PaymentMethod  `json:"payment_method"`
+	Payments      []model.SalesPayment `json:"payments"`
+}
+
+// VoidRequest is the body of a void. A cashier without the sales.void permission needs a
+// supervisor to approve the void by entering their NIK and password, and their TOTP code or a
+// recovery code when they enrolled in two-factor login.
+type VoidRequest struct {
+	Reason               string `json:"reason"`
+	ApproverNIK          int    `json:"approver_nik"`
+	ApproverPassword     string `json:"approver_password"`
+	ApproverCode         string `json:"approver_code"`
+	ApproverRecoveryCode string `json:"approver_recovery_code"`
+}
+
+// Hold parks an open basket so it can be resumed later, possibly on another register
+func (c *SalesBasketController) Hold() {
+	c.changeStatus("HOLD", "held", []model.SalesStatus{model.SalesStatusOpen}, func(tx *sql.Tx, basket *model.SalesBasket) bool {
+		basket.Status = model.SalesStatusHeld
+		return true
+	})
+}
+
+// Resume reopens a held basket on the calling register; the resuming cashier takes it over
+func (c *SalesBasketController) Resume() {
+	var request ResumeRequest
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+			return
+		}
+	}
+
+	c.changeStatus("RESUME", "resumed", []model.SalesStatus{model.SalesStatusHeld}, func(tx *sql.Tx, basket *model.SalesBasket) bool {
+		basket.Status = model.SalesStatusOpen
+		basket.UserID = c.CurrentUser().ID
+		if request.Register != "" {
+			basket.Register = request.Register
+		}
+		return true
+	})
+}
+
+// Complete closes an open basket once its tenders cover the total, after the promotions running
+// now and the current taxes are applied, takes the points spent on it and its quantities out of
+// stock, credits the member with the points it earns and gives it its invoice number
+func (c *SalesBasketController) Complete() {
+	if !c.Idempotent() {
+		return
+	}
+
+	var request CompleteRequest
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+			return
+		}
+	}
+
+	c.changeStatus("COMPLETE", "completed", []model.SalesStatus{model.SalesStatusOpen}, func(tx *sql.Tx, basket *model.SalesBasket) bool {
+		lines, err := c.itemRepo.GetSalesItemsBySales(basket.ID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales items: "+err.Error(), nil)
+			return false
+		}
+		if len(lines) == 0 {
+			c.JSONResponse(http.StatusBadRequest, "At least one item is required", nil)
+			return false
+		}
+
+		// The payment goes into the drawer of the completing cashier's shift
+		var ok bool
+		if basket.ShiftID, ok = c.currentShiftTx(tx); !ok {
+			return false
+		}
+
+		// Promotions and taxes are settled at the moment of payment
+		if !c.repriceLinesTx(tx, basket, lines) {
+			return false
+		}
+
+		if request.PaymentMethod != "" {
+			basket.PaymentMethod = request.PaymentMethod
+		}
+		basket.Payments = request.Payments
+		if !c.settlePayments(basket) || !c.savePayments(tx, basket) || !c.redeemPoints(tx, basket) {
+			return false
+		}
+
+		if !c.deductStock(tx, lines) || !c.awardPoints(tx, basket, lines) || !c.numberInvoice(tx, basket) {
+			return false
+		}
+
+		basket.Status = model.SalesStatusCompleted
+		basket.SalesDate = int(time.Now().Unix())
+		basket.Items = lines
+		return true
+	})
+}
+
+// Void cancels a sale with a reason and supervisor approval. A paid sale gets its stock back,
+// hands its money back through the voiding cashier's shift, gives back the points spent on it
+// and loses the member points it earned; sales with returns can only be returned further.
+func (c *SalesBasketController) Void() {
+	var request VoidRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if request.Reason == "" {
+		c.JSONResponse(http.StatusBadRequest, "Void reason is required", nil)
+		return
+	}
+
+	approverID, ok := c.approveVoid(request)
+	if !ok {
+		return
+	}
+
+	from := []model.SalesStatus{model.SalesStatusOpen, model.SalesStatusHeld, model.SalesStatusCompleted}
+	c.changeStatus("VOID", "voided", from, func(tx *sql.Tx, basket *model.SalesBasket) bool {
+		if basket.Status == model.SalesStatusCompleted && !c.reverseCompletedSale(tx, basket) {
+			return false
+		}
+
+		basket.Status = model.SalesStatusVoided
+		basket.VoidReason = request.Reason
+		basket.VoidedBy = c.CurrentUser().ID
+		basket.VoidApprovedBy = approverID
+		basket.VoidedAt = time.Now()
+		return true
+	})
+}
+
+// changeStatus locks the sale named by the :id parameter, checks it is in one of the from
+// statuses and lets apply move it on within the same transaction before saving it
+func (c *SalesBasketController) changeStatus(action string, done string, from []model.SalesStatus, apply func(tx *sql.Tx, basket *model.SalesBasket) bool) {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
+	}
+
+	basket, err := c.repo.GetSalesBasketForUpdateTx(tx, id)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return
+	}
+
+	allowed := false
+	for _, status := range from {
+		if basket.Status == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, fmt.Sprintf("A %s sale cannot be %s", basket.Status, done), nil)
+		return
+	}
+
+	before := dto.NewSalesBasketResponse(basket)
+
+	if !apply(tx, basket) {
+		tx.Rollback()
+		return
+	}
+
+	if err := c.repo.UpdateStatusTx(tx, basket); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update sales status: "+err.Error(), nil)
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs(action, "sales", id, before, dto.NewSalesBasketResponse(basket))
+
+	c.JSONResponse(http.StatusOK, "Sales basket "+done+" successfully", dto.NewSalesBasketResponse(basket))
+}
+
+// reverseCompletedSale puts the stock of a paid sale back into its batches, records the money
+// handed back, gives back the points spent on it and takes back the member points it earned.
+// It writes the error response and returns false on failure.
+func (c *SalesBasketController) reverseCompletedSale(tx *sql.Tx, basket *model.SalesBasket) bool {
+	hasReturns, err := repository.NewSalesReturnRepository().SalesHasReturns(basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check sales returns: "+err.Error(), nil)
+		return false
+	}
+	if hasReturns {
+		c.JSONResponse(http.StatusConflict, "Sale has returns; return the remaining items instead", nil)
+		return false
+	}
+
+	if err := c.itemRepo.RestoreStockBySalesTx(tx, basket.ID); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to restore stock: "+err.Error(), nil)
+		return false
+	}
+
+	if !c.refundVoidTx(tx, basket) {
+		return false
+	}
+
+	if basket.MemberID == 0 {
+		return true
+	}
+
+	earned, err := repository.NewMemberPointRepository().GetEarnedPointsBySalesTx(tx, basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve earned points: "+err.Error(), nil)
+		return false
+	}
+
+	spent, _, err := repository.NewSalesPaymentRepository().GetPointsPaidBySalesTx(tx, basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve spent points: "+err.Error(), nil)
+		return false
+	}
+
+	_, ok := c.reverseSalePoints(tx, basket.MemberID, basket.ID, spent, earned)
+	return ok
+}
+
+// refundVoidTx records the money a paid sale being voided hands back, one row per tender other
+// than points, against the voiding cashier's shift. The sale keeps counting in the takings of
+// the shift that was paid, so a closed shift's report does not change.
+// It writes the error response and returns false on failure.
+func (c *SalesBasketController) refundVoidTx(tx *sql.Tx, basket *model.SalesBasket) bool {
+	shiftID, ok := c.currentShiftTx(tx)
+	if !ok {
+		return false
+	}
+
+	paymentRepo := repository.NewSalesPaymentRepository()
+	payments, err := paymentRepo.GetSalesPaymentsBySalesTx(tx, basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales payments: "+err.Error(), nil)
+		return false
+	}
+
+	for _, payment := range payments {
+		if payment.Method == model.PaymentMethodPoints || payment.Amount == 0 {
+			continue
+		}
+
+		refund := &model.SalesVoidPayment{SalesID: basket.ID, ShiftID: shiftID, Method: payment.Method, Amount: payment.Amount}
+		if _, err := paymentRepo.CreateSalesVoidPaymentTx(tx, refund); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to save void refund: "+err.Error(), nil)
+			return false
+		}
+	}
+	return true
+}
+
+// approveVoid returns the ID of the user approving a void: the current user when they hold
+// the sales.void permission, otherwise the supervisor whose credentials are in the request.
+// Wrong supervisor passwords and second-factor codes count towards the supervisor's login lockout.
+// It writes the error response and returns false when the void is not approved.
+func (c *SalesBasketController) approveVoid(request VoidRequest) (int, bool) {
+	if request.ApproverNIK == 0 {
+		if !c.HasPermission(model.PermissionSalesVoid) {
+			c.JSONResponse(http.StatusForbidden, "Supervisor approval required: missing permission "+string(model.PermissionSalesVoid), nil)
+			return 0, false
+		}
+		return c.CurrentUser().ID, true
+	}
+
+	policy := config.GetLoginPolicy()
+	nikAttempt, ipAttempt, ok := c.checkLoginAttempts(policy, request.ApproverNIK)
+	if !ok {
+		return 0, false
+	}
+
+	approver, err := repository.NewUserRepository().GetUserByNIK(request.ApproverNIK)
+	if err != nil {
+		c.recordLoginFailure(policy, nil, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusForbidden, "Supervisor approval rejected", nil)
+		return 0, false
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(approver.PasswordHash), []byte(request.ApproverPassword))
+	if err != nil {
+		c.recordLoginFailure(policy, approver, nikAttempt, ipAttempt)
+		c.JSONResponse(http.StatusForbidden, "Supervisor approval rejected", nil)
+		return 0, false
+	}
+
+	// An enrolled supervisor approves with their second factor as well, as when logging in
+	totpEnabled, err := repository.NewUserTOTPRepository().IsTOTPEnabled(approver.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check two-factor enrollment", nil)
+		return 0, false
+	}
+
+	if totpEnabled {
+		if request.ApproverCode == "" && request.ApproverRecoveryCode == "" {
+			c.JSONResponse(http.StatusForbidden, "Supervisor two-factor code required", nil)
+			return 0, false
+		}
+
+		verified, err := verifySecondFactor(approver.ID, request.ApproverCode, request.ApproverRecoveryCode)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to verify two-factor code", nil)
+			return 0, false
+		}
+		if !verified {
+			c.recordLoginFailure(policy, approver, nikAttempt, ipAttempt)
+			c.JSONResponse(http.StatusForbidden, "Supervisor approval rejected", nil)
+			return 0, false
+		}
+	}
+
+	repository.NewLoginAttemptRepository().DeleteLoginAttempt(nikAttempt.Key)
+
+	if !approver.IsAdmin {
+		granted, err := repository.NewRoleRepository().UserHasPermission(approver.ID, model.PermissionSalesVoid)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to check approver permissions: "+err.Error(), nil)
+			return 0, false
+		}
+		if !granted {
+			c.JSONResponse(http.StatusForbidden, "Approver lacks permission "+string(model.PermissionSalesVoid), nil)
+			return 0, false
+		}
+	}
+
+	return approver.ID, true
+}
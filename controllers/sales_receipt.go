@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"fmt"
+	"go-pos/config"
+	"go-pos/database"
+	"go-pos/model"
+	"go-pos/receipt"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Receipt renders the receipt of a paid sale as ?format=text (the default), escpos or pdf, for
+// ?width=58 or 80 (the default) millimetre paper. The first print is the original; every later
+// print is flagged as a copy.
+func (c *SalesBasketController) Receipt() {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	format := model.ReceiptFormat(c.GetString("format", string(model.ReceiptFormatText)))
+	if !format.IsValid() {
+		c.JSONResponse(http.StatusBadRequest, "Format must be text, escpos or pdf", nil)
+		return
+	}
+
+	columns := receipt.Columns80mm
+	switch c.GetString("width", "80") {
+	case "80":
+	case "58":
+		columns = receipt.Columns58mm
+	default:
+		c.JSONResponse(http.StatusBadRequest, "Width must be 58 or 80", nil)
+		return
+	}
+
+	basket, err := c.repo.GetSalesBasket(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return
+	}
+
+	if basket.Status != model.SalesStatusCompleted && basket.Status != model.SalesStatusRefunded {
+		c.JSONResponse(http.StatusConflict, fmt.Sprintf("A %s sale has no receipt", basket.Status), nil)
+		return
+	}
+
+	r, ok := c.buildReceipt(basket)
+	if !ok {
+		return
+	}
+
+	r.Print, ok = c.recordPrint(basket.ID, format)
+	if !ok {
+		return
+	}
+	r.Copy = r.Print > 1
+
+	var body []byte
+	switch format {
+	case model.ReceiptFormatESCPOS:
+		body = receipt.ESCPOS(r, columns)
+		c.Ctx.Output.Header("Content-Type", "application/octet-stream")
+	case model.ReceiptFormatPDF:
+		body = receipt.PDF(r, columns)
+		c.Ctx.Output.Header("Content-Type", "application/pdf")
+		c.Ctx.Output.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"receipt-%d.pdf\"", basket.ID))
+	default:
+		body = []byte(receipt.Text(r, columns))
+		c.Ctx.Output.Header("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	c.EnableRender = false
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Ctx.Output.Body(body)
+}
+
+// buildReceipt loads everything printed on the receipt of a sale: its lines with item names,
+// its payments, the cashier and the member's points. It writes the error response and returns
+// false on failure.
+func (c *SalesBasketController) buildReceipt(basket *model.SalesBasket) (*receipt.Receipt, bool) {
+	items, err := c.itemRepo.GetSalesItemsBySales(basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales items: "+err.Error(), nil)
+		return nil, false
+	}
+	basket.Items = items
+
+	basket.Payments, err = repository.NewSalesPaymentRepository().GetSalesPaymentsBySales(basket.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve payments: "+err.Error(), nil)
+		return nil, false
+	}
+
+	// Items deleted from the catalogue since are printed by ID
+	itemRepo := repository.NewItemRepository()
+	names := make(map[int]string)
+	for _, line := range items {
+		if _, ok := names[line.ItemID]; ok {
+			continue
+		}
+		if item, err := itemRepo.GetItem(line.ItemID); err == nil {
+			names[item.ID] = item.Name
+		}
+	}
+
+	cashier := ""
+	if user, err := repository.NewUserRepository().GetUser(basket.UserID); err == nil {
+		cashier = user.Name
+	}
+
+	var member *model.Member
+	earned := 0
+	if basket.MemberID != 0 {
+		member, err = repository.NewMemberRepository().GetMember(basket.MemberID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve member: "+err.Error(), nil)
+			return nil, false
+		}
+
+		earned, err = repository.NewMemberPointRepository().GetEarnedPointsBySales(basket.ID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve earned points: "+err.Error(), nil)
+			return nil, false
+		}
+	}
+
+	settings := config.GetReceiptConfig()
+	store := receipt.Store{
+		Name:    settings.StoreName,
+		Address: settings.Address,
+		Phone:   settings.Phone,
+		TaxID:   settings.TaxID,
+		Footer:  settings.Footer,
+	}
+
+	return receipt.New(store, basket, names, cashier, member, earned), true
+}
+
+// recordPrint records a print of a sale's receipt and returns how many prints the sale now has,
+// counting this one. The sale is locked so two first prints cannot both be originals.
+// It writes the error response and returns false on failure.
+func (c *SalesBasketController) recordPrint(salesID int, format model.ReceiptFormat) (int, bool) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return 0, false
+	}
+
+	if _, err := c.repo.GetSalesBasketForUpdateTx(tx, salesID); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return 0, false
+	}
+
+	printRepo := repository.NewReceiptPrintRepository()
+	printed, err := printRepo.CountReceiptPrintsTx(tx, salesID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to count receipt prints: "+err.Error(), nil)
+		return 0, false
+	}
+
+	receiptPrint := &model.ReceiptPrint{
+		SalesID:   salesID,
+		UserID:    c.CurrentUser().ID,
+		Format:    format,
+		IsCopy:    printed > 0,
+		PrintedAt: time.Now(),
+	}
+	if _, err := printRepo.CreateReceiptPrintTx(tx, receiptPrint); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to record receipt print: "+err.Error(), nil)
+		return 0, false
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return 0, false
+	}
+
+	return printed + 1, true
+}
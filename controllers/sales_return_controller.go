@@ -0,0 +1,414 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-pos/database"
+	"go-pos/dto"
+	"go-pos/inventory"
+	"go-pos/model"
+	"go-pos/pricing"
+	"go-pos/repository"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SalesReturnController handles returns and refunds against completed sales
+type SalesReturnController struct {
+	BaseController
+	repo      *repository.SalesReturnRepository
+	salesRepo *repository.SalesBasketRepository
+	itemRepo  *repository.SalesItemRepository
+}
+
+// SalesReturnRequest is the body of a return. Without items, everything still returnable is returned.
+type SalesReturnRequest struct {
+	Reason string              `json:"reason"`
+	Items  []SalesReturnedLine `json:"items"`
+}
+
+// SalesReturnedLine asks to return qty units of one sales line
+type SalesReturnedLine struct {
+	SalesItemID int `json:"id_sales_item"`
+	Qty         int `json:"qty"`
+}
+
+// Prepare initializes the controller
+func (c *SalesReturnController) Prepare() {
+	// Initialize the repositories
+	c.repo = repository.NewSalesReturnRepository()
+	c.salesRepo = repository.NewSalesBasketRepository()
+	c.itemRepo = repository.NewSalesItemRepository()
+}
+
+// Create takes back all or part of a sale: it records the refund document, puts the
+// quantities back into their batches, gives back its share of any points spent on the sale and
+// reverses the member points the refund covers
+func (c *SalesReturnController) Create() {
+	if !c.RequirePermission(model.PermissionSalesRefund) || !c.Idempotent() {
+		return
+	}
+
+	salesID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var request SalesReturnRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if request.Reason == "" {
+		c.JSONResponse(http.StatusBadRequest, "Return reason is required", nil)
+		return
+	}
+
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
+	}
+
+	// Lock the sale so concurrent returns see each other's quantities
+	sale, err := c.salesRepo.GetSalesBasketForUpdateTx(tx, salesID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return
+	}
+
+	// Only paid sales can be refunded; fully refunded ones have nothing left to return
+	if sale.Status != model.SalesStatusCompleted {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, fmt.Sprintf("A %s sale cannot be returned", sale.Status), nil)
+		return
+	}
+
+	// With the sale locked, its lines and allocations are read as they were paid
+	lines, err := c.itemRepo.GetSalesItemsBySalesTx(tx, salesID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales items: "+err.Error(), nil)
+		return
+	}
+
+	allocations, err := c.itemRepo.GetSalesItemBatchesBySalesTx(tx, salesID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve stock allocations: "+err.Error(), nil)
+		return
+	}
+
+	items, ok := c.returnLines(tx, salesID, lines, allocations, request.Items)
+	if !ok {
+		tx.Rollback()
+		return
+	}
+
+	// The refund is paid out of the drawer of the returning cashier's shift
+	shiftID, ok := c.currentShiftTx(tx)
+	if !ok {
+		tx.Rollback()
+		return
+	}
+
+	salesReturn := &model.SalesReturn{
+		SalesID:       salesID,
+		UserID:        c.CurrentUser().ID,
+		ShiftID:       shiftID,
+		ReturnDate:    time.Now(),
+		Reason:        request.Reason,
+		PaymentMethod: sale.PaymentMethod,
+		Items:         items,
+	}
+	for _, item := range items {
+		salesReturn.TotalRefund += item.TotalRefund
+	}
+
+	if !c.returnPoints(tx, sale, salesReturn) {
+		tx.Rollback()
+		return
+	}
+
+	if !c.splitRefund(tx, sale, salesReturn) || !c.saveReturn(tx, salesReturn) {
+		tx.Rollback()
+		return
+	}
+
+	if !c.markRefunded(tx, sale, lines) {
+		tx.Rollback()
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("sales_return", salesReturn.ID, nil, dto.NewSalesReturnResponse(salesReturn))
+
+	c.JSONResponse(http.StatusCreated, "Sales return created successfully", dto.NewSalesReturnResponse(salesReturn))
+}
+
+// Get retrieves a sales return by ID with its lines
+func (c *SalesReturnController) Get() {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	salesReturn, err := c.repo.GetSalesReturn(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Sales return not found", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Sales return retrieved successfully", dto.NewSalesReturnResponse(salesReturn))
+}
+
+// GetAllBySales retrieves every return taken against a sale
+func (c *SalesReturnController) GetAllBySales() {
+	salesID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	if _, err := c.salesRepo.GetSalesBasket(salesID); err != nil {
+		c.JSONResponse(http.StatusNotFound, "Sales basket not found", nil)
+		return
+	}
+
+	salesReturns, err := c.repo.GetSalesReturnsBySales(salesID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales returns: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Sales returns retrieved successfully", dto.NewSalesReturnResponses(salesReturns))
+}
+
+// returnLines prices the requested lines and picks the batches they go back to, checking each
+// against what is left to return after earlier returns. Without requested lines, every line
+// with a quantity left is returned in full.
+// It writes the error response and returns false on failure.
+func (c *SalesReturnController) returnLines(tx *sql.Tx, salesID int, lines []model.SalesItem, allocations []model.SalesItemBatch, requested []SalesReturnedLine) ([]model.SalesReturnItem, bool) {
+	returned, err := c.repo.GetReturnedQtyBySalesTx(tx, salesID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve returned quantities: "+err.Error(), nil)
+		return nil, false
+	}
+
+	restocked, err := c.repo.GetRestockedBySalesTx(tx, salesID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve restocked quantities: "+err.Error(), nil)
+		return nil, false
+	}
+
+	if len(requested) == 0 {
+		for _, line := range lines {
+			if open := line.Qty - returned[line.ID]; open > 0 {
+				requested = append(requested, SalesReturnedLine{SalesItemID: line.ID, Qty: open})
+			}
+		}
+		if len(requested) == 0 {
+			c.JSONResponse(http.StatusConflict, "Nothing left to return on this sale", nil)
+			return nil, false
+		}
+	}
+
+	var items []model.SalesReturnItem
+	for _, request := range requested {
+		var line *model.SalesItem
+		for i := range lines {
+			if lines[i].ID == request.SalesItemID {
+				line = &lines[i]
+				break
+			}
+		}
+		if line == nil {
+			c.JSONResponse(http.StatusBadRequest, fmt.Sprintf("Sales item %d is not part of sale %d", request.SalesItemID, salesID), nil)
+			return nil, false
+		}
+
+		item, err := pricing.RefundLine(*line, returned[line.ID], request.Qty)
+		if err != nil {
+			var overErr *pricing.OverReturnError
+			if errors.As(err, &overErr) {
+				c.JSONResponse(http.StatusConflict, "Return rejected: "+err.Error(), nil)
+				return nil, false
+			}
+			c.JSONResponse(http.StatusBadRequest, "Return rejected: "+err.Error(), nil)
+			return nil, false
+		}
+
+		var drawn []model.SalesItemBatch
+		for _, allocation := range allocations {
+			if allocation.SalesItemID == line.ID {
+				drawn = append(drawn, allocation)
+			}
+		}
+		if restocked[line.ID] == nil {
+			restocked[line.ID] = make(map[int]int)
+		}
+		item.Batches = inventory.Restock(drawn, restocked[line.ID], item.Qty)
+
+		// A line may be listed more than once; later entries see the earlier ones
+		returned[line.ID] += item.Qty
+		for _, batch := range item.Batches {
+			restocked[line.ID][batch.BatchID] += batch.Qty
+		}
+
+		items = append(items, item)
+	}
+
+	return items, true
+}
+
+// returnPoints gives back the share of the points spent on the sale that this refund covers and
+// takes back the share of the points the sale earned, limited to what the member holds once the
+// spent points are back, and records both on the return.
+// It writes the error response and returns false on failure.
+func (c *SalesReturnController) returnPoints(tx *sql.Tx, sale *model.SalesBasket, salesReturn *model.SalesReturn) bool {
+	if sale.MemberID == 0 {
+		return true
+	}
+
+	refunded, reversed, err := c.repo.GetRefundTotalsBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve earlier refunds: "+err.Error(), nil)
+		return false
+	}
+
+	earned, err := repository.NewMemberPointRepository().GetEarnedPointsBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve earned points: "+err.Error(), nil)
+		return false
+	}
+
+	spent, paid, err := repository.NewSalesPaymentRepository().GetPointsPaidBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve spent points: "+err.Error(), nil)
+		return false
+	}
+
+	restored, value, err := c.repo.GetRestoredBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve restored points: "+err.Error(), nil)
+		return false
+	}
+
+	// Spent points come back in the same proportion earned ones are reversed; the part of the
+	// refund they paid is given back in points rather than money
+	salesReturn.PointsRestored = pricing.ReversePoints(spent, sale.Total, refunded, salesReturn.TotalRefund, restored)
+	salesReturn.PointsRefund = pricing.ReversePoints(paid, sale.Total, refunded, salesReturn.TotalRefund, value)
+	points := pricing.ReversePoints(earned, sale.Total, refunded, salesReturn.TotalRefund, reversed)
+
+	// Later returns of the sale catch up on points the member could not give back this time
+	var ok bool
+	salesReturn.PointsReversed, ok = c.reverseSalePoints(tx, sale.MemberID, sale.ID, salesReturn.PointsRestored, points)
+	return ok
+}
+
+// splitRefund shares out the money part of the refund over the methods the sale was paid with.
+// It writes the error response and returns false on failure.
+func (c *SalesReturnController) splitRefund(tx *sql.Tx, sale *model.SalesBasket, salesReturn *model.SalesReturn) bool {
+	tenders, err := repository.NewSalesPaymentRepository().GetSalesPaymentsBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve sales payments: "+err.Error(), nil)
+		return false
+	}
+
+	refunded, err := c.repo.GetRefundedByMethodBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve earlier refunds: "+err.Error(), nil)
+		return false
+	}
+
+	refund := salesReturn.TotalRefund - salesReturn.PointsRefund
+	salesReturn.Payments = pricing.SplitRefund(tenders, refunded, refund, sale.PaymentMethod)
+	return true
+}
+
+// saveReturn writes the return document, its lines and its refund split, and puts the returned
+// quantities back into their batches. It writes the error response and returns false on failure.
+func (c *SalesReturnController) saveReturn(tx *sql.Tx, salesReturn *model.SalesReturn) bool {
+	if _, err := c.repo.CreateSalesReturnTx(tx, salesReturn); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create sales return: "+err.Error(), nil)
+		return false
+	}
+
+	for i := range salesReturn.Payments {
+		salesReturn.Payments[i].ReturnID = salesReturn.ID
+		if _, err := c.repo.CreateSalesReturnPaymentTx(tx, &salesReturn.Payments[i]); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to record refund payment: "+err.Error(), nil)
+			return false
+		}
+	}
+
+	// Restock in item order, the same order checkout locks batches in
+	sort.SliceStable(salesReturn.Items, func(a, b int) bool {
+		return salesReturn.Items[a].ItemID < salesReturn.Items[b].ItemID
+	})
+
+	batchRepo := repository.NewItemBatchRepository()
+	for i := range salesReturn.Items {
+		item := &salesReturn.Items[i]
+		item.ReturnID = salesReturn.ID
+
+		if _, err := c.repo.CreateSalesReturnItemTx(tx, item); err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to create sales return item: "+err.Error(), nil)
+			return false
+		}
+
+		for j := range item.Batches {
+			item.Batches[j].ReturnItemID = item.ID
+
+			if err := batchRepo.AdjustQtyTx(tx, item.Batches[j].BatchID, item.Batches[j].Qty); err != nil {
+				c.JSONResponse(http.StatusInternalServerError, "Failed to restock item: "+err.Error(), nil)
+				return false
+			}
+			if err := c.repo.CreateSalesReturnItemBatchTx(tx, &item.Batches[j]); err != nil {
+				c.JSONResponse(http.StatusInternalServerError, "Failed to record restocked batch: "+err.Error(), nil)
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// markRefunded moves the sale to REFUNDED once every line has been returned in full.
+// It writes the error response and returns false on failure.
+func (c *SalesReturnController) markRefunded(tx *sql.Tx, sale *model.SalesBasket, lines []model.SalesItem) bool {
+	returned, err := c.repo.GetReturnedQtyBySalesTx(tx, sale.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve returned quantities: "+err.Error(), nil)
+		return false
+	}
+
+	for _, line := range lines {
+		if returned[line.ID] < line.Qty {
+			return true
+		}
+	}
+
+	sale.Status = model.SalesStatusRefunded
+	if err := c.salesRepo.UpdateStatusTx(tx, sale); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update sales status: "+err.Error(), nil)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,423 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/database"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ShiftController handles cashier shifts, the cash moved through their drawers and their X and Z reports
+type ShiftController struct {
+	BaseController
+	repo         *repository.ShiftRepository
+	movementRepo *repository.DrawerMovementRepository
+}
+
+// OpenShiftRequest is the body of a shift opening
+type OpenShiftRequest struct {
+	Register     string `json:"register"`
+	OpeningFloat int    `json:"opening_float"`
+}
+
+// CloseShiftRequest is the body of a Z report: the cash counted in the drawer
+type CloseShiftRequest struct {
+	CountedCash *int   `json:"counted_cash"`
+	Note        string `json:"note"`
+}
+
+// Prepare initializes the controller
+func (c *ShiftController) Prepare() {
+	// Initialize the repositories
+	c.repo = repository.NewShiftRepository()
+	c.movementRepo = repository.NewDrawerMovementRepository()
+}
+
+// Open starts a shift for the current user with the float put in the drawer.
+// A user has at most one open shift.
+func (c *ShiftController) Open() {
+	var request OpenShiftRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if request.OpeningFloat < 0 {
+		c.JSONResponse(http.StatusBadRequest, "Opening float cannot be negative", nil)
+		return
+	}
+
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
+	}
+
+	// Lock the user's open shifts so two openings cannot both go through
+	userID := c.CurrentUser().ID
+	open, err := c.repo.GetOpenShiftByUserForUpdateTx(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve open shift: "+err.Error(), nil)
+		return
+	}
+	if open != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, "Shift "+strconv.Itoa(open.ID)+" is still open; close it first", nil)
+		return
+	}
+
+	shift := &model.Shift{
+		UserID:       userID,
+		Register:     request.Register,
+		Status:       model.ShiftStatusOpen,
+		OpeningFloat: request.OpeningFloat,
+		OpenedAt:     time.Now(),
+	}
+	if _, err := c.repo.CreateShiftTx(tx, shift); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to open shift: "+err.Error(), nil)
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("OPEN", "shift", shift.ID, nil, dto.NewShiftResponse(shift))
+
+	c.JSONResponse(http.StatusCreated, "Shift opened successfully", dto.NewShiftResponse(shift))
+}
+
+// GetCurrent retrieves the shift the current user has open
+func (c *ShiftController) GetCurrent() {
+	shift, err := c.repo.GetOpenShiftByUser(c.CurrentUser().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve open shift: "+err.Error(), nil)
+		return
+	}
+	if shift == nil {
+		c.JSONResponse(http.StatusNotFound, "No open shift", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Shift retrieved successfully", dto.NewShiftResponse(shift))
+}
+
+// Get retrieves a shift by ID
+func (c *ShiftController) Get() {
+	shift, ok := c.accessibleShift()
+	if !ok {
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Shift retrieved successfully", dto.NewShiftResponse(shift))
+}
+
+// GetAll retrieves shifts, newest first. Without the shifts.manage permission only the current
+// user's shifts are listed.
+func (c *ShiftController) GetAll() {
+	var filter model.ShiftFilter
+
+	if userIDStr := c.GetString("user_id"); userIDStr != "" {
+		userID, err := strconv.Atoi(userIDStr)
+		if err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Invalid user ID format", nil)
+			return
+		}
+		filter.UserID = userID
+	}
+	if !c.HasPermission(model.PermissionShiftsManage) {
+		filter.UserID = c.CurrentUser().ID
+	}
+
+	if status := c.GetString("status"); status != "" {
+		filter.Status = model.ShiftStatus(status)
+		if filter.Status != model.ShiftStatusOpen && filter.Status != model.ShiftStatusClosed {
+			c.JSONResponse(http.StatusBadRequest, "Invalid status filter", nil)
+			return
+		}
+	}
+
+	shifts, err := c.repo.GetShifts(filter)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve shifts: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Shifts retrieved successfully", dto.NewShiftResponses(shifts))
+}
+
+// XReport takes a snapshot of an open shift: its takings and refunds per payment method, the
+// cash moved through its drawer and the cash the drawer should hold. The shift stays open.
+func (c *ShiftController) XReport() {
+	shift, ok := c.accessibleShift()
+	if !ok {
+		return
+	}
+
+	if !shift.IsOpen() {
+		c.JSONResponse(http.StatusConflict, "Shift is closed; see its Z report", nil)
+		return
+	}
+
+	takings, err := c.repo.GetShiftTakings(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve shift takings: "+err.Error(), nil)
+		return
+	}
+
+	refunds, err := c.repo.GetShiftRefunds(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve shift refunds: "+err.Error(), nil)
+		return
+	}
+
+	movements, err := c.movementRepo.GetDrawerTotals(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve drawer movements: "+err.Error(), nil)
+		return
+	}
+
+	sales, voided, err := c.repo.CountShiftSales(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to count shift sales: "+err.Error(), nil)
+		return
+	}
+
+	report := model.NewShiftReport(model.ShiftReportX, shift, takings, refunds, movements, time.Now())
+	report.Sales, report.Voided = sales, voided
+
+	c.JSONResponse(http.StatusOK, "X report generated successfully", dto.NewShiftReportResponse(report))
+}
+
+// ZReport closes an open shift with the cash counted in its drawer and reports it against the
+// cash expected from the shift's takings, refunds and drawer movements
+func (c *ShiftController) ZReport() {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var request CloseShiftRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if request.CountedCash == nil || *request.CountedCash < 0 {
+		c.JSONResponse(http.StatusBadRequest, "Counted cash is required and cannot be negative", nil)
+		return
+	}
+
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
+	}
+
+	// Lock the shift; sales still being paid on it finish first
+	shift, err := c.repo.GetShiftForUpdateTx(tx, id)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Shift not found", nil)
+		return
+	}
+
+	if shift.UserID != c.CurrentUser().ID && !c.RequirePermission(model.PermissionShiftsManage) {
+		tx.Rollback()
+		return
+	}
+
+	if !shift.IsOpen() {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, "Shift is already closed", nil)
+		return
+	}
+
+	before := dto.NewShiftResponse(shift)
+
+	takings, err := c.repo.GetShiftTakingsTx(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve shift takings: "+err.Error(), nil)
+		return
+	}
+
+	refunds, err := c.repo.GetShiftRefundsTx(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve shift refunds: "+err.Error(), nil)
+		return
+	}
+
+	movements, err := c.movementRepo.GetDrawerTotalsTx(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve drawer movements: "+err.Error(), nil)
+		return
+	}
+
+	sales, voided, err := c.repo.CountShiftSalesTx(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to count shift sales: "+err.Error(), nil)
+		return
+	}
+
+	now := time.Now()
+	shift.Status = model.ShiftStatusClosed
+	shift.CountedCash = *request.CountedCash
+	shift.ClosedBy = c.CurrentUser().ID
+	shift.ClosedAt = now
+	shift.CloseNote = request.Note
+
+	report := model.NewShiftReport(model.ShiftReportZ, shift, takings, refunds, movements, now)
+	report.Sales, report.Voided = sales, voided
+	shift.ExpectedCash = report.ExpectedCash
+
+	if err := c.repo.CloseShiftTx(tx, shift); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to close shift: "+err.Error(), nil)
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("CLOSE", "shift", shift.ID, before, dto.NewShiftResponse(shift))
+
+	c.JSONResponse(http.StatusOK, "Shift closed successfully", dto.NewShiftReportResponse(report))
+}
+
+// AddMovement records cash put into or taken out of the drawer of an open shift outside of
+// sales, such as a change top-up, a petty cash purchase or a safe drop
+func (c *ShiftController) AddMovement() {
+	if !c.Idempotent() {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var movement model.DrawerMovement
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &movement); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := movement.Validate(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid drawer movement: "+err.Error(), nil)
+		return
+	}
+
+	// Create transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start transaction: "+err.Error(), nil)
+		return
+	}
+
+	// Lock the shift so it cannot be closed while the movement is recorded
+	shift, err := c.repo.GetShiftForUpdateTx(tx, id)
+	if err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusNotFound, "Shift not found", nil)
+		return
+	}
+
+	if shift.UserID != c.CurrentUser().ID && !c.RequirePermission(model.PermissionShiftsManage) {
+		tx.Rollback()
+		return
+	}
+
+	if !shift.IsOpen() {
+		tx.Rollback()
+		c.JSONResponse(http.StatusConflict, "Cash cannot be moved through the drawer of a closed shift", nil)
+		return
+	}
+
+	movement.ShiftID = shift.ID
+	movement.UserID = c.CurrentUser().ID
+	movement.CreatedAt = time.Now()
+
+	if _, err := c.movementRepo.CreateDrawerMovementTx(tx, &movement); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to record drawer movement: "+err.Error(), nil)
+		return
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		c.JSONResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("drawer_movement", movement.ID, nil, dto.NewDrawerMovementResponse(&movement))
+
+	c.JSONResponse(http.StatusCreated, "Drawer movement recorded successfully", dto.NewDrawerMovementResponse(&movement))
+}
+
+// GetMovements reports the cash moved through the drawer of a shift: every movement, with
+// totals per type and reason code
+func (c *ShiftController) GetMovements() {
+	shift, ok := c.accessibleShift()
+	if !ok {
+		return
+	}
+
+	movements, err := c.movementRepo.GetDrawerMovementsByShift(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve drawer movements: "+err.Error(), nil)
+		return
+	}
+
+	totals, err := c.movementRepo.GetDrawerTotals(shift.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve drawer movement totals: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Drawer movements retrieved successfully", dto.NewDrawerMovementReportResponse(shift.ID, movements, totals))
+}
+
+// accessibleShift loads the shift named by the :id parameter if the current user may see it:
+// their own shifts, or any shift with the shifts.manage permission.
+// It writes the error response and returns false otherwise.
+func (c *ShiftController) accessibleShift() (*model.Shift, bool) {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return nil, false
+	}
+
+	shift, err := c.repo.GetShift(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Shift not found", nil)
+		return nil, false
+	}
+
+	if shift.UserID != c.CurrentUser().ID && !c.RequirePermission(model.PermissionShiftsManage) {
+		return nil, false
+	}
+
+	return shift, true
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/dto"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strconv"
+)
+
+// TaxRateController handles TaxRate CRUD operations and the tax summary report
+type TaxRateController struct {
+	BaseController
+	repo *repository.TaxRateRepository
+}
+
+// Prepare initializes the controller
+func (c *TaxRateController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewTaxRateRepository()
+}
+
+// Create adds a new tax rate
+func (c *TaxRateController) Create() {
+	if !c.RequirePermission(model.PermissionTaxesManage) {
+		return
+	}
+
+	var taxRate model.TaxRate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &taxRate); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := taxRate.Validate(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid tax rate: "+err.Error(), nil)
+		return
+	}
+
+	newTaxRate, err := c.repo.CreateTaxRate(&taxRate)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create tax rate: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("tax_rate", newTaxRate.ID, nil, dto.NewTaxRateResponse(newTaxRate))
+
+	c.JSONResponse(http.StatusCreated, "Tax rate created successfully", dto.NewTaxRateResponse(newTaxRate))
+}
+
+// Get retrieves a tax rate by ID
+func (c *TaxRateController) Get() {
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	taxRate, err := c.repo.GetTaxRate(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Tax rate not found", nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Tax rate retrieved successfully", dto.NewTaxRateResponse(taxRate))
+}
+
+// GetAll retrieves all tax rates
+func (c *TaxRateController) GetAll() {
+	taxRates, err := c.repo.GetAllTaxRates()
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve tax rates: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Tax rates retrieved successfully", dto.NewTaxRateResponses(taxRates))
+}
+
+// Update updates a tax rate. Sales already taxed keep the rate they were taxed at.
+func (c *TaxRateController) Update() {
+	if !c.RequirePermission(model.PermissionTaxesManage) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	var taxRate model.TaxRate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &taxRate); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	taxRate.ID = id
+
+	if err := taxRate.Validate(); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid tax rate: "+err.Error(), nil)
+		return
+	}
+
+	// Check if tax rate exists
+	existingTaxRate, err := c.repo.GetTaxRate(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Tax rate not found", nil)
+		return
+	}
+
+	updatedTaxRate, err := c.repo.UpdateTaxRate(&taxRate)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update tax rate: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("tax_rate", id, dto.NewTaxRateResponse(existingTaxRate), dto.NewTaxRateResponse(updatedTaxRate))
+
+	c.JSONResponse(http.StatusOK, "Tax rate updated successfully", dto.NewTaxRateResponse(updatedTaxRate))
+}
+
+// Delete deletes a tax rate that no category or item is assigned to
+func (c *TaxRateController) Delete() {
+	if !c.RequirePermission(model.PermissionTaxesManage) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+
+	// Check if tax rate exists
+	existingTaxRate, err := c.repo.GetTaxRate(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "Tax rate not found", nil)
+		return
+	}
+
+	inUse, err := c.repo.IsTaxRateInUse(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check tax rate usage: "+err.Error(), nil)
+		return
+	}
+	if inUse {
+		c.JSONResponse(http.StatusConflict, "Tax rate is assigned to categories or items", nil)
+		return
+	}
+
+	if err := c.repo.DeleteTaxRate(id); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to delete tax rate: "+err.Error(), nil)
+		return
+	}
+
+	c.Audit("tax_rate", id, dto.NewTaxRateResponse(existingTaxRate), nil)
+
+	c.JSONResponse(http.StatusOK, "Tax rate deleted successfully", nil)
+}
+
+// GetSummary reports the tax charged on paid sales and refunded by returns per rate, optionally
+// between the from and to dates, for tax filings
+func (c *TaxRateController) GetSummary() {
+	if !c.RequirePermission(model.PermissionReportsView) {
+		return
+	}
+
+	from, to, ok := c.reportPeriod()
+	if !ok {
+		return
+	}
+
+	summary, err := c.repo.GetTaxSummary(from, to)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve tax summary: "+err.Error(), nil)
+		return
+	}
+
+	c.JSONResponse(http.StatusOK, "Tax summary retrieved successfully", dto.NewTaxSummaryResponses(summary))
+}
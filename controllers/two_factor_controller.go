@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-pos/config"
+	"go-pos/model"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TwoFactorController handles TOTP enrollment for the current user
+type TwoFactorController struct {
+	BaseController
+	repo *repository.UserTOTPRepository
+}
+
+// TwoFactorRequest carries the credentials needed to change the two-factor settings
+type TwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// TwoFactorStatusResponse describes the two-factor state of the current user
+type TwoFactorStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// TwoFactorEnrollmentResponse carries a new secret for the user's authenticator app.
+// The otpauth URI is the payload to render as a QR code.
+type TwoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse carries freshly generated recovery codes; they are only shown once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Prepare initializes the controller
+func (c *TwoFactorController) Prepare() {
+	// Initialize the repository
+	c.repo = repository.NewUserTOTPRepository()
+}
+
+// Get retrieves the two-factor status of the current user
+func (c *TwoFactorController) Get() {
+	user := c.CurrentUser()
+
+	enabled, err := c.repo.IsTOTPEnabled(user.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve two-factor status: "+err.Error(), nil)
+		return
+	}
+
+	recoveryCodesLeft := 0
+	if enabled {
+		recoveryCodesLeft, err = c.repo.CountRecoveryCodes(user.ID)
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve two-factor status: "+err.Error(), nil)
+			return
+		}
+	}
+
+	c.JSONResponse(http.StatusOK, "Two-factor status retrieved successfully", TwoFactorStatusResponse{
+		Enabled:           enabled,
+		Required:          twoFactorRequired(user),
+		RecoveryCodesLeft: recoveryCodesLeft,
+	})
+}
+
+// Enroll generates a new secret for the current user. Two-factor login stays off until
+// the secret is confirmed with a code from the authenticator app.
+func (c *TwoFactorController) Enroll() {
+	var req TwoFactorRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	user, ok := c.checkPassword(req.Password)
+	if !ok {
+		return
+	}
+
+	enabled, err := c.repo.IsTOTPEnabled(user.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to check two-factor enrollment: "+err.Error(), nil)
+		return
+	}
+	if enabled {
+		c.JSONResponse(http.StatusConflict, "Two-factor authentication is already enabled", nil)
+		return
+	}
+
+	totp := &model.UserTOTP{
+		UserID:    user.ID,
+		Secret:    security.NewTOTPSecret(),
+		CreatedAt: time.Now(),
+	}
+
+	if err := c.repo.SavePendingTOTP(totp); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to start enrollment: "+err.Error(), nil)
+		return
+	}
+
+	// The secret itself is never written to the audit trail
+	c.AuditAs("2FA_ENROLL", "user", user.ID, nil, nil)
+
+	policy := config.GetTwoFactorPolicy()
+	c.JSONResponse(http.StatusOK, "Scan the code with an authenticator app and confirm it", TwoFactorEnrollmentResponse{
+		Secret:     totp.Secret,
+		OtpauthURI: security.TOTPURI(policy.Issuer, user.Name, totp.Secret),
+	})
+}
+
+// Confirm verifies the first code from the authenticator app, turns two-factor login on
+// and returns the recovery codes
+func (c *TwoFactorController) Confirm() {
+	var req TwoFactorRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	user := c.CurrentUser()
+
+	totp, err := c.repo.GetUserTOTP(user.ID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "No pending two-factor enrollment", nil)
+		return
+	}
+	if totp.Enabled {
+		c.JSONResponse(http.StatusConflict, "Two-factor authentication is already enabled", nil)
+		return
+	}
+
+	now := time.Now()
+	step, ok := security.VerifyTOTP(totp.Secret, req.Code, now, config.GetTwoFactorPolicy().Skew)
+	if !ok {
+		c.JSONResponse(http.StatusBadRequest, "Invalid two-factor code", nil)
+		return
+	}
+
+	codes, hashes := newRecoveryCodes(config.GetTwoFactorPolicy().RecoveryCodes)
+	if err := c.repo.EnableTOTP(user.ID, step, now, hashes); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to enable two-factor authentication: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("2FA_ENABLE", "user", user.ID, nil, nil)
+
+	c.JSONResponse(http.StatusOK, "Two-factor authentication enabled, store the recovery codes safely", RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// Delete turns two-factor login off for the current user.
+// Both the password and a current code are required.
+func (c *TwoFactorController) Delete() {
+	var req TwoFactorRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	user, ok := c.checkPassword(req.Password)
+	if !ok {
+		return
+	}
+
+	if twoFactorRequired(user) {
+		c.JSONResponse(http.StatusForbidden, "Two-factor authentication is mandatory for admin accounts", nil)
+		return
+	}
+
+	if !c.checkCode(user.ID, req.Code) {
+		return
+	}
+
+	if err := c.repo.DeleteUserTOTP(user.ID); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to disable two-factor authentication: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("2FA_DISABLE", "user", user.ID, nil, nil)
+
+	c.JSONResponse(http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user
+func (c *TwoFactorController) RegenerateRecoveryCodes() {
+	var req TwoFactorRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	user := c.CurrentUser()
+	if !c.checkCode(user.ID, req.Code) {
+		return
+	}
+
+	codes, hashes := newRecoveryCodes(config.GetTwoFactorPolicy().RecoveryCodes)
+	if err := c.repo.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to replace recovery codes: "+err.Error(), nil)
+		return
+	}
+
+	c.AuditAs("2FA_RECOVERY_CODES", "user", user.ID, nil, nil)
+
+	c.JSONResponse(http.StatusOK, "Recovery codes replaced, store them safely", RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// checkPassword reloads the current user and verifies their password.
+// It writes the error response and returns false on failure.
+func (c *TwoFactorController) checkPassword(password string) (*model.User, bool) {
+	user, err := repository.NewUserRepository().GetUser(c.CurrentUser().ID)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return nil, false
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		c.JSONResponse(http.StatusUnauthorized, "Password is incorrect", nil)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// checkCode verifies a TOTP or recovery code of an enrolled user.
+// It writes the error response and returns false on failure.
+func (c *TwoFactorController) checkCode(userID int, code string) bool {
+	verified, err := verifySecondFactor(userID, code, "")
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to verify two-factor code", nil)
+		return false
+	}
+	if !verified {
+		c.JSONResponse(http.StatusUnauthorized, "Invalid two-factor code", nil)
+		return false
+	}
+	return true
+}
+
+// twoFactorRequired reports whether configuration makes two-factor login mandatory for the user
+func twoFactorRequired(user *model.User) bool {
+	return user.IsAdmin && config.GetTwoFactorPolicy().RequiredForAdmins
+}
+
+// verifySecondFactor checks a TOTP code, or a recovery code when one is given, for an enrolled user.
+// Accepted TOTP codes and recovery codes cannot be used again.
+func verifySecondFactor(userID int, code string, recoveryCode string) (bool, error) {
+	totpRepo := repository.NewUserTOTPRepository()
+
+	enabled, err := totpRepo.IsTOTPEnabled(userID)
+	if err != nil || !enabled {
+		return false, err
+	}
+
+	if recoveryCode != "" {
+		return totpRepo.UseRecoveryCode(userID, security.HashToken(normalizeRecoveryCode(recoveryCode)), time.Now())
+	}
+
+	totp, err := totpRepo.GetUserTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := security.VerifyTOTP(totp.Secret, code, time.Now(), config.GetTwoFactorPolicy().Skew)
+	if !ok {
+		return false, nil
+	}
+
+	return totpRepo.UseStep(userID, step)
+}
+
+// newRecoveryCodes returns count recovery codes formatted for display, with their digests
+func newRecoveryCodes(count int) ([]string, []string) {
+	codes := make([]string, count)
+	hashes := make([]string, count)
+	for i := range codes {
+		code := security.NewCode(10)
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = security.HashToken(code)
+	}
+	return codes, hashes
+}
+
+// normalizeRecoveryCode strips the separator and spacing users may type
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
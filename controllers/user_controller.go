@@ -2,12 +2,15 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
+	"go-pos/config"
 	"go-pos/model"
 	"net/http"
 	"strconv"
+	"time"
 	"golang.org/x/crypto/bcrypt"
-	"github.com/google/uuid"
 	"go-pos/repository"
+	"go-pos/security"
 )
 
 // UserController handles User CRUD operations
@@ -17,6 +20,10 @@ type UserController struct {
 
 // Create adds a new user
 func (c *UserController) Create() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
 	var user model.User
 	
 	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &user); err != nil {
@@ -24,6 +31,17 @@ func (c *UserController) Create() {
 		return
 	}
 	
+	// Only admins may create other admins
+	if !c.canGrantAdmin() {
+		user.IsAdmin = false
+	}
+	
+	// Enforce the password policy
+	if err := config.GetPasswordPolicy().Validate(user.PasswordHash); err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Password rejected: "+err.Error(), nil)
+		return
+	}
+	
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.PasswordHash), bcrypt.DefaultCost)
 	if err != nil {
@@ -32,10 +50,6 @@ func (c *UserController) Create() {
 	}
 	user.PasswordHash = string(hashedPassword)
 	
-	// Generate a unique token using UUID
-	token := uuid.New().String()
-	user.Token = token
-	
 	// Create new user repository instance
 	repo := repository.NewUserRepository()
 	
@@ -46,10 +60,16 @@ func (c *UserController) Create() {
 		return
 	}
 	
-	// Don't return sensitive information
-	newUser.PasswordHash = ""
+	c.Audit("user", newUser.ID, nil, dto.NewUserResponse(newUser))
 	
-	c.JSONResponse(http.StatusCreated, "User created successfully", newUser)
+	c.JSONResponse(http.StatusCreated, "User created successfully", dto.NewUserResponse(newUser))
+}
+
+// canGrantAdmin reports whether the current user may set the admin flag of a user: only admins
+// signed in themselves may, not holders of users.manage nor API keys
+func (c *UserController) canGrantAdmin() bool {
+	user := c.CurrentUser()
+	return user != nil && user.IsAdmin && c.CurrentAPIKey() == nil
 }
 
 // Get retrieves a user by ID
@@ -71,10 +91,7 @@ func (c *UserController) Get() {
 		return
 	}
 	
-	// Don't include password hash in response for security
-	user.PasswordHash = ""
-	
-	c.JSONResponse(http.StatusOK, "User retrieved successfully", user)
+	c.JSONResponse(http.StatusOK, "User retrieved successfully", dto.NewUserResponse(user))
 }
 
 // GetAll retrieves all users
@@ -89,16 +106,15 @@ func (c *UserController) GetAll() {
 		return
 	}
 	
-	// Don't include password hash in response for security
-	for i := range users {
-		users[i].PasswordHash = ""
-	}
-	
-	c.JSONResponse(http.StatusOK, "Users retrieved successfully", users)
+	c.JSONResponse(http.StatusOK, "Users retrieved successfully", dto.NewUserResponses(users))
 }
 
 // Update updates a user
 func (c *UserController) Update() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -124,8 +140,19 @@ func (c *UserController) Update() {
 		return
 	}
 	
-	// If password is being updated, hash it
-	if user.PasswordHash != "" {
+	// Only admins may grant or take away admin rights
+	if !c.canGrantAdmin() {
+		user.IsAdmin = existingUser.IsAdmin
+	}
+	
+	// If password is being updated, enforce the policy and hash it
+	passwordChanged := user.PasswordHash != ""
+	if passwordChanged {
+		if err := config.GetPasswordPolicy().Validate(user.PasswordHash); err != nil {
+			c.JSONResponse(http.StatusBadRequest, "Password rejected: "+err.Error(), nil)
+			return
+		}
+		
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.PasswordHash), bcrypt.DefaultCost)
 		if err != nil {
 			c.JSONResponse(http.StatusInternalServerError, "Failed to hash password", nil)
@@ -144,14 +171,26 @@ func (c *UserController) Update() {
 		return
 	}
 	
-	// Don't return sensitive information
-	updatedUser.PasswordHash = ""
+	// A new password ends every session opened with the old one
+	if passwordChanged {
+		err = repository.NewUserSessionRepository().DeleteSessionsByUser(id, "")
+		if err != nil {
+			c.JSONResponse(http.StatusInternalServerError, "Failed to end user sessions: "+err.Error(), nil)
+			return
+		}
+	}
+	
+	c.Audit("user", id, dto.NewUserResponse(existingUser), dto.NewUserResponse(updatedUser))
 	
-	c.JSONResponse(http.StatusOK, "User updated successfully", updatedUser)
+	c.JSONResponse(http.StatusOK, "User updated successfully", dto.NewUserResponse(updatedUser))
 }
 
 // Delete deletes a user
 func (c *UserController) Delete() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -163,7 +202,7 @@ func (c *UserController) Delete() {
 	repo := repository.NewUserRepository()
 	
 	// Check if user exists
-	_, err = repo.GetUser(id)
+	existingUser, err := repo.GetUser(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "User not found", nil)
 		return
@@ -176,5 +215,134 @@ func (c *UserController) Delete() {
 		return
 	}
 	
+	c.Audit("user", id, dto.NewUserResponse(existingUser), nil)
+	
 	c.JSONResponse(http.StatusOK, "User deleted successfully", nil)
-}
\ No newline at end of file
+}
+
+// Unlock clears the failed login counter and lockout of a user's NIK
+func (c *UserController) Unlock() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+	
+	// Check if user exists
+	user, err := repository.NewUserRepository().GetUser(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return
+	}
+	
+	err = repository.NewLoginAttemptRepository().DeleteLoginAttempt(nikAttemptKey(user.NIK))
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to unlock user: "+err.Error(), nil)
+		return
+	}
+	
+	// Record the unlock against the affected user
+	userLog := &model.UserLog{
+		UserID:          user.ID,
+		Date:            time.Now(),
+		IP:              c.Ctx.Input.IP(),
+		PlatformBrowser: c.Ctx.Request.UserAgent(),
+		Action:          "UNLOCKED",
+	}
+	repository.NewUserLogRepository().CreateUserLog(userLog)
+	
+	c.AuditAs("UNLOCK", "user", id, nil, nil)
+	
+	c.JSONResponse(http.StatusOK, "User unlocked successfully", nil)
+}
+
+// GetLockouts lists the NIKs and addresses that are currently locked out
+func (c *UserController) GetLockouts() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
+	attempts, err := repository.NewLoginAttemptRepository().GetLockedAttempts(time.Now())
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve lockouts: "+err.Error(), nil)
+		return
+	}
+	
+	c.JSONResponse(http.StatusOK, "Lockouts retrieved successfully", dto.NewLoginAttemptResponses(attempts))
+}
+
+// DeleteLockout clears a lockout by its key, e.g. "ip:10.0.0.5"
+func (c *UserController) DeleteLockout() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
+	key := c.Ctx.Input.Param(":key")
+	
+	err := repository.NewLoginAttemptRepository().DeleteLoginAttempt(key)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to clear lockout: "+err.Error(), nil)
+		return
+	}
+	
+	c.AuditAs("UNLOCK", "login_lockout", key, nil, nil)
+	
+	c.JSONResponse(http.StatusOK, "Lockout cleared successfully", nil)
+}
+
+// ResetCodeResponse represents an issued password reset code
+type ResetCodeResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IssueResetCode creates a one-time password reset code for a user.
+// The plain code is only returned here; it is handed to the user out of band.
+func (c *UserController) IssueResetCode() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
+	idStr := c.Ctx.Input.Param(":id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSONResponse(http.StatusBadRequest, "Invalid ID format", nil)
+		return
+	}
+	
+	// Check if user exists
+	_, err = repository.NewUserRepository().GetUser(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User not found", nil)
+		return
+	}
+	
+	now := time.Now()
+	code := security.NewCode(10)
+	reset := &model.PasswordReset{
+		UserID:    id,
+		CodeHash:  security.HashToken(code),
+		CreatedBy: c.CurrentUser().ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.GetPasswordPolicy().ResetCodeTTL),
+	}
+	
+	_, err = repository.NewPasswordResetRepository().CreatePasswordReset(reset)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create reset code: "+err.Error(), nil)
+		return
+	}
+	
+	// The code itself is never written to the audit trail
+	c.AuditAs("RESET_CODE", "user", id, nil, nil)
+	
+	c.JSONResponse(http.StatusCreated, "Reset code issued successfully", ResetCodeResponse{
+		Code:      code,
+		ExpiresAt: reset.ExpiresAt,
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
 	"go-pos/repository"
 	"net/http"
@@ -48,11 +49,17 @@ func (c *UserLogController) Create() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusCreated, "User log created successfully", newUserLog)
+	c.Audit("user_log", newUserLog.ID, nil, dto.NewUserLogResponse(newUserLog))
+	
+	c.JSONResponse(http.StatusCreated, "User log created successfully", dto.NewUserLogResponse(newUserLog))
 }
 
 // Get retrieves a user log by ID
 func (c *UserLogController) Get() {
+	if !c.RequirePermission(model.PermissionReportsView) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -70,11 +77,15 @@ func (c *UserLogController) Get() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "User log retrieved successfully", userLog)
+	c.JSONResponse(http.StatusOK, "User log retrieved successfully", dto.NewUserLogResponse(userLog))
 }
 
 // GetAll retrieves all user logs
 func (c *UserLogController) GetAll() {
+	if !c.RequirePermission(model.PermissionReportsView) {
+		return
+	}
+	
 	// Check for optional filter by user ID
 	userIDStr := c.GetString("user_id")
 	var userID int
@@ -105,11 +116,15 @@ func (c *UserLogController) GetAll() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "User logs retrieved successfully", userLogs)
+	c.JSONResponse(http.StatusOK, "User logs retrieved successfully", dto.NewUserLogResponses(userLogs))
 }
 
 // Update updates a user log
 func (c *UserLogController) Update() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -129,7 +144,7 @@ func (c *UserLogController) Update() {
 	repo := repository.NewUserLogRepository()
 	
 	// Check if user log exists
-	_, err = repo.GetUserLog(id)
+	existingUserLog, err := repo.GetUserLog(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "User log not found", nil)
 		return
@@ -142,11 +157,17 @@ func (c *UserLogController) Update() {
 		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "User log updated successfully", updatedUserLog)
+	c.Audit("user_log", id, dto.NewUserLogResponse(existingUserLog), dto.NewUserLogResponse(updatedUserLog))
+	
+	c.JSONResponse(http.StatusOK, "User log updated successfully", dto.NewUserLogResponse(updatedUserLog))
 }
 
 // Delete deletes a user log
 func (c *UserLogController) Delete() {
+	if !c.RequirePermission(model.PermissionUsersManage) {
+		return
+	}
+	
 	idStr := c.Ctx.Input.Param(":id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -158,7 +179,7 @@ func (c *UserLogController) Delete() {
 	repo := repository.NewUserLogRepository()
 	
 	// Check if user log exists
-	_, err = repo.GetUserLog(id)
+	existingUserLog, err := repo.GetUserLog(id)
 	if err != nil {
 		c.JSONResponse(http.StatusNotFound, "User log not found", nil)
 		return
@@ -171,5 +192,7 @@ func (c *UserLogController) Delete() {
 		return
 	}
 	
+	c.Audit("user_log", id, dto.NewUserLogResponse(existingUserLog), nil)
+	
 	c.JSONResponse(http.StatusOK, "User log deleted successfully", nil)
 }
@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"encoding/json"
+	"go-pos/dto"
 	"go-pos/model"
+	"go-pos/repository"
 	"net/http"
 	"strconv"
 	"time"
@@ -37,11 +39,19 @@ func (c *UserMemberController) Create() {
 		userMember.PlatformBrowser = c.Ctx.Request.UserAgent()
 	}
 	
-	// TODO: Implement repository call to save the user member log
-	// For now, mock the response
-	userMember.ID = 1 // Mocked ID
+	// Create repository instance
+	repo := repository.NewUserMemberRepository()
 	
-	c.JSONResponse(http.StatusCreated, "User member log created successfully", userMember)
+	// Save the user member log
+	newUserMember, err := repo.CreateUserMember(&userMember)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to create user member log: "+err.Error(), nil)
+		return
+	}
+	
+	c.Audit("user_member", newUserMember.ID, nil, dto.NewUserMemberResponse(newUserMember))
+	
+	c.JSONResponse(http.StatusCreated, "User member log created successfully", dto.NewUserMemberResponse(newUserMember))
 }
 
 // Get retrieves a user member log by ID
@@ -53,17 +63,17 @@ func (c *UserMemberController) Get() {
 		return
 	}
 	
-	// TODO: Implement repository call to fetch the user member log
-	// For now, mock the response
-	userMember := &model.UserMember{
-		ID:              id,
-		MemberID:        1,
-		Date:            time.Now(),
-		IP:              "192.168.1.1",
-		PlatformBrowser: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36",
+	// Create repository instance
+	repo := repository.NewUserMemberRepository()
+	
+	// Fetch the user member log
+	userMember, err := repo.GetUserMember(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User member log not found", nil)
+		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "User member log retrieved successfully", userMember)
+	c.JSONResponse(http.StatusOK, "User member log retrieved successfully", dto.NewUserMemberResponse(userMember))
 }
 
 // GetAll retrieves all user member logs
@@ -81,37 +91,24 @@ func (c *UserMemberController) GetAll() {
 		}
 	}
 	
-	// TODO: Implement repository call to fetch user member logs, filtered by member ID if provided
-	// For now, mock the response
-	userMembers := []model.UserMember{
-		{
-			ID:              1,
-			MemberID:        1,
-			Date:            time.Now().Add(-1 * time.Hour),
-			IP:              "192.168.1.1",
-			PlatformBrowser: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36",
-		},
-		{
-			ID:              2,
-			MemberID:        2,
-			Date:            time.Now(),
-			IP:              "192.168.1.2",
-			PlatformBrowser: "Mozilla/5.0 (iPhone; CPU iPhone OS 13_2_3)",
-		},
-	}
-	
-	// Filter by member ID if specified
+	// Create repository instance
+	repo := repository.NewUserMemberRepository()
+	
+	var userMembers []model.UserMember
+	
+	// Fetch user member logs, filtered by member ID if provided
 	if memberIDStr != "" {
-		var filteredLogs []model.UserMember
-		for _, log := range userMembers {
-			if log.MemberID == memberID {
-				filteredLogs = append(filteredLogs, log)
-			}
-		}
-		userMembers = filteredLogs
+		userMembers, err = repo.GetUserMembersByMember(memberID)
+	} else {
+		userMembers, err = repo.GetAllUserMembers()
+	}
+	
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to retrieve user member logs: "+err.Error(), nil)
+		return
 	}
 	
-	c.JSONResponse(http.StatusOK, "User member logs retrieved successfully", userMembers)
+	c.JSONResponse(http.StatusOK, "User member logs retrieved successfully", dto.NewUserMemberResponses(userMembers))
 }
 
 // Update updates a user member log
@@ -131,9 +128,26 @@ func (c *UserMemberController) Update() {
 	
 	userMember.ID = id
 	
-	// TODO: Implement repository call to update the user member log
+	// Create repository instance
+	repo := repository.NewUserMemberRepository()
 	
-	c.JSONResponse(http.StatusOK, "User member log updated successfully", userMember)
+	// Check if user member log exists
+	existingUserMember, err := repo.GetUserMember(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User member log not found", nil)
+		return
+	}
+	
+	// Update user member log
+	updatedUserMember, err := repo.UpdateUserMember(&userMember)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to update user member log: "+err.Error(), nil)
+		return
+	}
+	
+	c.Audit("user_member", id, dto.NewUserMemberResponse(existingUserMember), dto.NewUserMemberResponse(updatedUserMember))
+	
+	c.JSONResponse(http.StatusOK, "User member log updated successfully", dto.NewUserMemberResponse(updatedUserMember))
 }
 
 // Delete deletes a user member log
@@ -145,8 +159,24 @@ func (c *UserMemberController) Delete() {
 		return
 	}
 	
-	// TODO: Implement repository call to delete the user member log
-	_ = id //temporary fix
+	// Create repository instance
+	repo := repository.NewUserMemberRepository()
+	
+	// Check if user member log exists
+	existingUserMember, err := repo.GetUserMember(id)
+	if err != nil {
+		c.JSONResponse(http.StatusNotFound, "User member log not found", nil)
+		return
+	}
+	
+	// Delete user member log
+	err = repo.DeleteUserMember(id)
+	if err != nil {
+		c.JSONResponse(http.StatusInternalServerError, "Failed to delete user member log: "+err.Error(), nil)
+		return
+	}
+	
+	c.Audit("user_member", id, dto.NewUserMemberResponse(existingUserMember), nil)
 	
 	c.JSONResponse(http.StatusOK, "User member log deleted successfully", nil)
 }
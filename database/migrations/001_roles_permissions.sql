@@ -0,0 +1,48 @@
+-- Roles and permissions for staff users.
+-- user.is_admin keeps working as a super-user flag that bypasses permission checks.
+
+CREATE TABLE IF NOT EXISTS role (
+    id_role     INT AUTO_INCREMENT PRIMARY KEY,
+    role_name   VARCHAR(64) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permission (
+    id_permission INT AUTO_INCREMENT PRIMARY KEY,
+    code          VARCHAR(64) NOT NULL UNIQUE,
+    description   VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permission (
+    id_role       INT NOT NULL,
+    id_permission INT NOT NULL,
+    PRIMARY KEY (id_role, id_permission),
+    FOREIGN KEY (id_role) REFERENCES role (id_role) ON DELETE CASCADE,
+    FOREIGN KEY (id_permission) REFERENCES permission (id_permission) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_role (
+    id_user INT NOT NULL,
+    id_role INT NOT NULL,
+    PRIMARY KEY (id_user, id_role),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE,
+    FOREIGN KEY (id_role) REFERENCES role (id_role) ON DELETE CASCADE
+);
+
+INSERT IGNORE INTO permission (code, description) VALUES
+    ('sales.void', 'Void or delete sales'),
+    ('items.price.edit', 'Change item prices'),
+    ('users.manage', 'Manage staff users and roles'),
+    ('reports.view', 'View reports and logs');
+
+INSERT IGNORE INTO role (role_name, description) VALUES
+    ('cashier', 'Front counter sales'),
+    ('supervisor', 'Approves voids and views reports'),
+    ('stock_clerk', 'Maintains items and stock'),
+    ('manager', 'Full store management');
+
+INSERT IGNORE INTO role_permission (id_role, id_permission)
+SELECT r.id_role, p.id_permission FROM role r JOIN permission p
+WHERE (r.role_name = 'supervisor' AND p.code IN ('sales.void', 'reports.view'))
+   OR (r.role_name = 'stock_clerk' AND p.code IN ('items.price.edit'))
+   OR (r.role_name = 'manager');
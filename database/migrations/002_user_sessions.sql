@@ -0,0 +1,16 @@
+-- Per-device login sessions, replacing the single user.token column.
+-- Only SHA-256 digests of the access and refresh tokens are stored.
+
+CREATE TABLE IF NOT EXISTS user_session (
+    id_session         CHAR(36) PRIMARY KEY,
+    id_user            INT NOT NULL,
+    device             VARCHAR(100) NOT NULL DEFAULT '',
+    token_hash         CHAR(64) NOT NULL UNIQUE,
+    refresh_token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at         DATETIME NOT NULL,
+    last_seen_at       DATETIME NOT NULL,
+    expires_at         DATETIME NOT NULL,
+    refresh_expires_at DATETIME NOT NULL,
+    INDEX idx_user_session_user (id_user),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+-- Member self-service login.
+-- member.token holds the SHA-256 digest of the member's current login token.
+
+ALTER TABLE member
+    MODIFY token CHAR(64) NOT NULL DEFAULT '',
+    MODIFY password_hash VARCHAR(255) NOT NULL DEFAULT '';
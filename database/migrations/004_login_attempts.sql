@@ -0,0 +1,9 @@
+-- Failed login counters used for progressive delays and temporary lockout.
+-- attempt_key is "nik:<nik>" or "ip:<address>".
+
+CREATE TABLE IF NOT EXISTS login_attempt (
+    attempt_key    VARCHAR(80) PRIMARY KEY,
+    failures       INT NOT NULL DEFAULT 0,
+    last_failed_at DATETIME NOT NULL,
+    locked_until   DATETIME NULL
+);
@@ -0,0 +1,14 @@
+-- One-time password reset codes issued by administrators.
+-- Only the SHA-256 digest of each code is stored.
+
+CREATE TABLE IF NOT EXISTS password_reset (
+    id_reset   INT AUTO_INCREMENT PRIMARY KEY,
+    id_user    INT NOT NULL,
+    code_hash  CHAR(64) NOT NULL,
+    created_by INT NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at    DATETIME NULL,
+    INDEX idx_password_reset_user (id_user),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
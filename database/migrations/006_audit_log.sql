@@ -0,0 +1,16 @@
+-- Audit trail of every successful mutating API call made by a staff user.
+-- changes holds a JSON object of {field: {before, after}} for the affected entity.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id_audit    BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id_user     INT NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id   VARCHAR(64) NOT NULL DEFAULT '',
+    action      VARCHAR(30) NOT NULL,
+    ip          VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent  VARCHAR(255) NOT NULL DEFAULT '',
+    changes     TEXT NOT NULL,
+    created_at  DATETIME NOT NULL,
+    INDEX idx_audit_user (id_user, created_at),
+    INDEX idx_audit_entity (entity_type, entity_id, created_at)
+);
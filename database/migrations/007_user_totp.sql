@@ -0,0 +1,34 @@
+-- TOTP two-factor authentication for staff accounts.
+-- The secret is stored as-is because it is needed to compute codes; recovery codes and
+-- login challenge tokens are stored as SHA-256 digests only.
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    id_user        INT PRIMARY KEY,
+    secret         VARCHAR(64) NOT NULL,
+    enabled        BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     DATETIME NOT NULL,
+    confirmed_at   DATETIME NULL,
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_code (
+    id_recovery_code INT AUTO_INCREMENT PRIMARY KEY,
+    id_user          INT NOT NULL,
+    code_hash        CHAR(64) NOT NULL,
+    used_at          DATETIME NULL,
+    INDEX idx_recovery_code_user (id_user),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
+
+-- Pending second login steps: issued after a correct password, redeemed with a TOTP or recovery code
+CREATE TABLE IF NOT EXISTS login_challenge (
+    id_challenge CHAR(36) PRIMARY KEY,
+    id_user      INT NOT NULL,
+    token_hash   CHAR(64) NOT NULL,
+    device       VARCHAR(100) NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    UNIQUE KEY uq_login_challenge_token (token_hash),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
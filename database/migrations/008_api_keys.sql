@@ -0,0 +1,36 @@
+-- Long-lived API keys for machine-to-machine integrations.
+-- Only the SHA-256 digest of each key is stored; key_prefix is kept to tell keys apart.
+-- A key acts as id_user, is limited to the permissions in api_key_permission,
+-- and can only call the endpoints listed in api_key_route.
+
+CREATE TABLE IF NOT EXISTS api_key (
+    id_api_key   INT AUTO_INCREMENT PRIMARY KEY,
+    key_name     VARCHAR(100) NOT NULL,
+    key_prefix   VARCHAR(16) NOT NULL,
+    key_hash     CHAR(64) NOT NULL,
+    id_user      INT NOT NULL,
+    created_by   INT NOT NULL,
+    created_at   DATETIME NOT NULL,
+    last_used_at DATETIME NULL,
+    expires_at   DATETIME NULL,
+    revoked_at   DATETIME NULL,
+    UNIQUE KEY uq_api_key_hash (key_hash),
+    FOREIGN KEY (id_user) REFERENCES user (id_user) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS api_key_permission (
+    id_api_key    INT NOT NULL,
+    id_permission INT NOT NULL,
+    PRIMARY KEY (id_api_key, id_permission),
+    FOREIGN KEY (id_api_key) REFERENCES api_key (id_api_key) ON DELETE CASCADE,
+    FOREIGN KEY (id_permission) REFERENCES permission (id_permission) ON DELETE CASCADE
+);
+
+-- method is an HTTP method or '*'; path is an exact path or a prefix ending in '/*'
+CREATE TABLE IF NOT EXISTS api_key_route (
+    id_api_key INT NOT NULL,
+    method     VARCHAR(10) NOT NULL DEFAULT '*',
+    path       VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id_api_key, method, path),
+    FOREIGN KEY (id_api_key) REFERENCES api_key (id_api_key) ON DELETE CASCADE
+);
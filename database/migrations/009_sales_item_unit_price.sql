@@ -0,0 +1,6 @@
+-- Snapshot of the item price at the time of sale, so later price edits do not rewrite history.
+
+ALTER TABLE sales_item ADD COLUMN unit_price INT NOT NULL DEFAULT 0 AFTER qty;
+
+-- Best-effort backfill for sales recorded before the snapshot existed
+UPDATE sales_item SET unit_price = total_item_sales DIV qty WHERE qty > 0 AND unit_price = 0;
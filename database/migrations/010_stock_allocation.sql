@@ -0,0 +1,14 @@
+-- Stock deduction on sale: batches get an optional expiry date for first-expiry-first-out,
+-- and every sales line records the batches it drew its quantity from.
+
+ALTER TABLE item_batch ADD COLUMN expiry_date DATE NULL AFTER date_out;
+
+CREATE TABLE IF NOT EXISTS sales_item_batch (
+    id_sales_item INT NOT NULL,
+    id_batch      INT NOT NULL,
+    qty           INT NOT NULL,
+    PRIMARY KEY (id_sales_item, id_batch),
+    INDEX idx_sales_item_batch_batch (id_batch),
+    FOREIGN KEY (id_sales_item) REFERENCES sales_item (id_sales_item) ON DELETE CASCADE,
+    FOREIGN KEY (id_batch) REFERENCES item_batch (id_batch)
+);
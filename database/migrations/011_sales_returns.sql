@@ -0,0 +1,49 @@
+-- Returns are reversing documents linked to the original sale; the sale itself is never changed.
+-- Each returned line records the batches its quantity was restocked to, and member points
+-- are tied to the sale that earned them so a refund can reverse them.
+
+ALTER TABLE member_point ADD COLUMN id_sales INT NULL AFTER id_member;
+ALTER TABLE member_point ADD INDEX idx_member_point_sales (id_sales);
+
+CREATE TABLE IF NOT EXISTS sales_return (
+    id_return       INT AUTO_INCREMENT PRIMARY KEY,
+    id_sales        INT NOT NULL,
+    id_user         INT NOT NULL,
+    return_date     DATETIME NOT NULL,
+    reason          VARCHAR(255) NOT NULL DEFAULT '',
+    payment_method  VARCHAR(20) NOT NULL,
+    total_refund    INT NOT NULL,
+    points_reversed INT NOT NULL DEFAULT 0,
+    INDEX idx_sales_return_sales (id_sales),
+    FOREIGN KEY (id_sales) REFERENCES sales_basket (id_sales),
+    FOREIGN KEY (id_user) REFERENCES user (id_user)
+);
+
+CREATE TABLE IF NOT EXISTS sales_return_item (
+    id_return_item INT AUTO_INCREMENT PRIMARY KEY,
+    id_return      INT NOT NULL,
+    id_sales_item  INT NOT NULL,
+    id_item        INT NOT NULL,
+    qty            INT NOT NULL,
+    unit_price     INT NOT NULL,
+    total_refund   INT NOT NULL,
+    INDEX idx_sales_return_item_sales_item (id_sales_item),
+    FOREIGN KEY (id_return) REFERENCES sales_return (id_return) ON DELETE CASCADE,
+    FOREIGN KEY (id_sales_item) REFERENCES sales_item (id_sales_item)
+);
+
+CREATE TABLE IF NOT EXISTS sales_return_item_batch (
+    id_return_item INT NOT NULL,
+    id_batch       INT NOT NULL,
+    qty            INT NOT NULL,
+    PRIMARY KEY (id_return_item, id_batch),
+    FOREIGN KEY (id_return_item) REFERENCES sales_return_item (id_return_item) ON DELETE CASCADE,
+    FOREIGN KEY (id_batch) REFERENCES item_batch (id_batch)
+);
+
+INSERT IGNORE INTO permission (code, description) VALUES
+    ('sales.refund', 'Take back sold items and refund them');
+
+INSERT IGNORE INTO role_permission (id_role, id_permission)
+SELECT r.id_role, p.id_permission FROM role r JOIN permission p
+WHERE p.code = 'sales.refund' AND r.role_name IN ('supervisor', 'manager');
@@ -0,0 +1,11 @@
+-- Sale lifecycle: baskets are OPEN while being rung up, can be HELD and resumed on another
+-- register, and end COMPLETED, VOIDED or REFUNDED. Existing baskets were all paid in full.
+
+ALTER TABLE sales_basket
+    ADD COLUMN status           VARCHAR(20) NOT NULL DEFAULT 'COMPLETED' AFTER total_amount,
+    ADD COLUMN register         VARCHAR(40) NOT NULL DEFAULT '' AFTER status,
+    ADD COLUMN void_reason      VARCHAR(255) NOT NULL DEFAULT '' AFTER register,
+    ADD COLUMN voided_by        INT NULL AFTER void_reason,
+    ADD COLUMN void_approved_by INT NULL AFTER voided_by,
+    ADD COLUMN voided_at        DATETIME NULL AFTER void_approved_by,
+    ADD INDEX idx_sales_basket_status (status);
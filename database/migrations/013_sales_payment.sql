@@ -0,0 +1,22 @@
+-- Split tenders: a sale is settled by one or more payments. amount is what the tender paid
+-- towards the sale; for cash, tendered minus amount is the change handed back.
+-- Sales completed before this migration get a single payment of their full total.
+
+CREATE TABLE IF NOT EXISTS sales_payment (
+    id_payment     INT AUTO_INCREMENT PRIMARY KEY,
+    id_sales       INT NOT NULL,
+    payment_method VARCHAR(20) NOT NULL,
+    amount         INT NOT NULL,
+    tendered       INT NOT NULL,
+    change_due     INT NOT NULL DEFAULT 0,
+    reference      VARCHAR(64) NOT NULL DEFAULT '',
+    created_at     DATETIME NOT NULL,
+    INDEX idx_sales_payment_sales (id_sales),
+    FOREIGN KEY (id_sales) REFERENCES sales_basket (id_sales) ON DELETE CASCADE
+);
+
+INSERT INTO sales_payment (id_sales, payment_method, amount, tendered, change_due, created_at)
+SELECT id_sales, payment_method, total_amount, total_amount, 0, FROM_UNIXTIME(sales_date)
+FROM sales_basket
+WHERE status IN ('COMPLETED', 'REFUNDED')
+  AND id_sales NOT IN (SELECT id_sales FROM sales_payment);
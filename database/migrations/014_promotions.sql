@@ -0,0 +1,39 @@
+-- Promotions discount sales lines automatically at checkout. Every line keeps the discount it
+-- was given, and the promotions behind it are recorded per line for reporting.
+
+CREATE TABLE IF NOT EXISTS promotion (
+    id_promotion   INT AUTO_INCREMENT PRIMARY KEY,
+    promotion_name VARCHAR(100) NOT NULL,
+    type           VARCHAR(20) NOT NULL,
+    scope          VARCHAR(20) NOT NULL,
+    target_id      INT NOT NULL DEFAULT 0,
+    value          INT NOT NULL DEFAULT 0,
+    buy_qty        INT NOT NULL DEFAULT 0,
+    get_qty        INT NOT NULL DEFAULT 0,
+    min_spend      INT NOT NULL DEFAULT 0,
+    starts_at      DATETIME NULL,
+    ends_at        DATETIME NULL,
+    daily_from     VARCHAR(5) NOT NULL DEFAULT '',
+    daily_to       VARCHAR(5) NOT NULL DEFAULT '',
+    active         TINYINT(1) NOT NULL DEFAULT 1,
+    INDEX idx_promotion_active (active)
+);
+
+ALTER TABLE sales_item ADD COLUMN discount INT NOT NULL DEFAULT 0 AFTER unit_price;
+
+CREATE TABLE IF NOT EXISTS sales_item_promotion (
+    id_sales_item INT NOT NULL,
+    id_promotion  INT NOT NULL,
+    discount      INT NOT NULL,
+    PRIMARY KEY (id_sales_item, id_promotion),
+    INDEX idx_sales_item_promotion_promotion (id_promotion),
+    FOREIGN KEY (id_sales_item) REFERENCES sales_item (id_sales_item) ON DELETE CASCADE,
+    FOREIGN KEY (id_promotion) REFERENCES promotion (id_promotion)
+);
+
+INSERT IGNORE INTO permission (code, description) VALUES
+    ('promotions.manage', 'Create, change and end promotions');
+
+INSERT IGNORE INTO role_permission (id_role, id_permission)
+SELECT r.id_role, p.id_permission FROM role r JOIN permission p
+WHERE p.code = 'promotions.manage' AND r.role_name = 'manager';
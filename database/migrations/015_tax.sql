@@ -0,0 +1,35 @@
+-- Tax rates are assigned per category, and per item to override the category. Every sales
+-- line keeps the rate it was taxed at and the tax included in its total, and the sale keeps
+-- its tax total and whether its prices included tax. Returns record the tax they refund.
+
+CREATE TABLE IF NOT EXISTS tax_rate (
+    id_tax_rate INT AUTO_INCREMENT PRIMARY KEY,
+    tax_name    VARCHAR(50) NOT NULL,
+    rate        INT NOT NULL
+);
+
+ALTER TABLE category ADD COLUMN id_tax_rate INT NULL;
+ALTER TABLE category ADD FOREIGN KEY (id_tax_rate) REFERENCES tax_rate (id_tax_rate);
+
+ALTER TABLE item ADD COLUMN id_tax_rate INT NULL;
+ALTER TABLE item ADD FOREIGN KEY (id_tax_rate) REFERENCES tax_rate (id_tax_rate);
+
+ALTER TABLE sales_item ADD COLUMN id_tax_rate INT NULL AFTER discount;
+ALTER TABLE sales_item ADD COLUMN tax_rate INT NOT NULL DEFAULT 0 AFTER id_tax_rate;
+ALTER TABLE sales_item ADD COLUMN tax_amount INT NOT NULL DEFAULT 0 AFTER tax_rate;
+
+ALTER TABLE sales_basket ADD COLUMN tax_amount INT NOT NULL DEFAULT 0 AFTER total_amount;
+ALTER TABLE sales_basket ADD COLUMN tax_mode VARCHAR(10) NOT NULL DEFAULT 'INCLUSIVE' AFTER tax_amount;
+
+ALTER TABLE sales_return_item ADD COLUMN tax_refund INT NOT NULL DEFAULT 0 AFTER total_refund;
+
+INSERT INTO tax_rate (tax_name, rate)
+SELECT 'PPN', 1100 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM tax_rate);
+
+INSERT IGNORE INTO permission (code, description) VALUES
+    ('taxes.manage', 'Create and change tax rates');
+
+INSERT IGNORE INTO role_permission (id_role, id_permission)
+SELECT r.id_role, p.id_permission FROM role r JOIN permission p
+WHERE p.code = 'taxes.manage' AND r.role_name = 'manager';
@@ -0,0 +1,14 @@
+-- Every receipt printed for a sale is recorded, so reprints can be flagged as copies and
+-- traced to the user who made them.
+
+CREATE TABLE IF NOT EXISTS receipt_print (
+    id_print   INT AUTO_INCREMENT PRIMARY KEY,
+    id_sales   INT NOT NULL,
+    id_user    INT NOT NULL,
+    format     VARCHAR(10) NOT NULL,
+    is_copy    TINYINT(1) NOT NULL DEFAULT 0,
+    printed_at DATETIME NOT NULL,
+    INDEX idx_receipt_print_sales (id_sales),
+    FOREIGN KEY (id_sales) REFERENCES sales_basket (id_sales) ON DELETE CASCADE,
+    FOREIGN KEY (id_user) REFERENCES user (id_user)
+);
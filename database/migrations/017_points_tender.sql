@@ -0,0 +1,9 @@
+-- Member points can pay for part of a sale. A POINTS tender records the points it spent, and
+-- returns give back the matching share of them along with the part of the refund they cover.
+
+ALTER TABLE sales_payment
+    ADD COLUMN points INT NOT NULL DEFAULT 0;
+
+ALTER TABLE sales_return
+    ADD COLUMN points_restored INT NOT NULL DEFAULT 0,
+    ADD COLUMN points_refund   INT NOT NULL DEFAULT 0;
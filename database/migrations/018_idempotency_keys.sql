@@ -0,0 +1,15 @@
+-- Money-moving requests may carry an Idempotency-Key header. The first request with a key
+-- reserves it (status_code 0) and stores its response once answered, so a retry of the same
+-- request replays that response instead of creating a second sale. Keys are scoped per user.
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    id_user         INT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    route           VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64) NOT NULL,
+    status_code     INT NOT NULL DEFAULT 0,
+    response        MEDIUMTEXT NULL,
+    created_at      DATETIME NOT NULL,
+    PRIMARY KEY (id_user, idempotency_key),
+    INDEX idx_idempotency_key_created (created_at)
+);
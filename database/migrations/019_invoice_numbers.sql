@@ -0,0 +1,13 @@
+-- Completed sales get a human-readable invoice number such as STORE-01-20260302-0001.
+-- invoice_sequence holds the last number handed out in each series (the invoice number
+-- without its sequence); the row stays locked until the sale's transaction ends, so numbers
+-- are gap-free and never handed out twice, even with several registers checking out at once.
+
+CREATE TABLE IF NOT EXISTS invoice_sequence (
+    series      VARCHAR(128) NOT NULL PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+ALTER TABLE sales_basket
+    ADD COLUMN invoice_no VARCHAR(128) NULL AFTER id_sales,
+    ADD UNIQUE INDEX uq_sales_basket_invoice (invoice_no);
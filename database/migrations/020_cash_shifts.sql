@@ -0,0 +1,33 @@
+-- Till sessions: a cashier opens a shift with the float in the drawer and closes it with the
+-- cash counted at the end. Sales and returns are tied to the shift of the user who took or
+-- gave back the money, so the drawer can be reconciled per shift in the X and Z reports.
+
+CREATE TABLE IF NOT EXISTS cash_shift (
+    id_shift      INT AUTO_INCREMENT PRIMARY KEY,
+    id_user       INT NOT NULL,
+    register      VARCHAR(32) NOT NULL DEFAULT '',
+    status        VARCHAR(10) NOT NULL DEFAULT 'OPEN',
+    opening_float INT NOT NULL DEFAULT 0,
+    opened_at     DATETIME NOT NULL,
+    expected_cash INT NOT NULL DEFAULT 0,
+    counted_cash  INT NOT NULL DEFAULT 0,
+    closed_by     INT NULL,
+    closed_at     DATETIME NULL,
+    close_note    VARCHAR(255) NOT NULL DEFAULT '',
+    INDEX idx_cash_shift_user_status (id_user, status),
+    FOREIGN KEY (id_user) REFERENCES user (id_user),
+    FOREIGN KEY (closed_by) REFERENCES user (id_user)
+);
+
+ALTER TABLE sales_basket ADD COLUMN id_shift INT NULL AFTER id_user;
+ALTER TABLE sales_basket ADD INDEX idx_sales_basket_shift (id_shift);
+
+ALTER TABLE sales_return ADD COLUMN id_shift INT NULL AFTER id_user;
+ALTER TABLE sales_return ADD INDEX idx_sales_return_shift (id_shift);
+
+INSERT IGNORE INTO permission (code, description) VALUES
+    ('shifts.manage', 'View and close the shifts of other cashiers');
+
+INSERT IGNORE INTO role_permission (id_role, id_permission)
+SELECT r.id_role, p.id_permission FROM role r JOIN permission p
+WHERE p.code = 'shifts.manage' AND r.role_name = 'manager';
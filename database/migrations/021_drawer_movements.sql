@@ -0,0 +1,17 @@
+-- Cash put into or taken out of a shift's drawer outside of sales: change top-ups, petty cash
+-- purchases, safe drops. Each movement has a reason code and the user who moved the cash, and
+-- counts towards the cash the shift's drawer is expected to hold.
+
+CREATE TABLE IF NOT EXISTS drawer_movement (
+    id_movement INT AUTO_INCREMENT PRIMARY KEY,
+    id_shift    INT NOT NULL,
+    id_user     INT NOT NULL,
+    type        VARCHAR(10) NOT NULL,
+    reason      VARCHAR(32) NOT NULL,
+    amount      INT NOT NULL,
+    note        VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    INDEX idx_drawer_movement_shift (id_shift),
+    FOREIGN KEY (id_shift) REFERENCES cash_shift (id_shift),
+    FOREIGN KEY (id_user) REFERENCES user (id_user)
+);
@@ -0,0 +1,18 @@
+-- Items carry an optional SKU and any number of barcodes (EAN-13, UPC-A, Code128 or internal
+-- store labels) so cashiers can scan them instead of typing item IDs. A code identifies one
+-- item only: SKUs and barcodes are each unique, and the item repository also keeps an SKU from
+-- matching another item's barcode.
+
+ALTER TABLE item
+    ADD COLUMN sku VARCHAR(64) NULL AFTER item_name,
+    ADD UNIQUE INDEX uq_item_sku (sku);
+
+CREATE TABLE IF NOT EXISTS item_barcode (
+    id_barcode INT AUTO_INCREMENT PRIMARY KEY,
+    id_item    INT NOT NULL,
+    code       VARCHAR(64) NOT NULL,
+    type       VARCHAR(10) NOT NULL,
+    UNIQUE INDEX uq_item_barcode_code (code),
+    INDEX idx_item_barcode_item (id_item),
+    FOREIGN KEY (id_item) REFERENCES item (id_item) ON DELETE CASCADE
+);
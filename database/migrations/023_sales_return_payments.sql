@@ -0,0 +1,19 @@
+-- A refund is given back the ways the sale was paid: the money part of a return is split over
+-- the sale's tenders in proportion to what each paid, one row per method. Shift reports take
+-- their refunds per method from here, so the cash share of a split-tender refund counts against
+-- the drawer. Returns taken before this migration get one row for their whole money refund.
+
+CREATE TABLE IF NOT EXISTS sales_return_payment (
+    id_return_payment INT AUTO_INCREMENT PRIMARY KEY,
+    id_return         INT NOT NULL,
+    payment_method    VARCHAR(20) NOT NULL,
+    amount            INT NOT NULL,
+    INDEX idx_sales_return_payment_return (id_return),
+    FOREIGN KEY (id_return) REFERENCES sales_return (id_return) ON DELETE CASCADE
+);
+
+INSERT INTO sales_return_payment (id_return, payment_method, amount)
+SELECT id_return, payment_method, total_refund - points_refund
+FROM sales_return
+WHERE total_refund > points_refund
+  AND id_return NOT IN (SELECT id_return FROM sales_return_payment);
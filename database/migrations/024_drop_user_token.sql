@@ -0,0 +1,4 @@
+-- Login sessions (002_user_sessions.sql) hold every access and refresh token, so the single
+-- token column they replaced on the user row goes.
+
+ALTER TABLE user DROP COLUMN token;
@@ -0,0 +1,24 @@
+-- Voiding a paid sale hands its money back the ways it was paid, one row per tender, on the
+-- shift of the cashier voiding it. The sale stays in the takings of the shift that took the
+-- money and shift reports count these rows with the refunds, so a void on a later shift does
+-- not change the report of a shift already closed. Sales voided before this migration get their
+-- rows on their own shift, which keeps the reports of those shifts as they were.
+
+CREATE TABLE IF NOT EXISTS sales_void_payment (
+    id_void_payment INT AUTO_INCREMENT PRIMARY KEY,
+    id_sales        INT NOT NULL,
+    id_shift        INT NULL,
+    payment_method  VARCHAR(20) NOT NULL,
+    amount          INT NOT NULL,
+    INDEX idx_sales_void_payment_sales (id_sales),
+    INDEX idx_sales_void_payment_shift (id_shift),
+    FOREIGN KEY (id_sales) REFERENCES sales_basket (id_sales) ON DELETE CASCADE
+);
+
+INSERT INTO sales_void_payment (id_sales, id_shift, payment_method, amount)
+SELECT p.id_sales, s.id_shift, p.payment_method, p.amount
+FROM sales_payment p
+JOIN sales_basket s ON s.id_sales = p.id_sales
+WHERE s.status = 'VOIDED'
+  AND p.payment_method <> 'POINTS'
+  AND p.id_sales NOT IN (SELECT id_sales FROM sales_void_payment);
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// APIKeyResponse is the public representation of an API key; the key itself is never included
+type APIKeyResponse struct {
+	ID          int                   `json:"id_api_key"`
+	Name        string                `json:"key_name"`
+	Prefix      string                `json:"key_prefix"`
+	UserID      int                   `json:"id_user"`
+	CreatedBy   int                   `json:"created_by"`
+	CreatedAt   time.Time             `json:"created_at"`
+	LastUsedAt  *time.Time            `json:"last_used_at"`
+	ExpiresAt   *time.Time            `json:"expires_at"`
+	RevokedAt   *time.Time            `json:"revoked_at"`
+	Permissions []PermissionResponse  `json:"permissions"`
+	Routes      []APIKeyRouteResponse `json:"routes"`
+}
+
+// APIKeyRouteResponse is the public representation of an endpoint an API key may call
+type APIKeyRouteResponse struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// NewAPIKeyResponse maps an API key to its public representation
+func NewAPIKeyResponse(apiKey *model.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		UserID:      apiKey.UserID,
+		CreatedBy:   apiKey.CreatedBy,
+		CreatedAt:   apiKey.CreatedAt,
+		LastUsedAt:  optionalTime(apiKey.LastUsedAt),
+		ExpiresAt:   optionalTime(apiKey.ExpiresAt),
+		RevokedAt:   optionalTime(apiKey.RevokedAt),
+		Permissions: NewPermissionResponses(apiKey.Permissions),
+		Routes:      mapAll(apiKey.Routes, NewAPIKeyRouteResponse),
+	}
+}
+
+// NewAPIKeyResponses maps a list of API keys
+func NewAPIKeyResponses(apiKeys []model.APIKey) []APIKeyResponse {
+	return mapAll(apiKeys, NewAPIKeyResponse)
+}
+
+// NewAPIKeyRouteResponse maps an API key route to its public representation
+func NewAPIKeyRouteResponse(route *model.APIKeyRoute) APIKeyRouteResponse {
+	return APIKeyRouteResponse{
+		Method: route.Method,
+		Path:   route.Path,
+	}
+}
+
+// optionalTime returns nil for the zero time so unset timestamps serialize as null
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// DrawerMovementResponse is the public representation of cash moved through a drawer
+type DrawerMovementResponse struct {
+	ID        int                      `json:"id_movement"`
+	ShiftID   int                      `json:"id_shift"`
+	UserID    int                      `json:"id_user"`
+	Type      model.DrawerMovementType `json:"type"`
+	Reason    model.DrawerReason       `json:"reason"`
+	Amount    int                      `json:"amount"`
+	Note      string                   `json:"note,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+// NewDrawerMovementResponse maps a drawer movement to its public representation
+func NewDrawerMovementResponse(movement *model.DrawerMovement) DrawerMovementResponse {
+	return DrawerMovementResponse{
+		ID:        movement.ID,
+		ShiftID:   movement.ShiftID,
+		UserID:    movement.UserID,
+		Type:      movement.Type,
+		Reason:    movement.Reason,
+		Amount:    movement.Amount,
+		Note:      movement.Note,
+		CreatedAt: movement.CreatedAt,
+	}
+}
+
+// DrawerTotalResponse is the public representation of the movements of one type and reason
+type DrawerTotalResponse struct {
+	Type   model.DrawerMovementType `json:"type"`
+	Reason model.DrawerReason       `json:"reason"`
+	Count  int                      `json:"count"`
+	Amount int                      `json:"amount"`
+}
+
+// NewDrawerTotalResponse maps a drawer movement total to its public representation
+func NewDrawerTotalResponse(total *model.DrawerMovementTotal) DrawerTotalResponse {
+	return DrawerTotalResponse{
+		Type:   total.Type,
+		Reason: total.Reason,
+		Count:  total.Count,
+		Amount: total.Amount,
+	}
+}
+
+// DrawerMovementReportResponse lists the movements of a shift's drawer with their totals
+type DrawerMovementReportResponse struct {
+	ShiftID   int                      `json:"id_shift"`
+	Movements []DrawerMovementResponse `json:"movements"`
+	Totals    []DrawerTotalResponse    `json:"totals"`
+	PayIns    int                      `json:"pay_ins"`
+	PayOuts   int                      `json:"pay_outs"`
+	Drops     int                      `json:"drops"`
+	Net       int                      `json:"net"` // Cash the movements added to the drawer; negative when they took it out
+}
+
+// NewDrawerMovementReportResponse maps the movements of a shift's drawer and their totals
+func NewDrawerMovementReportResponse(shiftID int, movements []model.DrawerMovement, totals []model.DrawerMovementTotal) DrawerMovementReportResponse {
+	payIns, payOuts, drops := model.SumDrawerMovements(totals)
+	return DrawerMovementReportResponse{
+		ShiftID:   shiftID,
+		Movements: mapAll(movements, NewDrawerMovementResponse),
+		Totals:    mapAll(totals, NewDrawerTotalResponse),
+		PayIns:    payIns,
+		PayOuts:   payOuts,
+		Drops:     drops,
+		Net:       payIns - payOuts - drops,
+	}
+}
@@ -0,0 +1,16 @@
+// Package dto maps database models to the public JSON shapes returned by the API.
+//
+// Controllers never serialize model structs directly: every response goes through
+// one of the New*Response constructors, which copy only the fields that are safe
+// to expose. Password hashes, tokens and other internal columns have no field in
+// the response types, so they cannot leak even if a repository starts loading them.
+package dto
+
+// mapAll applies a response constructor to every element of a slice
+func mapAll[M any, R any](models []M, fn func(*M) R) []R {
+	responses := make([]R, 0, len(models))
+	for i := range models {
+		responses = append(responses, fn(&models[i]))
+	}
+	return responses
+}
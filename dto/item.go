@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// CategoryResponse is the public representation of a category
+type CategoryResponse struct {
+	ID        int    `json:"id_category"`
+	Name      string `json:"category_name"`
+	TaxRateID int    `json:"id_tax_rate,omitempty"`
+}
+
+// NewCategoryResponse maps a category to its public representation
+func NewCategoryResponse(category *model.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		TaxRateID: category.TaxRateID,
+	}
+}
+
+// NewCategoryResponses maps a list of categories
+func NewCategoryResponses(categories []model.Category) []CategoryResponse {
+	return mapAll(categories, NewCategoryResponse)
+}
+
+// ItemResponse is the public representation of an item
+type ItemResponse struct {
+	ID         int               `json:"id_item"`
+	CategoryID int               `json:"item_category"`
+	Name       string            `json:"item_name"`
+	SKU        string            `json:"sku,omitempty"`
+	Price      int               `json:"item_price"`
+	TaxRateID  int               `json:"id_tax_rate,omitempty"`
+	Barcodes   []BarcodeResponse `json:"barcodes,omitempty"`
+	Category   *CategoryResponse `json:"category,omitempty"`
+}
+
+// NewItemResponse maps an item to its public representation
+func NewItemResponse(item *model.Item) ItemResponse {
+	response := ItemResponse{
+		ID:         item.ID,
+		CategoryID: item.CategoryID,
+		Name:       item.Name,
+		SKU:        item.SKU,
+		Price:      item.Price,
+		TaxRateID:  item.TaxRateID,
+		Barcodes:   NewBarcodeResponses(item.Barcodes),
+	}
+	if item.Category != nil {
+		category := NewCategoryResponse(item.Category)
+		response.Category = &category
+	}
+	return response
+}
+
+// NewItemResponses maps a list of items
+func NewItemResponses(items []model.Item) []ItemResponse {
+	return mapAll(items, NewItemResponse)
+}
+
+// BarcodeResponse is the public representation of an item barcode
+type BarcodeResponse struct {
+	Code string            `json:"code"`
+	Type model.BarcodeType `json:"type"`
+}
+
+// NewBarcodeResponse maps an item barcode to its public representation
+func NewBarcodeResponse(barcode *model.ItemBarcode) BarcodeResponse {
+	return BarcodeResponse{
+		Code: barcode.Code,
+		Type: barcode.Type,
+	}
+}
+
+// NewBarcodeResponses maps a list of item barcodes
+func NewBarcodeResponses(barcodes []model.ItemBarcode) []BarcodeResponse {
+	return mapAll(barcodes, NewBarcodeResponse)
+}
+
+// ItemScanResponse is the item a scanned code resolved to, with its price and the quantity on hand
+type ItemScanResponse struct {
+	ItemResponse
+	Stock int `json:"stock"`
+}
+
+// NewItemScanResponse maps a scanned item and its stock
+func NewItemScanResponse(item *model.Item, stock int) ItemScanResponse {
+	return ItemScanResponse{
+		ItemResponse: NewItemResponse(item),
+		Stock:        stock,
+	}
+}
+
+// ItemBatchResponse is the public representation of an item batch
+type ItemBatchResponse struct {
+	ID         int        `json:"id_batch"`
+	ItemID     int        `json:"id_item"`
+	DateIn     time.Time  `json:"date_in"`
+	DateOut    time.Time  `json:"date_out"`
+	ExpiryDate *time.Time `json:"expiry_date"`
+	Qty        int        `json:"batch_qty"`
+}
+
+// NewItemBatchResponse maps an item batch to its public representation
+func NewItemBatchResponse(itemBatch *model.ItemBatch) ItemBatchResponse {
+	return ItemBatchResponse{
+		ID:         itemBatch.ID,
+		ItemID:     itemBatch.ItemID,
+		DateIn:     itemBatch.DateIn,
+		DateOut:    itemBatch.DateOut,
+		ExpiryDate: optionalTime(itemBatch.ExpiryDate),
+		Qty:        itemBatch.Qty,
+	}
+}
+
+// NewItemBatchResponses maps a list of item batches
+func NewItemBatchResponses(itemBatches []model.ItemBatch) []ItemBatchResponse {
+	return mapAll(itemBatches, NewItemBatchResponse)
+}
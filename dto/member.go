@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// MemberResponse is the public representation of a member
+type MemberResponse struct {
+	ID       int       `json:"id_member"`
+	Name     string    `json:"name_member"`
+	Phone    int       `json:"phone_member"`
+	JoinDate time.Time `json:"join_date"`
+	Points   int       `json:"member_point"`
+}
+
+// NewMemberResponse maps a member to its public representation
+func NewMemberResponse(member *model.Member) MemberResponse {
+	return MemberResponse{
+		ID:       member.ID,
+		Name:     member.Name,
+		Phone:    member.Phone,
+		JoinDate: member.JoinDate,
+		Points:   member.Points,
+	}
+}
+
+// NewMemberResponses maps a list of members
+func NewMemberResponses(members []model.Member) []MemberResponse {
+	return mapAll(members, NewMemberResponse)
+}
+
+// MemberPointResponse is the public representation of a member point transaction
+type MemberPointResponse struct {
+	ID       int             `json:"id_point"`
+	MemberID int             `json:"id_member"`
+	SalesID  int             `json:"id_sales,omitempty"`
+	Type     model.PointType `json:"type"`
+	Points   int             `json:"points"`
+}
+
+// NewMemberPointResponse maps a member point transaction to its public representation
+func NewMemberPointResponse(memberPoint *model.MemberPoint) MemberPointResponse {
+	return MemberPointResponse{
+		ID:       memberPoint.ID,
+		MemberID: memberPoint.MemberID,
+		SalesID:  memberPoint.SalesID,
+		Type:     memberPoint.Type,
+		Points:   memberPoint.Points,
+	}
+}
+
+// NewMemberPointResponses maps a list of member point transactions
+func NewMemberPointResponses(memberPoints []model.MemberPoint) []MemberPointResponse {
+	return mapAll(memberPoints, NewMemberPointResponse)
+}
+
+// UserMemberResponse is the public representation of a member access log entry
+type UserMemberResponse struct {
+	ID              int       `json:"id_log_member"`
+	MemberID        int       `json:"id_member"`
+	Date            time.Time `json:"date"`
+	IP              string    `json:"ip"`
+	PlatformBrowser string    `json:"platform_browser"`
+}
+
+// NewUserMemberResponse maps a member access log entry to its public representation
+func NewUserMemberResponse(userMember *model.UserMember) UserMemberResponse {
+	return UserMemberResponse{
+		ID:              userMember.ID,
+		MemberID:        userMember.MemberID,
+		Date:            userMember.Date,
+		IP:              userMember.IP,
+		PlatformBrowser: userMember.PlatformBrowser,
+	}
+}
+
+// NewUserMemberResponses maps a list of member access log entries
+func NewUserMemberResponses(userMembers []model.UserMember) []UserMemberResponse {
+	return mapAll(userMembers, NewUserMemberResponse)
+}
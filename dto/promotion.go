@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// PromotionResponse is the public representation of a promotion
+type PromotionResponse struct {
+	ID        int                  `json:"id_promotion"`
+	Name      string               `json:"promotion_name"`
+	Type      model.PromotionType  `json:"type"`
+	Scope     model.PromotionScope `json:"scope"`
+	TargetID  int                  `json:"target_id,omitempty"`
+	Value     int                  `json:"value"`
+	BuyQty    int                  `json:"buy_qty,omitempty"`
+	GetQty    int                  `json:"get_qty,omitempty"`
+	MinSpend  int                  `json:"min_spend"`
+	StartsAt  *time.Time           `json:"starts_at,omitempty"`
+	EndsAt    *time.Time           `json:"ends_at,omitempty"`
+	DailyFrom string               `json:"daily_from,omitempty"`
+	DailyTo   string               `json:"daily_to,omitempty"`
+	Active    bool                 `json:"active"`
+}
+
+// NewPromotionResponse maps a promotion to its public representation
+func NewPromotionResponse(promotion *model.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:        promotion.ID,
+		Name:      promotion.Name,
+		Type:      promotion.Type,
+		Scope:     promotion.Scope,
+		TargetID:  promotion.TargetID,
+		Value:     promotion.Value,
+		BuyQty:    promotion.BuyQty,
+		GetQty:    promotion.GetQty,
+		MinSpend:  promotion.MinSpend,
+		StartsAt:  optionalTime(promotion.StartsAt),
+		EndsAt:    optionalTime(promotion.EndsAt),
+		DailyFrom: promotion.DailyFrom,
+		DailyTo:   promotion.DailyTo,
+		Active:    promotion.Active,
+	}
+}
+
+// NewPromotionResponses maps a list of promotions
+func NewPromotionResponses(promotions []model.Promotion) []PromotionResponse {
+	return mapAll(promotions, NewPromotionResponse)
+}
+
+// PromotionUsageResponse is the public representation of what a promotion gave away over a period
+type PromotionUsageResponse struct {
+	PromotionID int    `json:"id_promotion"`
+	Name        string `json:"promotion_name"`
+	Sales       int    `json:"sales"`
+	Lines       int    `json:"lines"`
+	Discount    int    `json:"discount"`
+}
+
+// NewPromotionUsageResponse maps a promotion usage row to its public representation
+func NewPromotionUsageResponse(usage *model.PromotionUsage) PromotionUsageResponse {
+	return PromotionUsageResponse{
+		PromotionID: usage.PromotionID,
+		Name:        usage.Name,
+		Sales:       usage.Sales,
+		Lines:       usage.Lines,
+		Discount:    usage.Discount,
+	}
+}
+
+// NewPromotionUsageResponses maps a promotion usage report
+func NewPromotionUsageResponses(usage []model.PromotionUsage) []PromotionUsageResponse {
+	return mapAll(usage, NewPromotionUsageResponse)
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "go-pos/model"
+
+// RoleResponse is the public representation of a role
+type RoleResponse struct {
+	ID          int                  `json:"id_role"`
+	Name        string               `json:"role_name"`
+	Description string               `json:"description"`
+	Permissions []PermissionResponse `json:"permissions,omitempty"`
+}
+
+// NewRoleResponse maps a role to its public representation
+func NewRoleResponse(role *model.Role) RoleResponse {
+	response := RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+	}
+	if len(role.Permissions) > 0 {
+		response.Permissions = NewPermissionResponses(role.Permissions)
+	}
+	return response
+}
+
+// NewRoleResponses maps a list of roles
+func NewRoleResponses(roles []model.Role) []RoleResponse {
+	return mapAll(roles, NewRoleResponse)
+}
+
+// PermissionResponse is the public representation of a permission
+type PermissionResponse struct {
+	ID          int                  `json:"id_permission"`
+	Code        model.PermissionCode `json:"code"`
+	Description string               `json:"description"`
+}
+
+// NewPermissionResponse maps a permission to its public representation
+func NewPermissionResponse(permission *model.Permission) PermissionResponse {
+	return PermissionResponse{
+		ID:          permission.ID,
+		Code:        permission.Code,
+		Description: permission.Description,
+	}
+}
+
+// NewPermissionResponses maps a list of permissions
+func NewPermissionResponses(permissions []model.Permission) []PermissionResponse {
+	return mapAll(permissions, NewPermissionResponse)
+}
@@ -0,0 +1,253 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// SalesBasketResponse is the public representation of a sales basket
+type SalesBasketResponse struct {
+	ID             int                    `json:"id_sales"`
+	InvoiceNo      string                 `json:"invoice_no,omitempty"`
+	SalesDate      int                    `json:"sales_date"`
+	UserID         int                    `json:"id_user"`
+	ShiftID        int                    `json:"id_shift,omitempty"`
+	MemberID       int                    `json:"id_member"`
+	PaymentMethod  model.PaymentMethod    `json:"payment_method"`
+	Total          int                    `json:"total"`
+	TaxAmount      int                    `json:"tax_amount"`
+	TaxMode        model.TaxMode          `json:"tax_mode"`
+	Status         model.SalesStatus      `json:"status"`
+	Register       string                 `json:"register"`
+	VoidReason     string                 `json:"void_reason,omitempty"`
+	VoidedBy       int                    `json:"voided_by,omitempty"`
+	VoidApprovedBy int                    `json:"void_approved_by,omitempty"`
+	VoidedAt       *time.Time             `json:"voided_at,omitempty"`
+	Items          []SalesItemResponse    `json:"items,omitempty"`
+	Payments       []SalesPaymentResponse `json:"payments,omitempty"`
+	ChangeDue      int                    `json:"change_due,omitempty"`
+	PointsEarned   int                    `json:"points_earned,omitempty"`
+}
+
+// NewSalesBasketResponse maps a sales basket and its loaded items to their public representation
+func NewSalesBasketResponse(basket *model.SalesBasket) SalesBasketResponse {
+	response := SalesBasketResponse{
+		ID:             basket.ID,
+		InvoiceNo:      basket.InvoiceNo,
+		SalesDate:      basket.SalesDate,
+		UserID:         basket.UserID,
+		ShiftID:        basket.ShiftID,
+		MemberID:       basket.MemberID,
+		PaymentMethod:  basket.PaymentMethod,
+		Total:          basket.Total,
+		TaxAmount:      basket.TaxAmount,
+		TaxMode:        basket.TaxMode,
+		Status:         basket.Status,
+		Register:       basket.Register,
+		VoidReason:     basket.VoidReason,
+		VoidedBy:       basket.VoidedBy,
+		VoidApprovedBy: basket.VoidApprovedBy,
+		VoidedAt:       optionalTime(basket.VoidedAt),
+		PointsEarned:   basket.PointsEarned,
+	}
+	if len(basket.Items) > 0 {
+		response.Items = NewSalesItemResponses(basket.Items)
+	}
+	if len(basket.Payments) > 0 {
+		response.Payments = mapAll(basket.Payments, NewSalesPaymentResponse)
+	}
+	for _, payment := range basket.Payments {
+		response.ChangeDue += payment.Change
+	}
+	return response
+}
+
+// NewSalesBasketResponses maps a list of sales baskets
+func NewSalesBasketResponses(baskets []model.SalesBasket) []SalesBasketResponse {
+	return mapAll(baskets, NewSalesBasketResponse)
+}
+
+// SalesItemResponse is the public representation of a sales line
+type SalesItemResponse struct {
+	ID          int                        `json:"id_sales_item"`
+	SalesID     int                        `json:"id_sales"`
+	ItemID      int                        `json:"id_item"`
+	Qty         int                        `json:"qty"`
+	UnitPrice   int                        `json:"unit_price"`
+	Discount    int                        `json:"discount"`
+	TaxRate     int                        `json:"tax_rate"`
+	TaxAmount   int                        `json:"tax_amount"`
+	TotalAmount int                        `json:"total_item_sales"`
+	Batches     []SalesItemBatchResponse   `json:"batches,omitempty"`
+	Promotions  []AppliedPromotionResponse `json:"promotions,omitempty"`
+}
+
+// NewSalesItemResponse maps a sales line to its public representation
+func NewSalesItemResponse(item *model.SalesItem) SalesItemResponse {
+	response := SalesItemResponse{
+		ID:          item.ID,
+		SalesID:     item.SalesID,
+		ItemID:      item.ItemID,
+		Qty:         item.Qty,
+		UnitPrice:   item.UnitPrice,
+		Discount:    item.Discount,
+		TaxRate:     item.TaxRate,
+		TaxAmount:   item.TaxAmount,
+		TotalAmount: item.TotalAmount,
+	}
+	if len(item.Batches) > 0 {
+		response.Batches = mapAll(item.Batches, NewSalesItemBatchResponse)
+	}
+	if len(item.Promotions) > 0 {
+		response.Promotions = mapAll(item.Promotions, NewAppliedPromotionResponse)
+	}
+	return response
+}
+
+// NewSalesItemResponses maps a list of sales lines
+func NewSalesItemResponses(items []model.SalesItem) []SalesItemResponse {
+	return mapAll(items, NewSalesItemResponse)
+}
+
+// SalesItemBatchResponse is the public representation of the stock a sales line drew from a batch
+type SalesItemBatchResponse struct {
+	BatchID int `json:"id_batch"`
+	Qty     int `json:"qty"`
+}
+
+// NewSalesItemBatchResponse maps a batch allocation to its public representation
+func NewSalesItemBatchResponse(allocation *model.SalesItemBatch) SalesItemBatchResponse {
+	return SalesItemBatchResponse{
+		BatchID: allocation.BatchID,
+		Qty:     allocation.Qty,
+	}
+}
+
+// AppliedPromotionResponse is the public representation of the discount a promotion gave a sales line
+type AppliedPromotionResponse struct {
+	PromotionID int `json:"id_promotion"`
+	Discount    int `json:"discount"`
+}
+
+// NewAppliedPromotionResponse maps an applied promotion to its public representation
+func NewAppliedPromotionResponse(applied *model.SalesItemPromotion) AppliedPromotionResponse {
+	return AppliedPromotionResponse{
+		PromotionID: applied.PromotionID,
+		Discount:    applied.Discount,
+	}
+}
+
+// SalesReturnResponse is the public representation of a sales return
+type SalesReturnResponse struct {
+	ID             int                       `json:"id_return"`
+	SalesID        int                       `json:"id_sales"`
+	UserID         int                       `json:"id_user"`
+	ShiftID        int                       `json:"id_shift,omitempty"`
+	ReturnDate     time.Time                 `json:"return_date"`
+	Reason         string                    `json:"reason"`
+	PaymentMethod  model.PaymentMethod       `json:"payment_method"`
+	TotalRefund    int                       `json:"total_refund"`
+	PointsReversed int                       `json:"points_reversed"`
+	PointsRestored int                       `json:"points_restored,omitempty"`
+	PointsRefund   int                       `json:"points_refund,omitempty"`
+	Items          []SalesReturnItemResponse `json:"items,omitempty"`
+	Payments       []RefundPaymentResponse   `json:"payments,omitempty"`
+}
+
+// NewSalesReturnResponse maps a sales return and its loaded lines to their public representation
+func NewSalesReturnResponse(salesReturn *model.SalesReturn) SalesReturnResponse {
+	response := SalesReturnResponse{
+		ID:             salesReturn.ID,
+		SalesID:        salesReturn.SalesID,
+		UserID:         salesReturn.UserID,
+		ShiftID:        salesReturn.ShiftID,
+		ReturnDate:     salesReturn.ReturnDate,
+		Reason:         salesReturn.Reason,
+		PaymentMethod:  salesReturn.PaymentMethod,
+		TotalRefund:    salesReturn.TotalRefund,
+		PointsReversed: salesReturn.PointsReversed,
+		PointsRestored: salesReturn.PointsRestored,
+		PointsRefund:   salesReturn.PointsRefund,
+	}
+	if len(salesReturn.Items) > 0 {
+		response.Items = mapAll(salesReturn.Items, NewSalesReturnItemResponse)
+	}
+	if len(salesReturn.Payments) > 0 {
+		response.Payments = mapAll(salesReturn.Payments, NewRefundPaymentResponse)
+	}
+	return response
+}
+
+// RefundPaymentResponse is the part of a refund given back with one payment method
+type RefundPaymentResponse struct {
+	Method model.PaymentMethod `json:"payment_method"`
+	Amount int                 `json:"amount"`
+}
+
+// NewRefundPaymentResponse maps a refund payment to its public representation
+func NewRefundPaymentResponse(payment *model.SalesReturnPayment) RefundPaymentResponse {
+	return RefundPaymentResponse{
+		Method: payment.Method,
+		Amount: payment.Amount,
+	}
+}
+
+// NewSalesReturnResponses maps a list of sales returns
+func NewSalesReturnResponses(salesReturns []model.SalesReturn) []SalesReturnResponse {
+	return mapAll(salesReturns, NewSalesReturnResponse)
+}
+
+// SalesReturnItemResponse is the public representation of a returned sales line
+type SalesReturnItemResponse struct {
+	ID          int                      `json:"id_return_item"`
+	SalesItemID int                      `json:"id_sales_item"`
+	ItemID      int                      `json:"id_item"`
+	Qty         int                      `json:"qty"`
+	UnitPrice   int                      `json:"unit_price"`
+	TotalRefund int                      `json:"total_refund"`
+	TaxRefund   int                      `json:"tax_refund"`
+	Batches     []SalesItemBatchResponse `json:"batches,omitempty"`
+}
+
+// NewSalesReturnItemResponse maps a returned line and the batches it restocked to its public representation
+func NewSalesReturnItemResponse(item *model.SalesReturnItem) SalesReturnItemResponse {
+	response := SalesReturnItemResponse{
+		ID:          item.ID,
+		SalesItemID: item.SalesItemID,
+		ItemID:      item.ItemID,
+		Qty:         item.Qty,
+		UnitPrice:   item.UnitPrice,
+		TotalRefund: item.TotalRefund,
+		TaxRefund:   item.TaxRefund,
+	}
+	for _, batch := range item.Batches {
+		response.Batches = append(response.Batches, SalesItemBatchResponse{BatchID: batch.BatchID, Qty: batch.Qty})
+	}
+	return response
+}
+
+// SalesPaymentResponse is the public representation of a tender of a sale
+type SalesPaymentResponse struct {
+	ID        int                 `json:"id_payment"`
+	Method    model.PaymentMethod `json:"payment_method"`
+	Amount    int                 `json:"amount"`
+	Tendered  int                 `json:"tendered"`
+	Change    int                 `json:"change_due"`
+	Reference string              `json:"reference,omitempty"`
+	Points    int                 `json:"points,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// NewSalesPaymentResponse maps a tender to its public representation
+func NewSalesPaymentResponse(payment *model.SalesPayment) SalesPaymentResponse {
+	return SalesPaymentResponse{
+		ID:        payment.ID,
+		Method:    payment.Method,
+		Amount:    payment.Amount,
+		Tendered:  payment.Tendered,
+		Change:    payment.Change,
+		Reference: payment.Reference,
+		Points:    payment.Points,
+		CreatedAt: payment.CreatedAt,
+	}
+}
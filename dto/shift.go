@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// ShiftResponse is the public representation of a cashier shift
+type ShiftResponse struct {
+	ID           int               `json:"id_shift"`
+	UserID       int               `json:"id_user"`
+	Register     string            `json:"register"`
+	Status       model.ShiftStatus `json:"status"`
+	OpeningFloat int               `json:"opening_float"`
+	OpenedAt     time.Time         `json:"opened_at"`
+	ExpectedCash int               `json:"expected_cash,omitempty"`
+	CountedCash  int               `json:"counted_cash,omitempty"`
+	ClosedBy     int               `json:"closed_by,omitempty"`
+	ClosedAt     *time.Time        `json:"closed_at,omitempty"`
+	CloseNote    string            `json:"close_note,omitempty"`
+}
+
+// NewShiftResponse maps a shift to its public representation
+func NewShiftResponse(shift *model.Shift) ShiftResponse {
+	return ShiftResponse{
+		ID:           shift.ID,
+		UserID:       shift.UserID,
+		Register:     shift.Register,
+		Status:       shift.Status,
+		OpeningFloat: shift.OpeningFloat,
+		OpenedAt:     shift.OpenedAt,
+		ExpectedCash: shift.ExpectedCash,
+		CountedCash:  shift.CountedCash,
+		ClosedBy:     shift.ClosedBy,
+		ClosedAt:     optionalTime(shift.ClosedAt),
+		CloseNote:    shift.CloseNote,
+	}
+}
+
+// NewShiftResponses maps a list of shifts
+func NewShiftResponses(shifts []model.Shift) []ShiftResponse {
+	return mapAll(shifts, NewShiftResponse)
+}
+
+// PaymentTotalResponse is the public representation of what one payment method took or gave back
+type PaymentTotalResponse struct {
+	Method model.PaymentMethod `json:"payment_method"`
+	Count  int                 `json:"count"`
+	Amount int                 `json:"amount"`
+}
+
+// NewPaymentTotalResponse maps a payment method total to its public representation
+func NewPaymentTotalResponse(total *model.PaymentTotal) PaymentTotalResponse {
+	return PaymentTotalResponse{
+		Method: total.Method,
+		Count:  total.Count,
+		Amount: total.Amount,
+	}
+}
+
+// ShiftReportResponse is the public representation of an X or Z report
+type ShiftReportResponse struct {
+	Kind         model.ShiftReportKind  `json:"report"`
+	Shift        ShiftResponse          `json:"shift"`
+	GeneratedAt  time.Time              `json:"generated_at"`
+	Sales        int                    `json:"sales"`
+	Voided       int                    `json:"voided"`
+	Takings      []PaymentTotalResponse `json:"takings"`
+	Refunds      []PaymentTotalResponse `json:"refunds"`
+	Movements    []DrawerTotalResponse  `json:"movements"`
+	OpeningFloat int                    `json:"opening_float"`
+	CashSales    int                    `json:"cash_sales"`
+	CashRefunds  int                    `json:"cash_refunds"`
+	PayIns       int                    `json:"pay_ins"`
+	PayOuts      int                    `json:"pay_outs"`
+	Drops        int                    `json:"drops"`
+	ExpectedCash int                    `json:"expected_cash"`
+	CountedCash  *int                   `json:"counted_cash,omitempty"`
+	Difference   *int                   `json:"difference,omitempty"`
+}
+
+// NewShiftReportResponse maps a shift report to its public representation. Counted cash and the
+// difference only appear on Z reports, once the drawer has been counted.
+func NewShiftReportResponse(report *model.ShiftReport) ShiftReportResponse {
+	response := ShiftReportResponse{
+		Kind:         report.Kind,
+		Shift:        NewShiftResponse(&report.Shift),
+		GeneratedAt:  report.GeneratedAt,
+		Sales:        report.Sales,
+		Voided:       report.Voided,
+		Takings:      mapAll(report.Takings, NewPaymentTotalResponse),
+		Refunds:      mapAll(report.Refunds, NewPaymentTotalResponse),
+		Movements:    mapAll(report.Movements, NewDrawerTotalResponse),
+		OpeningFloat: report.Shift.OpeningFloat,
+		CashSales:    report.CashSales,
+		CashRefunds:  report.CashRefunds,
+		PayIns:       report.PayIns,
+		PayOuts:      report.PayOuts,
+		Drops:        report.Drops,
+		ExpectedCash: report.ExpectedCash,
+	}
+	if report.Kind == model.ShiftReportZ {
+		response.CountedCash = &report.CountedCash
+		response.Difference = &report.Difference
+	}
+	return response
+}
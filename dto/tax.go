@@ -0,0 +1,55 @@
+package dto
+
+import "go-pos/model"
+
+// TaxRateResponse is the public representation of a tax rate
+type TaxRateResponse struct {
+	ID   int    `json:"id_tax_rate"`
+	Name string `json:"tax_name"`
+	Rate int    `json:"rate"`
+}
+
+// NewTaxRateResponse maps a tax rate to its public representation
+func NewTaxRateResponse(taxRate *model.TaxRate) TaxRateResponse {
+	return TaxRateResponse{
+		ID:   taxRate.ID,
+		Name: taxRate.Name,
+		Rate: taxRate.Rate,
+	}
+}
+
+// NewTaxRateResponses maps a list of tax rates
+func NewTaxRateResponses(taxRates []model.TaxRate) []TaxRateResponse {
+	return mapAll(taxRates, NewTaxRateResponse)
+}
+
+// TaxSummaryResponse is the public representation of the tax charged and refunded at one rate
+type TaxSummaryResponse struct {
+	TaxRateID       int    `json:"id_tax_rate"`
+	Name            string `json:"tax_name"`
+	Rate            int    `json:"rate"`
+	Taxable         int    `json:"taxable"`
+	Tax             int    `json:"tax"`
+	RefundedTaxable int    `json:"refunded_taxable"`
+	RefundedTax     int    `json:"refunded_tax"`
+	NetTax          int    `json:"net_tax"`
+}
+
+// NewTaxSummaryResponse maps a tax summary row to its public representation
+func NewTaxSummaryResponse(summary *model.TaxSummary) TaxSummaryResponse {
+	return TaxSummaryResponse{
+		TaxRateID:       summary.TaxRateID,
+		Name:            summary.Name,
+		Rate:            summary.Rate,
+		Taxable:         summary.Taxable,
+		Tax:             summary.Tax,
+		RefundedTaxable: summary.RefundedTaxable,
+		RefundedTax:     summary.RefundedTax,
+		NetTax:          summary.Tax - summary.RefundedTax,
+	}
+}
+
+// NewTaxSummaryResponses maps a tax summary report
+func NewTaxSummaryResponses(summary []model.TaxSummary) []TaxSummaryResponse {
+	return mapAll(summary, NewTaxSummaryResponse)
+}
@@ -0,0 +1,153 @@
+package dto
+
+import (
+	"encoding/json"
+	"go-pos/model"
+	"time"
+)
+
+// UserResponse is the public representation of a staff user
+type UserResponse struct {
+	ID      int          `json:"id"`
+	NIK     int          `json:"nik"`
+	Name    string       `json:"name"`
+	Address string       `json:"address"`
+	Phone   int          `json:"phone"`
+	Gender  model.Gender `json:"gender"`
+	IsAdmin bool         `json:"admin"`
+}
+
+// NewUserResponse maps a user to its public representation
+func NewUserResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:      user.ID,
+		NIK:     user.NIK,
+		Name:    user.Name,
+		Address: user.Address,
+		Phone:   user.Phone,
+		Gender:  user.Gender,
+		IsAdmin: user.IsAdmin,
+	}
+}
+
+// NewUserResponses maps a list of users
+func NewUserResponses(users []model.User) []UserResponse {
+	return mapAll(users, NewUserResponse)
+}
+
+// SessionResponse is the public representation of a login session
+type SessionResponse struct {
+	ID               string    `json:"id_session"`
+	Device           string    `json:"device"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastSeenAt       time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	Current          bool      `json:"current"`
+}
+
+// NewSessionResponse maps a session to its public representation
+func NewSessionResponse(session *model.UserSession) SessionResponse {
+	return SessionResponse{
+		ID:               session.ID,
+		Device:           session.Device,
+		CreatedAt:        session.CreatedAt,
+		LastSeenAt:       session.LastSeenAt,
+		ExpiresAt:        session.ExpiresAt,
+		RefreshExpiresAt: session.RefreshExpiresAt,
+		Current:          session.Current,
+	}
+}
+
+// NewSessionResponses maps a list of sessions
+func NewSessionResponses(sessions []model.UserSession) []SessionResponse {
+	return mapAll(sessions, NewSessionResponse)
+}
+
+// UserLogResponse is the public representation of a user log entry
+type UserLogResponse struct {
+	ID              int       `json:"id_log_user"`
+	UserID          int       `json:"id_user"`
+	Date            time.Time `json:"date"`
+	IP              string    `json:"ip"`
+	PlatformBrowser string    `json:"platform_browser"`
+	Action          string    `json:"action"`
+}
+
+// NewUserLogResponse maps a user log entry to its public representation
+func NewUserLogResponse(userLog *model.UserLog) UserLogResponse {
+	return UserLogResponse{
+		ID:              userLog.ID,
+		UserID:          userLog.UserID,
+		Date:            userLog.Date,
+		IP:              userLog.IP,
+		PlatformBrowser: userLog.PlatformBrowser,
+		Action:          userLog.Action,
+	}
+}
+
+// NewUserLogResponses maps a list of user log entries
+func NewUserLogResponses(userLogs []model.UserLog) []UserLogResponse {
+	return mapAll(userLogs, NewUserLogResponse)
+}
+
+// LoginAttemptResponse is the public representation of a failed login counter
+type LoginAttemptResponse struct {
+	Key          string    `json:"attempt_key"`
+	Failures     int       `json:"failures"`
+	LastFailedAt time.Time `json:"last_failed_at"`
+	LockedUntil  time.Time `json:"locked_until"`
+}
+
+// NewLoginAttemptResponse maps a failed login counter to its public representation
+func NewLoginAttemptResponse(attempt *model.LoginAttempt) LoginAttemptResponse {
+	return LoginAttemptResponse{
+		Key:          attempt.Key,
+		Failures:     attempt.Failures,
+		LastFailedAt: attempt.LastFailedAt,
+		LockedUntil:  attempt.LockedUntil,
+	}
+}
+
+// NewLoginAttemptResponses maps a list of failed login counters
+func NewLoginAttemptResponses(attempts []model.LoginAttempt) []LoginAttemptResponse {
+	return mapAll(attempts, NewLoginAttemptResponse)
+}
+
+// AuditLogResponse is the public representation of an audit record
+type AuditLogResponse struct {
+	ID         int             `json:"id_audit"`
+	UserID     int             `json:"id_user"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"user_agent"`
+	Changes    json.RawMessage `json:"changes"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// NewAuditLogResponse maps an audit record to its public representation
+func NewAuditLogResponse(auditLog *model.AuditLog) AuditLogResponse {
+	changes := json.RawMessage(auditLog.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+
+	return AuditLogResponse{
+		ID:         auditLog.ID,
+		UserID:     auditLog.UserID,
+		EntityType: auditLog.EntityType,
+		EntityID:   auditLog.EntityID,
+		Action:     auditLog.Action,
+		IP:         auditLog.IP,
+		UserAgent:  auditLog.UserAgent,
+		Changes:    changes,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+}
+
+// NewAuditLogResponses maps a list of audit records
+func NewAuditLogResponses(auditLogs []model.AuditLog) []AuditLogResponse {
+	return mapAll(auditLogs, NewAuditLogResponse)
+}
@@ -0,0 +1,87 @@
+package filters
+
+import (
+	"encoding/json"
+	"go-pos/audit"
+	"go-pos/controllers"
+	"go-pos/model"
+	"go-pos/repository"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+// auditActions maps the mutating HTTP methods to their default audit action
+var auditActions = map[string]string{
+	http.MethodPost:   "CREATE",
+	http.MethodPut:    "UPDATE",
+	http.MethodDelete: "DELETE",
+}
+
+// Audit writes an audit_log record for every successful mutating /api call made by a staff user.
+// Handlers describe the change with BaseController.Audit; calls that do not are still recorded
+// against the entity named in the path, without a diff.
+func Audit(ctx *context.Context) {
+	action, ok := auditActions[ctx.Input.Method()]
+	if !ok {
+		return
+	}
+
+	status := ctx.ResponseWriter.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusBadRequest {
+		return
+	}
+
+	// A replayed response made no change of its own
+	if ctx.ResponseWriter.Header().Get(controllers.IdempotentReplayHeader) != "" {
+		return
+	}
+
+	user, ok := ctx.Input.GetData(controllers.CurrentUserKey).(*model.User)
+	if !ok || user == nil {
+		return
+	}
+
+	entry, ok := ctx.Input.GetData(controllers.AuditKey).(*controllers.AuditEntry)
+	if !ok || entry == nil {
+		entry = &controllers.AuditEntry{
+			EntityType: pathEntity(ctx.Input.URL()),
+			EntityID:   ctx.Input.Param(":id"),
+		}
+	}
+	if entry.Action != "" {
+		action = entry.Action
+	}
+
+	changes, err := audit.Diff(entry.Before, entry.After)
+	if err != nil {
+		changes = map[string]audit.Change{}
+	}
+	body, err := json.Marshal(changes)
+	if err != nil {
+		body = []byte("{}")
+	}
+
+	// Non-critical: the change has already been committed, so a failed audit write is not reported
+	repository.NewAuditLogRepository().CreateAuditLog(&model.AuditLog{
+		UserID:     user.ID,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     action,
+		IP:         ctx.Input.IP(),
+		UserAgent:  ctx.Input.UserAgent(),
+		Changes:    string(body),
+		CreatedAt:  time.Now(),
+	})
+}
+
+// pathEntity returns the resource segment of an /api path, e.g. "items" for /api/items/3
+func pathEntity(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api"), "/"), "/")
+	return segments[0]
+}
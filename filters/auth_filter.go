@@ -0,0 +1,141 @@
+package filters
+
+import (
+	"go-pos/config"
+	"go-pos/controllers"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+// publicRoutes lists the /api paths that can be called without a token
+var publicRoutes = map[string]bool{
+	"/api/auth/login":          true,
+	"/api/auth/login/verify":   true,
+	"/api/auth/refresh":        true,
+	"/api/auth/reset-password": true,
+}
+
+// twoFactorSetupRoutes stay reachable for admins who still have to enroll in mandatory two-factor login
+var twoFactorSetupRoutes = map[string]bool{
+	"/api/auth/2fa":         true,
+	"/api/auth/2fa/enroll":  true,
+	"/api/auth/2fa/confirm": true,
+	"/api/auth/logout":      true,
+}
+
+// isMemberRoute reports whether a path belongs to the member self-service API,
+// which is authenticated with member tokens instead of staff sessions
+func isMemberRoute(path string) bool {
+	return path == "/api/me" ||
+		strings.HasPrefix(path, "/api/me/") ||
+		strings.HasPrefix(path, "/api/member-auth/")
+}
+
+// Auth resolves the bearer token of every /api request to a user session.
+// Requests without a valid, unexpired session are rejected with 401 before they reach a controller.
+func Auth(ctx *context.Context) {
+	if publicRoutes[ctx.Input.URL()] || isMemberRoute(ctx.Input.URL()) {
+		return
+	}
+
+	token := controllers.BearerToken(ctx.Input.Header("Authorization"))
+	if key := ctx.Input.Header("X-API-Key"); key != "" {
+		token = key
+	}
+
+	// Integrations authenticate with API keys instead of sessions
+	if security.IsAPIKey(token) {
+		authenticateAPIKey(ctx, token)
+		return
+	}
+
+	if token == "" {
+		abort(ctx, http.StatusUnauthorized, "No authorization token provided")
+		return
+	}
+
+	sessionRepo := repository.NewUserSessionRepository()
+	session, err := sessionRepo.GetSessionByTokenHash(security.HashToken(token))
+	if err != nil {
+		abort(ctx, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		abort(ctx, http.StatusUnauthorized, "Session expired")
+		return
+	}
+
+	user, err := repository.NewUserRepository().GetUser(session.UserID)
+	if err != nil {
+		abort(ctx, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	// Sliding expiry: every authenticated call extends the session
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(config.GetSessionConfig().IdleTimeout)
+	sessionRepo.TouchSession(session.ID, session.LastSeenAt, session.ExpiresAt)
+
+	// Admins must enroll before they can use anything else when two-factor login is mandatory
+	if user.IsAdmin && config.GetTwoFactorPolicy().RequiredForAdmins && !twoFactorSetupRoutes[ctx.Input.URL()] {
+		enabled, err := repository.NewUserTOTPRepository().IsTOTPEnabled(user.ID)
+		if err != nil || !enabled {
+			abort(ctx, http.StatusForbidden, "Two-factor enrollment required")
+			return
+		}
+	}
+
+	ctx.Input.SetData(controllers.CurrentUserKey, user)
+	ctx.Input.SetData(controllers.CurrentSessionKey, session)
+}
+
+// authenticateAPIKey resolves an API key to the user it acts as.
+// Keys only reach the endpoints they are scoped to and never the session endpoints under /api/auth.
+func authenticateAPIKey(ctx *context.Context, key string) {
+	apiKeyRepo := repository.NewAPIKeyRepository()
+	apiKey, err := apiKeyRepo.GetAPIKeyByHash(security.HashToken(key))
+	if err != nil {
+		abort(ctx, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		abort(ctx, http.StatusUnauthorized, "API key revoked or expired")
+		return
+	}
+
+	path := ctx.Input.URL()
+	if strings.HasPrefix(path, "/api/auth/") || !apiKey.AllowsRoute(ctx.Input.Method(), path) {
+		abort(ctx, http.StatusForbidden, "API key is not allowed to call this endpoint")
+		return
+	}
+
+	user, err := repository.NewUserRepository().GetUser(apiKey.UserID)
+	if err != nil {
+		abort(ctx, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	apiKey.LastUsedAt = now
+	apiKeyRepo.TouchAPIKey(apiKey.ID, now)
+
+	ctx.Input.SetData(controllers.CurrentUserKey, user)
+	ctx.Input.SetData(controllers.CurrentAPIKeyKey, apiKey)
+}
+
+// abort writes a standard error response and stops the request
+func abort(ctx *context.Context, status int, message string) {
+	ctx.Output.SetStatus(status)
+	ctx.Output.JSON(controllers.Response{
+		Status:  status,
+		Message: message,
+	}, false, false)
+}
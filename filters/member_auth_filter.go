@@ -0,0 +1,38 @@
+package filters
+
+import (
+	"go-pos/controllers"
+	"go-pos/repository"
+	"go-pos/security"
+	"net/http"
+
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+// memberPublicRoutes lists the member API paths that can be called without a member token
+var memberPublicRoutes = map[string]bool{
+	"/api/member-auth/login": true,
+}
+
+// MemberAuth resolves the bearer token of member self-service requests to a member.
+// It ignores every path outside the member API, which Auth handles instead.
+// Staff session tokens are not accepted here, and member tokens are not accepted by Auth.
+func MemberAuth(ctx *context.Context) {
+	if !isMemberRoute(ctx.Input.URL()) || memberPublicRoutes[ctx.Input.URL()] {
+		return
+	}
+
+	token := controllers.BearerToken(ctx.Input.Header("Authorization"))
+	if token == "" {
+		abort(ctx, http.StatusUnauthorized, "No authorization token provided")
+		return
+	}
+
+	member, err := repository.NewMemberRepository().GetMemberByTokenHash(security.HashToken(token))
+	if err != nil {
+		abort(ctx, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	ctx.Input.SetData(controllers.CurrentMemberKey, member)
+}
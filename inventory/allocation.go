@@ -0,0 +1,97 @@
+// Package inventory decides which item batches a sale draws its stock from.
+package inventory
+
+import (
+	"fmt"
+	"go-pos/model"
+	"sort"
+)
+
+// Strategy selects the order in which batches are consumed
+type Strategy string
+
+const (
+	// FIFO consumes the batch received first
+	FIFO Strategy = "fifo"
+	// FEFO consumes the batch expiring first; batches without expiry go last
+	FEFO Strategy = "fefo"
+)
+
+// InsufficientStockError reports that an item does not have enough stock for a sale
+type InsufficientStockError struct {
+	ItemID    int
+	Requested int
+	Available int
+}
+
+// Error implements the error interface
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for item %d: %d requested, %d available", e.ItemID, e.Requested, e.Available)
+}
+
+// SortBatches orders batches in the sequence the strategy consumes them
+func SortBatches(batches []model.ItemBatch, strategy Strategy) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		a, b := batches[i], batches[j]
+
+		if strategy == FEFO && !a.ExpiryDate.Equal(b.ExpiryDate) {
+			switch {
+			case a.ExpiryDate.IsZero():
+				return false
+			case b.ExpiryDate.IsZero():
+				return true
+			default:
+				return a.ExpiryDate.Before(b.ExpiryDate)
+			}
+		}
+
+		if !a.DateIn.Equal(b.DateIn) {
+			return a.DateIn.Before(b.DateIn)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Allocate draws qty units of an item from batches, which must already be sorted.
+// Batches without stock are skipped. When the batches run out and allowNegative is set,
+// the shortfall is taken from the last batch, driving it negative; otherwise an
+// InsufficientStockError is returned. The batches are not modified.
+func Allocate(itemID int, batches []model.ItemBatch, qty int, allowNegative bool) ([]model.SalesItemBatch, error) {
+	var allocations []model.SalesItemBatch
+	remaining := qty
+	available := 0
+
+	for _, batch := range batches {
+		if batch.Qty <= 0 {
+			continue
+		}
+		available += batch.Qty
+		if remaining == 0 {
+			continue
+		}
+
+		take := batch.Qty
+		if take > remaining {
+			take = remaining
+		}
+		allocations = append(allocations, model.SalesItemBatch{BatchID: batch.ID, Qty: take})
+		remaining -= take
+	}
+
+	if remaining == 0 {
+		return allocations, nil
+	}
+
+	if !allowNegative || len(batches) == 0 {
+		return nil, &InsufficientStockError{ItemID: itemID, Requested: qty, Available: available}
+	}
+
+	last := batches[len(batches)-1].ID
+	if n := len(allocations); n > 0 && allocations[n-1].BatchID == last {
+		allocations[n-1].Qty += remaining
+	} else {
+		allocations = append(allocations, model.SalesItemBatch{BatchID: last, Qty: remaining})
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,29 @@
+package inventory
+
+import "go-pos/model"
+
+// Restock decides which batches take back qty returned units of a sales line.
+// Units go back to the batches the line drew them from, latest allocation first, and never
+// more to a batch than was drawn from it minus what earlier returns already put back
+// (restocked, keyed by batch ID). Units sold before stock was tracked per batch have no
+// allocation and are not restocked. The result has no ReturnItemID set.
+func Restock(drawn []model.SalesItemBatch, restocked map[int]int, qty int) []model.SalesReturnItemBatch {
+	var batches []model.SalesReturnItemBatch
+	remaining := qty
+
+	for i := len(drawn) - 1; i >= 0 && remaining > 0; i-- {
+		open := drawn[i].Qty - restocked[drawn[i].BatchID]
+		if open <= 0 {
+			continue
+		}
+
+		take := open
+		if take > remaining {
+			take = remaining
+		}
+		batches = append(batches, model.SalesReturnItemBatch{BatchID: drawn[i].BatchID, Qty: take})
+		remaining -= take
+	}
+
+	return batches
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey represents the api_key table in the database
+type APIKey struct {
+	ID         int       `json:"id_api_key" db:"id_api_key"`
+	Name       string    `json:"key_name" db:"key_name"`
+	Prefix     string    `json:"key_prefix" db:"key_prefix"`
+	KeyHash    string    `json:"-" db:"key_hash"`
+	UserID     int       `json:"id_user" db:"id_user"`
+	CreatedBy  int       `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt  time.Time `json:"revoked_at" db:"revoked_at"`
+
+	// Optional relation fields (not in database)
+	Permissions []Permission  `json:"permissions,omitempty" db:"-"`
+	Routes      []APIKeyRoute `json:"routes,omitempty" db:"-"`
+}
+
+// APIKeyRoute represents the api_key_route table in the database.
+// Method is an HTTP method or "*"; Path is an exact path or a prefix ending in "/*".
+type APIKeyRoute struct {
+	Method string `json:"method" db:"method"`
+	Path   string `json:"path" db:"path"`
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	if !k.RevokedAt.IsZero() {
+		return false
+	}
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
+
+// HasPermission reports whether the key has been granted the permission
+func (k *APIKey) HasPermission(code PermissionCode) bool {
+	for _, permission := range k.Permissions {
+		if permission.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRoute reports whether one of the key's routes covers the request
+func (k *APIKey) AllowsRoute(method, path string) bool {
+	for _, route := range k.Routes {
+		if route.Matches(method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the route covers the request method and path
+func (r APIKeyRoute) Matches(method, path string) bool {
+	if r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+
+	if base, ok := strings.CutSuffix(r.Path, "/*"); ok {
+		return path == base || strings.HasPrefix(path, base+"/")
+	}
+	return path == r.Path
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// AuditLog represents the audit_log table in the database
+type AuditLog struct {
+	ID         int       `json:"id_audit" db:"id_audit"`
+	UserID     int       `json:"id_user" db:"id_user"`
+	EntityType string    `json:"entity_type" db:"entity_type"`
+	EntityID   string    `json:"entity_id" db:"entity_id"`
+	Action     string    `json:"action" db:"action"`
+	IP         string    `json:"ip" db:"ip"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	Changes    string    `json:"changes" db:"changes"` // JSON object of field changes
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// AuditLogFilter narrows an audit log query; zero values are ignored
+type AuditLogFilter struct {
+	UserID     int
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
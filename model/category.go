@@ -2,6 +2,7 @@ package model
 
 // Category represents the category table in the database
 type Category struct {
-	ID   int    `json:"id_category" db:"id_category"`
-	Name string `json:"category_name" db:"category_name"`
+	ID        int    `json:"id_category" db:"id_category"`
+	Name      string `json:"category_name" db:"category_name"`
+	TaxRateID int    `json:"id_tax_rate" db:"id_tax_rate"` // Tax rate of the items in the category; 0 for untaxed
 }
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DrawerMovementType defines which way cash moved through a drawer outside of sales
+type DrawerMovementType string
+
+const (
+	DrawerPayIn  DrawerMovementType = "PAY_IN"  // Cash put into the drawer, e.g. a change top-up
+	DrawerPayOut DrawerMovementType = "PAY_OUT" // Cash paid out of the drawer, e.g. a petty cash purchase
+	DrawerDrop   DrawerMovementType = "DROP"    // Cash taken from the drawer to the safe
+)
+
+// DrawerReason is the reason code of a drawer movement
+type DrawerReason string
+
+const (
+	DrawerReasonChangeTopUp DrawerReason = "CHANGE_TOPUP"
+	DrawerReasonPettyCash   DrawerReason = "PETTY_CASH"
+	DrawerReasonSupplier    DrawerReason = "SUPPLIER_PAYMENT"
+	DrawerReasonSafeDrop    DrawerReason = "SAFE_DROP"
+	DrawerReasonCorrection  DrawerReason = "CORRECTION"
+	DrawerReasonOther       DrawerReason = "OTHER" // Needs a note
+)
+
+// drawerReasons lists the reason codes each movement type accepts
+var drawerReasons = map[DrawerMovementType][]DrawerReason{
+	DrawerPayIn:  {DrawerReasonChangeTopUp, DrawerReasonCorrection, DrawerReasonOther},
+	DrawerPayOut: {DrawerReasonPettyCash, DrawerReasonSupplier, DrawerReasonCorrection, DrawerReasonOther},
+	DrawerDrop:   {DrawerReasonSafeDrop},
+}
+
+// DrawerMovement represents the drawer_movement table in the database. It records cash put
+// into or taken out of the drawer of a shift other than through sales and refunds.
+type DrawerMovement struct {
+	ID        int                `json:"id_movement" db:"id_movement"`
+	ShiftID   int                `json:"id_shift" db:"id_shift"`
+	UserID    int                `json:"id_user" db:"id_user"` // User who moved the cash
+	Type      DrawerMovementType `json:"type" db:"type"`
+	Reason    DrawerReason       `json:"reason" db:"reason"`
+	Amount    int                `json:"amount" db:"amount"`
+	Note      string             `json:"note" db:"note"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+}
+
+// Validate checks the movement's type, reason code and amount
+func (m *DrawerMovement) Validate() error {
+	reasons, ok := drawerReasons[m.Type]
+	if !ok {
+		return fmt.Errorf("type must be %s, %s or %s", DrawerPayIn, DrawerPayOut, DrawerDrop)
+	}
+
+	valid := false
+	for _, reason := range reasons {
+		if m.Reason == reason {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("reason %q is not accepted for %s", m.Reason, m.Type)
+	}
+
+	if m.Reason == DrawerReasonOther && m.Note == "" {
+		return errors.New("a note is required for reason OTHER")
+	}
+	if m.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	return nil
+}
+
+// DrawerMovementTotal sums the movements of one type and reason on a shift
+type DrawerMovementTotal struct {
+	Type   DrawerMovementType `json:"type" db:"type"`
+	Reason DrawerReason       `json:"reason" db:"reason"`
+	Count  int                `json:"count" db:"count"`
+	Amount int                `json:"amount" db:"amount"`
+}
+
+// SumDrawerMovements returns the cash paid in, paid out and dropped across movement totals
+func SumDrawerMovements(totals []DrawerMovementTotal) (payIns, payOuts, drops int) {
+	for _, total := range totals {
+		switch total.Type {
+		case DrawerPayIn:
+			payIns += total.Amount
+		case DrawerPayOut:
+			payOuts += total.Amount
+		case DrawerDrop:
+			drops += total.Amount
+		}
+	}
+	return payIns, payOuts, drops
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyKey represents the idempotency_key table in the database. It keeps the response of
+// a money-moving request made with an Idempotency-Key header, so a retry with the same key gets
+// that response back instead of making the change twice.
+type IdempotencyKey struct {
+	UserID      int       `json:"id_user" db:"id_user"`
+	Key         string    `json:"idempotency_key" db:"idempotency_key"`
+	Route       string    `json:"route" db:"route"`               // Method and path the key was first used on
+	RequestHash string    `json:"request_hash" db:"request_hash"` // SHA-256 of the first request body
+	StatusCode  int       `json:"status_code" db:"status_code"`   // 0 while the first request is still running
+	Response    string    `json:"response" db:"response"`         // Body of the first request's response
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// IsPending reports whether the first request with the key has not been answered yet
+func (k *IdempotencyKey) IsPending() bool {
+	return k.StatusCode == 0
+}
+
+// Matches reports whether a request is a retry of the one the key was first used for
+func (k *IdempotencyKey) Matches(route, requestHash string) bool {
+	return k.Route == route && k.RequestHash == requestHash
+}
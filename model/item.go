@@ -6,7 +6,10 @@ type Item struct {
 	CategoryID int    `json:"item_category" db:"item_category"`
 	Name       string `json:"item_name" db:"item_name"`
 	Price      int    `json:"item_price" db:"item_price"`
+	TaxRateID  int    `json:"id_tax_rate" db:"id_tax_rate"` // Overrides the category tax rate when set
+	SKU        string `json:"sku" db:"sku"`                 // Empty when the item has no SKU
 	
 	// Optional relation field (not in database)
 	Category   *Category `json:"category,omitempty" db:"-"`
+	Barcodes   []ItemBarcode `json:"barcodes,omitempty" db:"-"` // Nil when not loaded; on update, nil keeps the saved barcodes
 }
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BarcodeType defines the symbology of an item barcode
+type BarcodeType string
+
+const (
+	BarcodeEAN13    BarcodeType = "EAN13"    // 13 digits, the last a check digit
+	BarcodeUPCA     BarcodeType = "UPCA"     // 12 digits, the last a check digit
+	BarcodeCode128  BarcodeType = "CODE128"  // Printable ASCII
+	BarcodeInternal BarcodeType = "INTERNAL" // Store-printed labels: letters, digits, '-' and '.'
+)
+
+// maxCodeLength is the longest SKU or barcode a column holds
+const maxCodeLength = 64
+
+// ItemBarcode represents the item_barcode table in the database. An item can carry several
+// barcodes, e.g. the manufacturer's EAN-13 and a store label; every code belongs to one item.
+type ItemBarcode struct {
+	ID     int         `json:"id_barcode" db:"id_barcode"`
+	ItemID int         `json:"id_item" db:"id_item"`
+	Code   string      `json:"code" db:"code"`
+	Type   BarcodeType `json:"type" db:"type"`
+}
+
+// Validate checks that the code is well formed for its type, including the check digit of
+// EAN-13 and UPC-A codes. Surrounding spaces are trimmed from the code first.
+func (b *ItemBarcode) Validate() error {
+	b.Code = strings.TrimSpace(b.Code)
+	if b.Code == "" {
+		return errors.New("barcode is required")
+	}
+	if len(b.Code) > maxCodeLength {
+		return fmt.Errorf("barcode %s is longer than %d characters", b.Code, maxCodeLength)
+	}
+
+	switch b.Type {
+	case BarcodeEAN13:
+		return validateGTIN(b.Code, 13)
+	case BarcodeUPCA:
+		return validateGTIN(b.Code, 12)
+	case BarcodeCode128:
+		for _, r := range b.Code {
+			if r < ' ' || r > '~' {
+				return fmt.Errorf("barcode %s has characters CODE128 cannot encode", b.Code)
+			}
+		}
+		return nil
+	case BarcodeInternal:
+		if !isCode(b.Code) {
+			return fmt.Errorf("barcode %s may only contain letters, digits, '-' and '.'", b.Code)
+		}
+		return nil
+	default:
+		return fmt.Errorf("barcode type must be %s, %s, %s or %s", BarcodeEAN13, BarcodeUPCA, BarcodeCode128, BarcodeInternal)
+	}
+}
+
+// ValidateCodes trims and checks the SKU and barcodes of an item, and that none of them is
+// given twice. Whether another item already uses them is left to the repository.
+func (i *Item) ValidateCodes() error {
+	i.SKU = strings.TrimSpace(i.SKU)
+	if len(i.SKU) > maxCodeLength {
+		return fmt.Errorf("SKU is longer than %d characters", maxCodeLength)
+	}
+	if i.SKU != "" && !isCode(i.SKU) {
+		return errors.New("SKU may only contain letters, digits, '-' and '.'")
+	}
+
+	seen := map[string]bool{i.SKU: i.SKU != ""}
+	for n := range i.Barcodes {
+		if err := i.Barcodes[n].Validate(); err != nil {
+			return err
+		}
+		if seen[i.Barcodes[n].Code] {
+			return fmt.Errorf("code %s is given more than once", i.Barcodes[n].Code)
+		}
+		seen[i.Barcodes[n].Code] = true
+	}
+	return nil
+}
+
+// ScanCodes returns the codes a scanned value may be stored under: the value itself and, for
+// UPC-A, its EAN-13 form with a leading zero or the other way round, since scanners report
+// UPC-A labels either way
+func ScanCodes(scanned string) []string {
+	code := strings.TrimSpace(scanned)
+	if code == "" {
+		return nil
+	}
+
+	codes := []string{code}
+	switch {
+	case len(code) == 12 && isDigits(code):
+		codes = append(codes, "0"+code)
+	case len(code) == 13 && code[0] == '0' && isDigits(code):
+		codes = append(codes, code[1:])
+	}
+	return codes
+}
+
+// validateGTIN checks the length, digits and check digit of an EAN-13 or UPC-A code
+func validateGTIN(code string, length int) error {
+	if len(code) != length || !isDigits(code) {
+		return fmt.Errorf("barcode %s must be %d digits", code, length)
+	}
+
+	// From the right, skipping the check digit, digits are weighted 3, 1, 3, ...
+	sum := 0
+	for n := length - 2; n >= 0; n-- {
+		digit := int(code[n] - '0')
+		if (length-2-n)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	if check := (10 - sum%10) % 10; int(code[length-1]-'0') != check {
+		return fmt.Errorf("barcode %s has a wrong check digit", code)
+	}
+	return nil
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isCode reports whether s consists of letters, digits, '-' and '.' only
+func isCode(s string) bool {
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
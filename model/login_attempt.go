@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// LoginAttempt represents the login_attempt table in the database
+type LoginAttempt struct {
+	Key          string    `json:"attempt_key" db:"attempt_key"`
+	Failures     int       `json:"failures" db:"failures"`
+	LastFailedAt time.Time `json:"last_failed_at" db:"last_failed_at"`
+	LockedUntil  time.Time `json:"locked_until" db:"locked_until"` // zero when not locked
+}
+
+// IsLocked reports whether the attempt key is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return now.Before(a.LockedUntil)
+}
+
+// RecentFailures returns the failure count, ignoring failures older than the window
+func (a *LoginAttempt) RecentFailures(now time.Time, window time.Duration) int {
+	if now.Sub(a.LastFailedAt) > window {
+		return 0
+	}
+	return a.Failures
+}
+
+// RegisterFailure counts a failed attempt and locks the key once maxFailures is reached
+func (a *LoginAttempt) RegisterFailure(now time.Time, window time.Duration, maxFailures int, lockout time.Duration) {
+	a.Failures = a.RecentFailures(now, window) + 1
+	a.LastFailedAt = now
+	if a.Failures >= maxFailures {
+		a.LockedUntil = now.Add(lockout)
+	}
+}
@@ -9,6 +9,6 @@ type Member struct {
 	Phone        int       `json:"phone_member" db:"phone_member"`
 	JoinDate     time.Time `json:"join_date" db:"join_date"`
 	Token        string    `json:"token" db:"token"`
-	PasswordHash string    `json:"password_hash" db:"password_hash"`
+	PasswordHash string    `json:"-" db:"password_hash"` // Never bound from or written to JSON
 	Points       int       `json:"member_point" db:"member_point(32)"`
 }
@@ -6,15 +6,40 @@ type PointType string
 const (
 	PointTypeEarned   PointType = "EARNED"
 	PointTypeRedeemed PointType = "REDEEMED"
+	PointTypeReversed PointType = "REVERSED" // Earned points taken back by a refund
+	PointTypeRestored PointType = "RESTORED" // Points spent on a sale given back by a void or refund
 )
 
+// Credits reports whether points of this type add to the member's balance
+func (t PointType) Credits() bool {
+	return t == PointTypeEarned || t == PointTypeRestored
+}
+
+// IsValid reports whether t is one of the known point types
+func (t PointType) IsValid() bool {
+	switch t {
+	case PointTypeEarned, PointTypeRedeemed, PointTypeReversed, PointTypeRestored:
+		return true
+	}
+	return false
+}
+
 // MemberPoint represents the member_point table in the database
 type MemberPoint struct {
 	ID       int       `json:"id_point" db:"id_point(32)"`
 	MemberID int       `json:"id_member" db:"id_member"`
+	SalesID  int       `json:"id_sales,omitempty" db:"id_sales"` // Sale the points belong to, 0 when not tied to one
 	Type     PointType `json:"type" db:"type"`
 	Points   int       `json:"points" db:"point(32)s"`
 	
 	// Optional relation field (not in database)
 	Member   *Member   `json:"member,omitempty" db:"-"`
 }
+
+// Delta returns how much the transaction changes the member's balance
+func (p *MemberPoint) Delta() int {
+	if p.Type.Credits() {
+		return p.Points
+	}
+	return -p.Points
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PasswordReset represents the password_reset table in the database
+type PasswordReset struct {
+	ID        int       `json:"id_reset" db:"id_reset"`
+	UserID    int       `json:"id_user" db:"id_user"`
+	CodeHash  string    `json:"-" db:"code_hash"`
+	CreatedBy int       `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
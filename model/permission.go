@@ -0,0 +1,22 @@
+package model
+
+// PermissionCode identifies an action that can be granted to a role
+type PermissionCode string
+
+const (
+	PermissionSalesVoid        PermissionCode = "sales.void"
+	PermissionSalesRefund      PermissionCode = "sales.refund"
+	PermissionItemsPriceEdit   PermissionCode = "items.price.edit"
+	PermissionUsersManage      PermissionCode = "users.manage"
+	PermissionReportsView      PermissionCode = "reports.view"
+	PermissionPromotionsManage PermissionCode = "promotions.manage"
+	PermissionTaxesManage      PermissionCode = "taxes.manage"
+	PermissionShiftsManage     PermissionCode = "shifts.manage"
+)
+
+// Permission represents the permission table in the database
+type Permission struct {
+	ID          int            `json:"id_permission" db:"id_permission"`
+	Code        PermissionCode `json:"code" db:"code"`
+	Description string         `json:"description" db:"description"`
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// PromotionType defines how a promotion discounts
+type PromotionType string
+
+const (
+	PromotionPercent  PromotionType = "PERCENT"     // Value percent off
+	PromotionFixed    PromotionType = "FIXED"       // Value off each unit, or off the basket once
+	PromotionBuyXGetY PromotionType = "BUY_X_GET_Y" // For every BuyQty units bought, GetQty more are free
+)
+
+// PromotionScope defines what a promotion applies to
+type PromotionScope string
+
+const (
+	PromotionScopeItem     PromotionScope = "ITEM"     // Lines of the item TargetID
+	PromotionScopeCategory PromotionScope = "CATEGORY" // Lines of items in the category TargetID
+	PromotionScopeBasket   PromotionScope = "BASKET"   // The whole basket
+)
+
+// dailyLayout is the format of the daily time window, e.g. "17:00"
+const dailyLayout = "15:04"
+
+// Promotion represents the promotion table in the database
+type Promotion struct {
+	ID        int            `json:"id_promotion" db:"id_promotion"`
+	Name      string         `json:"promotion_name" db:"promotion_name"`
+	Type      PromotionType  `json:"type" db:"type"`
+	Scope     PromotionScope `json:"scope" db:"scope"`
+	TargetID  int            `json:"target_id" db:"target_id"` // Item or category ID; unused for basket promotions
+	Value     int            `json:"value" db:"value"`
+	BuyQty    int            `json:"buy_qty" db:"buy_qty"`
+	GetQty    int            `json:"get_qty" db:"get_qty"`
+	MinSpend  int            `json:"min_spend" db:"min_spend"` // Basket subtotal needed before the promotion applies
+	StartsAt  time.Time      `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time      `json:"ends_at" db:"ends_at"`
+	DailyFrom string         `json:"daily_from" db:"daily_from"` // Optional time of day window, "HH:MM"
+	DailyTo   string         `json:"daily_to" db:"daily_to"`
+	Active    bool           `json:"active" db:"active"`
+}
+
+// SalesItemPromotion represents the sales_item_promotion table in the database.
+// It records the discount a promotion gave a sales line, for reporting.
+type SalesItemPromotion struct {
+	SalesItemID int `json:"id_sales_item" db:"id_sales_item"`
+	PromotionID int `json:"id_promotion" db:"id_promotion"`
+	Discount    int `json:"discount" db:"discount"`
+}
+
+// PromotionUsage sums up what a promotion gave away on paid sales over a period
+type PromotionUsage struct {
+	PromotionID int    `json:"id_promotion" db:"id_promotion"`
+	Name        string `json:"promotion_name" db:"promotion_name"`
+	Sales       int    `json:"sales" db:"sales"` // Number of sales the promotion applied to
+	Lines       int    `json:"lines" db:"lines"`
+	Discount    int    `json:"discount" db:"discount"`
+}
+
+// Validate checks that the promotion is complete and consistent
+func (p *Promotion) Validate() error {
+	if p.Name == "" {
+		return errors.New("promotion name is required")
+	}
+
+	switch p.Scope {
+	case PromotionScopeItem, PromotionScopeCategory:
+		if p.TargetID <= 0 {
+			return errors.New("item and category promotions need a target ID")
+		}
+	case PromotionScopeBasket:
+		if p.Type == PromotionBuyXGetY {
+			return errors.New("buy X get Y promotions apply to items or categories, not baskets")
+		}
+	default:
+		return errors.New("scope must be ITEM, CATEGORY or BASKET")
+	}
+
+	switch p.Type {
+	case PromotionPercent:
+		if p.Value <= 0 || p.Value > 100 {
+			return errors.New("percentage must be between 1 and 100")
+		}
+	case PromotionFixed:
+		if p.Value <= 0 {
+			return errors.New("fixed discount must be greater than zero")
+		}
+	case PromotionBuyXGetY:
+		if p.BuyQty <= 0 || p.GetQty <= 0 {
+			return errors.New("buy and get quantities must be greater than zero")
+		}
+	default:
+		return errors.New("type must be PERCENT, FIXED or BUY_X_GET_Y")
+	}
+
+	if p.MinSpend < 0 {
+		return errors.New("minimum spend cannot be negative")
+	}
+	if !p.StartsAt.IsZero() && !p.EndsAt.IsZero() && !p.EndsAt.After(p.StartsAt) {
+		return errors.New("promotion must end after it starts")
+	}
+
+	if (p.DailyFrom == "") != (p.DailyTo == "") {
+		return errors.New("daily window needs both a start and an end")
+	}
+	if p.DailyFrom != "" {
+		if _, err := time.Parse(dailyLayout, p.DailyFrom); err != nil {
+			return errors.New("daily window start must be HH:MM")
+		}
+		if _, err := time.Parse(dailyLayout, p.DailyTo); err != nil {
+			return errors.New("daily window end must be HH:MM")
+		}
+	}
+
+	return nil
+}
+
+// IsActiveAt reports whether the promotion runs at the given time: it is switched on, inside its
+// date range and, when it has one, inside its daily window. A window ending before it starts
+// runs overnight.
+func (p *Promotion) IsActiveAt(t time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if !p.StartsAt.IsZero() && t.Before(p.StartsAt) {
+		return false
+	}
+	if !p.EndsAt.IsZero() && !t.Before(p.EndsAt) {
+		return false
+	}
+	if p.DailyFrom == "" {
+		return true
+	}
+
+	from, err := time.Parse(dailyLayout, p.DailyFrom)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse(dailyLayout, p.DailyTo)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ReceiptFormat defines how a receipt is rendered
+type ReceiptFormat string
+
+const (
+	ReceiptFormatText   ReceiptFormat = "text"
+	ReceiptFormatESCPOS ReceiptFormat = "escpos"
+	ReceiptFormatPDF    ReceiptFormat = "pdf"
+)
+
+// IsValid reports whether the format is one receipts can be rendered in
+func (f ReceiptFormat) IsValid() bool {
+	return f == ReceiptFormatText || f == ReceiptFormatESCPOS || f == ReceiptFormatPDF
+}
+
+// ReceiptPrint represents the receipt_print table in the database.
+// Every print of a sale's receipt after the first is a copy.
+type ReceiptPrint struct {
+	ID        int           `json:"id_print" db:"id_print"`
+	SalesID   int           `json:"id_sales" db:"id_sales"`
+	UserID    int           `json:"id_user" db:"id_user"`
+	Format    ReceiptFormat `json:"format" db:"format"`
+	IsCopy    bool          `json:"is_copy" db:"is_copy"`
+	PrintedAt time.Time     `json:"printed_at" db:"printed_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// Role represents the role table in the database
+type Role struct {
+	ID          int    `json:"id_role" db:"id_role"`
+	Name        string `json:"role_name" db:"role_name"`
+	Description string `json:"description" db:"description"`
+
+	// Optional relation field (not in database)
+	Permissions []Permission `json:"permissions,omitempty" db:"-"`
+}
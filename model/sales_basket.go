@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // PaymentMethod defines the payment method type
 type PaymentMethod string
 
@@ -7,19 +9,82 @@ const (
 	PaymentMethodCash   PaymentMethod = "CASH"
 	PaymentMethodCredit PaymentMethod = "CREDIT"
 	PaymentMethodDebit  PaymentMethod = "DEBIT"
+	PaymentMethodPoints PaymentMethod = "POINTS" // Member loyalty points spent at the configured rate
+)
+
+// IsValid reports whether the payment method is one of the accepted tenders
+func (m PaymentMethod) IsValid() bool {
+	return m == PaymentMethodCash || m == PaymentMethodCredit || m == PaymentMethodDebit || m == PaymentMethodPoints
+}
+
+// NeedsReference reports whether a tender of this method must carry a card reference
+func (m PaymentMethod) NeedsReference() bool {
+	return m == PaymentMethodCredit || m == PaymentMethodDebit
+}
+
+// GivesChange reports whether a tender of this method may exceed what is due, the rest being handed back
+func (m PaymentMethod) GivesChange() bool {
+	return m == PaymentMethodCash
+}
+
+// SalesStatus defines where a sale is in its lifecycle
+type SalesStatus string
+
+const (
+	SalesStatusOpen      SalesStatus = "OPEN"      // Being rung up; lines can still change
+	SalesStatusHeld      SalesStatus = "HELD"      // Parked to be resumed later, possibly on another register
+	SalesStatusCompleted SalesStatus = "COMPLETED" // Paid; stock has been deducted
+	SalesStatusVoided    SalesStatus = "VOIDED"    // Cancelled with a reason and supervisor approval
+	SalesStatusRefunded  SalesStatus = "REFUNDED"  // Every line has been returned
 )
 
+// IsValid reports whether the status is one of the known sale statuses
+func (s SalesStatus) IsValid() bool {
+	switch s {
+	case SalesStatusOpen, SalesStatusHeld, SalesStatusCompleted, SalesStatusVoided, SalesStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// IsDraft reports whether the sale has not been completed yet, so no stock or payment is tied to it
+func (s SalesStatus) IsDraft() bool {
+	return s == SalesStatusOpen || s == SalesStatusHeld
+}
+
 // SalesBasket represents the sales_basket table in the database
 type SalesBasket struct {
 	ID            int           `json:"id_sales" db:"id_sales"`
+	InvoiceNo     string        `json:"invoice_no" db:"invoice_no"` // Set when the sale is completed
 	SalesDate     int           `json:"sales_date" db:"sales_date"` // This might need to be a time.Time depending on actual usage
 	UserID        int           `json:"id_user" db:"id_user"`
+	ShiftID       int           `json:"id_shift" db:"id_shift"` // Shift of the cashier who took the payment
 	MemberID      int           `json:"id_member" db:"id_member"`
-	PaymentMethod PaymentMethod `json:"payment_method" db:"payment_method"`
+	PaymentMethod PaymentMethod `json:"payment_method" db:"payment_method"` // Tender that paid the largest part
 	Total         int           `json:"total" db:"total"`
+	TaxAmount     int           `json:"tax_amount" db:"tax_amount"` // Tax included in the total
+	TaxMode       TaxMode       `json:"tax_mode" db:"tax_mode"`     // Whether the prices rung up included tax
+	Status        SalesStatus   `json:"status" db:"status"`
+	Register      string        `json:"register" db:"register"` // Register the basket is currently rung up on
+	
+	// Void details, set once the sale is voided
+	VoidReason     string    `json:"void_reason,omitempty" db:"void_reason"`
+	VoidedBy       int       `json:"voided_by,omitempty" db:"voided_by"`
+	VoidApprovedBy int       `json:"void_approved_by,omitempty" db:"void_approved_by"`
+	VoidedAt       time.Time `json:"voided_at,omitempty" db:"voided_at"`
 	
 	// Optional relation fields (not in database)
 	User          *User         `json:"user,omitempty" db:"-"`
 	Member        *Member       `json:"member,omitempty" db:"-"`
 	Items         []SalesItem   `json:"items,omitempty" db:"-"`
+	Payments      []SalesPayment `json:"payments,omitempty" db:"-"`
+	PointsEarned  int            `json:"points_earned,omitempty" db:"-"` // Set when the sale is completed
+}
+
+// SalesBasketFilter narrows a sales basket query; zero values are ignored
+type SalesBasketFilter struct {
+	UserID   int
+	MemberID int
+	Status   SalesStatus
+	Invoice  string // Invoice number or its leading part
 }
@@ -6,9 +6,16 @@ type SalesItem struct {
 	SalesID     int `json:"id_sales" db:"id_sales"`
 	ItemID      int `json:"id_item" db:"id_item"`
 	Qty         int `json:"qty" db:"qty"`
-	TotalAmount int `json:"total_item_sales" db:"total_item_sales"`
+	UnitPrice   int `json:"unit_price" db:"unit_price"` // Item price at the time of sale
+	Discount    int `json:"discount" db:"discount"`     // Promotions taken off the line
+	TaxRateID   int `json:"id_tax_rate" db:"id_tax_rate"`
+	TaxRate     int `json:"tax_rate" db:"tax_rate"`     // Hundredths of a percent at the time of sale
+	TaxAmount   int `json:"tax_amount" db:"tax_amount"` // Tax included in the line total
+	TotalAmount int `json:"total_item_sales" db:"total_item_sales"` // Unit price times quantity, less the discount, with tax
 	
 	// Optional relation fields (not in database)
-	Sales       *SalesBasket `json:"sales,omitempty" db:"-"`
-	Item        *Item        `json:"item,omitempty" db:"-"`
+	Sales       *SalesBasket     `json:"sales,omitempty" db:"-"`
+	Item        *Item            `json:"item,omitempty" db:"-"`
+	Batches     []SalesItemBatch `json:"batches,omitempty" db:"-"`
+	Promotions  []SalesItemPromotion `json:"promotions,omitempty" db:"-"`
 }
@@ -0,0 +1,9 @@
+package model
+
+// SalesItemBatch represents the sales_item_batch table in the database.
+// It records how much of a sales line was drawn from each item batch.
+type SalesItemBatch struct {
+	SalesItemID int `json:"id_sales_item" db:"id_sales_item"`
+	BatchID     int `json:"id_batch" db:"id_batch"`
+	Qty         int `json:"qty" db:"qty"`
+}
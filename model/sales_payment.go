@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// SalesPayment represents the sales_payment table in the database.
+// A sale may be settled with several tenders, e.g. part cash and part debit.
+type SalesPayment struct {
+	ID        int           `json:"id_payment" db:"id_payment"`
+	SalesID   int           `json:"id_sales" db:"id_sales"`
+	Method    PaymentMethod `json:"payment_method" db:"payment_method"`
+	Amount    int           `json:"amount" db:"amount"`         // Part of the sale total this tender paid
+	Tendered  int           `json:"tendered" db:"tendered"`     // What the customer handed over
+	Change    int           `json:"change_due" db:"change_due"` // Cash handed back, tendered minus amount
+	Reference string        `json:"reference" db:"reference"`   // Card approval or transaction number
+	Points    int           `json:"points" db:"points"`         // Member points a POINTS tender spends
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// SalesVoidPayment represents the sales_void_payment table in the database: the money a void
+// of a paid sale handed back with one payment method, on the shift of the voiding cashier.
+// Points tenders are given back as points and have no row.
+type SalesVoidPayment struct {
+	ID      int           `json:"id_void_payment" db:"id_void_payment"`
+	SalesID int           `json:"id_sales" db:"id_sales"`
+	ShiftID int           `json:"id_shift" db:"id_shift"`
+	Method  PaymentMethod `json:"payment_method" db:"payment_method"`
+	Amount  int           `json:"amount" db:"amount"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// SalesReturn represents the sales_return table in the database.
+// A return is a refund document linked to the original sale, which itself stays unchanged.
+type SalesReturn struct {
+	ID             int           `json:"id_return" db:"id_return"`
+	SalesID        int           `json:"id_sales" db:"id_sales"`
+	UserID         int           `json:"id_user" db:"id_user"`
+	ShiftID        int           `json:"id_shift" db:"id_shift"` // Shift whose drawer paid the refund
+	ReturnDate     time.Time     `json:"return_date" db:"return_date"`
+	Reason         string        `json:"reason" db:"reason"`
+	PaymentMethod  PaymentMethod `json:"payment_method" db:"payment_method"` // Main method of the sale; Payments has the split
+	TotalRefund    int           `json:"total_refund" db:"total_refund"`
+	PointsReversed int           `json:"points_reversed" db:"points_reversed"`
+	PointsRestored int           `json:"points_restored" db:"points_restored"` // Spent points given back to the member
+	PointsRefund   int           `json:"points_refund" db:"points_refund"`     // Part of the total refund given back as points
+
+	// Optional relation fields (not in database)
+	Items    []SalesReturnItem    `json:"items,omitempty" db:"-"`
+	Payments []SalesReturnPayment `json:"payments,omitempty" db:"-"`
+}
+
+// SalesReturnItem represents the sales_return_item table in the database
+type SalesReturnItem struct {
+	ID          int `json:"id_return_item" db:"id_return_item"`
+	ReturnID    int `json:"id_return" db:"id_return"`
+	SalesItemID int `json:"id_sales_item" db:"id_sales_item"`
+	ItemID      int `json:"id_item" db:"id_item"`
+	Qty         int `json:"qty" db:"qty"`
+	UnitPrice   int `json:"unit_price" db:"unit_price"`
+	TotalRefund int `json:"total_refund" db:"total_refund"`
+	TaxRefund   int `json:"tax_refund" db:"tax_refund"` // Tax included in the refund
+
+	// Optional relation fields (not in database)
+	Batches []SalesReturnItemBatch `json:"batches,omitempty" db:"-"`
+}
+
+// SalesReturnItemBatch represents the sales_return_item_batch table in the database.
+// It records how much of a returned line was put back into each item batch.
+type SalesReturnItemBatch struct {
+	ReturnItemID int `json:"id_return_item" db:"id_return_item"`
+	BatchID      int `json:"id_batch" db:"id_batch"`
+	Qty          int `json:"qty" db:"qty"`
+}
+
+// SalesReturnPayment represents the sales_return_payment table in the database.
+// It records how much of the money part of a refund was given back with one payment method.
+type SalesReturnPayment struct {
+	ID       int           `json:"id_return_payment" db:"id_return_payment"`
+	ReturnID int           `json:"id_return" db:"id_return"`
+	Method   PaymentMethod `json:"payment_method" db:"payment_method"`
+	Amount   int           `json:"amount" db:"amount"`
+}
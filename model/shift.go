@@ -0,0 +1,112 @@
+package model
+
+import "time"
+
+// ShiftStatus defines whether a till session is still taking payments
+type ShiftStatus string
+
+const (
+	ShiftStatusOpen   ShiftStatus = "OPEN"
+	ShiftStatusClosed ShiftStatus = "CLOSED" // Cash counted and the Z report taken
+)
+
+// ShiftReportKind tells a mid-shift snapshot from the report that closes a shift
+type ShiftReportKind string
+
+const (
+	ShiftReportX ShiftReportKind = "X" // Snapshot; the shift stays open
+	ShiftReportZ ShiftReportKind = "Z" // Final; the shift is closed with the counted cash
+)
+
+// Shift represents the cash_shift table in the database. A cashier opens a shift with the
+// float in the drawer; the sales they take payment for are tied to it until they count the
+// drawer and close it.
+type Shift struct {
+	ID           int         `json:"id_shift" db:"id_shift"`
+	UserID       int         `json:"id_user" db:"id_user"`
+	Register     string      `json:"register" db:"register"`
+	Status       ShiftStatus `json:"status" db:"status"`
+	OpeningFloat int         `json:"opening_float" db:"opening_float"`
+	OpenedAt     time.Time   `json:"opened_at" db:"opened_at"`
+
+	// Close details, set by the Z report
+	ExpectedCash int       `json:"expected_cash" db:"expected_cash"`
+	CountedCash  int       `json:"counted_cash" db:"counted_cash"`
+	ClosedBy     int       `json:"closed_by,omitempty" db:"closed_by"`
+	ClosedAt     time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	CloseNote    string    `json:"close_note" db:"close_note"`
+}
+
+// IsOpen reports whether the shift still takes payments
+func (s *Shift) IsOpen() bool {
+	return s.Status == ShiftStatusOpen
+}
+
+// ShiftFilter narrows a shift query; zero values are ignored
+type ShiftFilter struct {
+	UserID int
+	Status ShiftStatus
+}
+
+// PaymentTotal sums what one payment method took or gave back
+type PaymentTotal struct {
+	Method PaymentMethod `json:"payment_method" db:"payment_method"`
+	Count  int           `json:"count" db:"count"`
+	Amount int           `json:"amount" db:"amount"`
+}
+
+// ShiftReport reconciles the cash drawer of a shift against the payments it took and the cash
+// moved in and out of it
+type ShiftReport struct {
+	Kind        ShiftReportKind
+	Shift       Shift
+	GeneratedAt time.Time
+	Sales       int // Sales paid on the shift and not voided
+	Voided      int
+	Takings     []PaymentTotal // What the sales were paid with, per method
+	Refunds     []PaymentTotal // What returns and voids on the shift gave back, per method
+	Movements   []DrawerMovementTotal
+
+	CashSales    int
+	CashRefunds  int
+	PayIns       int
+	PayOuts      int
+	Drops        int
+	ExpectedCash int // Opening float plus cash taken and paid in, less cash refunded, paid out and dropped
+	CountedCash  int // Only known once the drawer is counted for the Z report
+	Difference   int // Counted less expected; negative when the drawer is short
+}
+
+// NewShiftReport works out the cash a shift's drawer should hold from its takings, refunds and
+// drawer movements. A Z report compares it with the cash counted at close.
+func NewShiftReport(kind ShiftReportKind, shift *Shift, takings, refunds []PaymentTotal, movements []DrawerMovementTotal, now time.Time) *ShiftReport {
+	report := &ShiftReport{
+		Kind:        kind,
+		Shift:       *shift,
+		GeneratedAt: now,
+		Takings:     takings,
+		Refunds:     refunds,
+		Movements:   movements,
+	}
+
+	for _, total := range takings {
+		if total.Method == PaymentMethodCash {
+			report.CashSales += total.Amount
+		}
+	}
+	for _, total := range refunds {
+		if total.Method == PaymentMethodCash {
+			report.CashRefunds += total.Amount
+		}
+	}
+	report.PayIns, report.PayOuts, report.Drops = SumDrawerMovements(movements)
+	report.ExpectedCash = shift.OpeningFloat + report.CashSales - report.CashRefunds +
+		report.PayIns - report.PayOuts - report.Drops
+
+	if kind == ShiftReportZ {
+		report.Shift.ExpectedCash = report.ExpectedCash
+		report.CountedCash = shift.CountedCash
+		report.Difference = shift.CountedCash - report.ExpectedCash
+	}
+	return report
+}
@@ -0,0 +1,47 @@
+package model
+
+import "errors"
+
+// TaxMode defines whether prices already include tax
+type TaxMode string
+
+const (
+	TaxInclusive TaxMode = "INCLUSIVE" // Prices include tax; the tax is the part of the price it makes up
+	TaxExclusive TaxMode = "EXCLUSIVE" // Tax is added on top of prices
+)
+
+// IsValid reports whether the tax mode is one of the known modes
+func (m TaxMode) IsValid() bool {
+	return m == TaxInclusive || m == TaxExclusive
+}
+
+// TaxRate represents the tax_rate table in the database.
+// Items take the rate assigned to them, or else the rate of their category; items with
+// neither are not taxed.
+type TaxRate struct {
+	ID   int    `json:"id_tax_rate" db:"id_tax_rate"`
+	Name string `json:"tax_name" db:"tax_name"`
+	Rate int    `json:"rate" db:"rate"` // Hundredths of a percent, e.g. 1100 for 11%
+}
+
+// Validate checks that the tax rate is complete and within range
+func (t *TaxRate) Validate() error {
+	if t.Name == "" {
+		return errors.New("tax name is required")
+	}
+	if t.Rate < 0 || t.Rate > 10000 {
+		return errors.New("rate must be between 0 and 10000 hundredths of a percent")
+	}
+	return nil
+}
+
+// TaxSummary sums up the tax charged and refunded at one rate over a period
+type TaxSummary struct {
+	TaxRateID       int    `json:"id_tax_rate" db:"id_tax_rate"`
+	Name            string `json:"tax_name" db:"tax_name"`
+	Rate            int    `json:"rate" db:"rate"`
+	Taxable         int    `json:"taxable" db:"taxable"` // Sales before tax
+	Tax             int    `json:"tax" db:"tax"`
+	RefundedTaxable int    `json:"refunded_taxable" db:"refunded_taxable"`
+	RefundedTax     int    `json:"refunded_tax" db:"refunded_tax"`
+}
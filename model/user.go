@@ -18,5 +18,4 @@ type User struct {
 	Gender       Gender `json:"gender" db:"gender"`
 	IsAdmin      bool   `json:"admin" db:"admin"` // tinyint(32) converted to bool
 	PasswordHash string `json:"password_hash" db:"password_hash"`
-	Token        string `json:"token" db:"token"`
 }
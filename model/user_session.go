@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// UserSession represents the user_session table in the database
+type UserSession struct {
+	ID               string    `json:"id_session" db:"id_session"`
+	UserID           int       `json:"id_user" db:"id_user"`
+	Device           string    `json:"device" db:"device"`
+	TokenHash        string    `json:"-" db:"token_hash"`
+	RefreshTokenHash string    `json:"-" db:"refresh_token_hash"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt       time.Time `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at" db:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at" db:"refresh_expires_at"`
+
+	// Set when listing sessions (not in database)
+	Current bool `json:"current" db:"-"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// UserTOTP represents the user_totp table in the database
+type UserTOTP struct {
+	UserID       int       `json:"id_user" db:"id_user"`
+	Secret       string    `json:"-" db:"secret"`
+	Enabled      bool      `json:"enabled" db:"enabled"`
+	LastUsedStep int64     `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ConfirmedAt  time.Time `json:"confirmed_at" db:"confirmed_at"`
+}
+
+// LoginChallenge represents the login_challenge table in the database
+type LoginChallenge struct {
+	ID        string    `json:"id_challenge" db:"id_challenge"`
+	UserID    int       `json:"id_user" db:"id_user"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	Device    string    `json:"device" db:"device"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
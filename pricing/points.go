@@ -0,0 +1,100 @@
+package pricing
+
+import (
+	"fmt"
+	"go-pos/model"
+)
+
+// EarningRule is how members earn loyalty points on a sale
+type EarningRule struct {
+	RupiahPerPoint      int
+	CategoryMultipliers map[int]int // Percent, keyed by category ID; 100 when missing
+	ExcludedItems       map[int]bool
+	Categories          map[int]int // Category ID of every item on the sale, keyed by item ID
+	PointsPaid          int         // Part of the sale total paid with points, which earns nothing
+}
+
+// EarnPoints returns the points a sale earns. Each line counts with what was paid for it before
+// tax, scaled by its category's multiplier; excluded items do not count. One point is earned
+// for every RupiahPerPoint of the scaled spend, rounded down. The part of the sale paid with
+// points is taken off the spend in proportion.
+func EarnPoints(lines []model.SalesItem, rule EarningRule) int {
+	if rule.RupiahPerPoint <= 0 {
+		return 0
+	}
+
+	// Scaled spend in hundredths of a rupiah, so multipliers stay exact
+	spend := 0
+	for _, line := range lines {
+		if rule.ExcludedItems[line.ItemID] {
+			continue
+		}
+
+		multiplier := 100
+		if categoryID, ok := rule.Categories[line.ItemID]; ok {
+			if m, ok := rule.CategoryMultipliers[categoryID]; ok {
+				multiplier = m
+			}
+		}
+		spend += (line.TotalAmount - line.TaxAmount) * multiplier
+	}
+
+	if spend <= 0 {
+		return 0
+	}
+
+	if rule.PointsPaid > 0 {
+		total := 0
+		for _, line := range lines {
+			total += line.TotalAmount
+		}
+		if rule.PointsPaid >= total {
+			return 0
+		}
+		spend = share(spend, total-rule.PointsPaid, total)
+	}
+
+	return spend / (rule.RupiahPerPoint * 100)
+}
+
+// RedemptionRule is how members spend loyalty points as a tender
+type RedemptionRule struct {
+	RupiahPerPoint int // What one point pays; 0 when points cannot be spent
+	MinimumPoints  int // Fewest points a sale may be paid with
+	MaxShare       int // Largest part of the sale total points may pay, in percent
+}
+
+// RedeemPoints values the points tenders of a sale at the rule's rate, setting what each one
+// tenders, and checks them against the rule's minimum and the share of the total they may pay.
+// It returns the points spent; whether the member holds them is checked when they are taken.
+func RedeemPoints(total int, tenders []model.SalesPayment, rule RedemptionRule) (int, error) {
+	points, amount := 0, 0
+	for i := range tenders {
+		tender := &tenders[i]
+		if tender.Method != model.PaymentMethodPoints {
+			continue
+		}
+
+		if rule.RupiahPerPoint <= 0 {
+			return 0, fmt.Errorf("points cannot be used as payment")
+		}
+		if tender.Points <= 0 {
+			return 0, fmt.Errorf("%s payment needs the number of points to spend", tender.Method)
+		}
+
+		tender.Tendered = tender.Points * rule.RupiahPerPoint
+		points += tender.Points
+		amount += tender.Tendered
+	}
+
+	if points == 0 {
+		return 0, nil
+	}
+	if points < rule.MinimumPoints {
+		return 0, fmt.Errorf("at least %d points must be spent, %d given", rule.MinimumPoints, points)
+	}
+	if limit := share(total, rule.MaxShare, 100); amount > limit {
+		return 0, fmt.Errorf("points may pay at most %d of the %d total, %d given", limit, total, amount)
+	}
+	return points, nil
+}
@@ -0,0 +1,84 @@
+// Package pricing computes sale totals on the server from the catalogue prices,
+// so the amounts a client sends are only ever checked, never trusted.
+package pricing
+
+import (
+	"fmt"
+	"go-pos/model"
+)
+
+// MismatchError reports a client-supplied amount that differs from the computed one
+type MismatchError struct {
+	Field    string
+	ItemID   int
+	Given    int
+	Expected int
+}
+
+// Error implements the error interface
+func (e *MismatchError) Error() string {
+	if e.ItemID > 0 {
+		return fmt.Sprintf("%s for item %d is %d, expected %d", e.Field, e.ItemID, e.Given, e.Expected)
+	}
+	return fmt.Sprintf("%s is %d, expected %d", e.Field, e.Given, e.Expected)
+}
+
+// PriceItem snapshots the unit price onto a sales line and computes its total.
+// A non-zero client total that differs is an error when strict, and is overwritten otherwise.
+func PriceItem(item *model.SalesItem, unitPrice int, strict bool) error {
+	if item.Qty <= 0 {
+		return fmt.Errorf("quantity for item %d must be greater than zero", item.ItemID)
+	}
+
+	lineTotal := unitPrice * item.Qty
+	if strict && item.TotalAmount != 0 && item.TotalAmount != lineTotal {
+		return &MismatchError{Field: "line total", ItemID: item.ItemID, Given: item.TotalAmount, Expected: lineTotal}
+	}
+
+	item.UnitPrice = unitPrice
+	item.TotalAmount = lineTotal
+	return nil
+}
+
+// PriceBasket prices every line of a basket from prices, keyed by item ID, applies the
+// promotions of the offer and then the taxes, either of which may be nil, and sets the basket
+// total and tax. Client-supplied totals are checked against the final amounts like in PriceItem.
+func PriceBasket(basket *model.SalesBasket, prices map[int]int, offer *Offer, taxes *Taxes, strict bool) error {
+	given := make([]int, len(basket.Items))
+	for i := range basket.Items {
+		item := &basket.Items[i]
+
+		unitPrice, ok := prices[item.ItemID]
+		if !ok {
+			return fmt.Errorf("no price for item %d", item.ItemID)
+		}
+		if item.Qty <= 0 {
+			return fmt.Errorf("quantity for item %d must be greater than zero", item.ItemID)
+		}
+
+		given[i] = item.TotalAmount
+		item.UnitPrice = unitPrice
+	}
+
+	ApplyPromotions(basket.Items, offer)
+	tax := ApplyTax(basket.Items, taxes)
+
+	total := 0
+	for i, item := range basket.Items {
+		if strict && given[i] != 0 && given[i] != item.TotalAmount {
+			return &MismatchError{Field: "line total", ItemID: item.ItemID, Given: given[i], Expected: item.TotalAmount}
+		}
+		total += item.TotalAmount
+	}
+
+	if strict && basket.Total != 0 && basket.Total != total {
+		return &MismatchError{Field: "basket total", Given: basket.Total, Expected: total}
+	}
+
+	basket.Total = total
+	basket.TaxAmount = tax
+	if taxes != nil {
+		basket.TaxMode = taxes.Mode
+	}
+	return nil
+}
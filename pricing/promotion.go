@@ -0,0 +1,144 @@
+package pricing
+
+import (
+	"go-pos/model"
+	"time"
+)
+
+// Offer is the set of promotions a basket is priced with
+type Offer struct {
+	Promotions []model.Promotion
+	Categories map[int]int // Category ID of every item on the basket, keyed by item ID
+	At         time.Time   // Moment the promotions must be running at
+}
+
+// ApplyPromotions discounts priced sales lines with the promotions running at the offer's time
+// and returns the discounted subtotal. Each line gets at most one item or category promotion,
+// whichever saves the most. Then the basket promotion saving the most, among those whose
+// minimum spend the discounted subtotal reaches, is spread over the lines in proportion to
+// their totals. Minimum spends of line promotions are checked against the undiscounted subtotal.
+func ApplyPromotions(lines []model.SalesItem, offer *Offer) int {
+	gross := 0
+	for i := range lines {
+		lines[i].Discount = 0
+		lines[i].Promotions = nil
+		lines[i].TotalAmount = lines[i].UnitPrice * lines[i].Qty
+		gross += lines[i].TotalAmount
+	}
+
+	if offer == nil {
+		return gross
+	}
+
+	var running []model.Promotion
+	for _, promotion := range offer.Promotions {
+		if promotion.IsActiveAt(offer.At) {
+			running = append(running, promotion)
+		}
+	}
+
+	subtotal := 0
+	for i := range lines {
+		line := &lines[i]
+
+		best, bestDiscount := 0, 0
+		for _, promotion := range running {
+			if promotion.MinSpend > gross || !appliesTo(promotion, *line, offer.Categories) {
+				continue
+			}
+			if discount := lineDiscount(promotion, *line); discount > bestDiscount {
+				best, bestDiscount = promotion.ID, discount
+			}
+		}
+
+		if bestDiscount > 0 {
+			discountLine(line, best, bestDiscount)
+		}
+		subtotal += line.TotalAmount
+	}
+
+	best, bestDiscount := 0, 0
+	for _, promotion := range running {
+		if promotion.Scope != model.PromotionScopeBasket || promotion.MinSpend > subtotal {
+			continue
+		}
+		if discount := basketDiscount(promotion, subtotal); discount > bestDiscount {
+			best, bestDiscount = promotion.ID, discount
+		}
+	}
+	if bestDiscount == 0 {
+		return subtotal
+	}
+
+	// Spread the basket discount so the line shares add up to exactly the discount
+	spread, cumulative := 0, 0
+	for i := range lines {
+		cumulative += lines[i].TotalAmount
+		share := share(bestDiscount, cumulative, subtotal) - spread
+		spread += share
+		if share > 0 {
+			discountLine(&lines[i], best, share)
+		}
+	}
+
+	return subtotal - bestDiscount
+}
+
+// appliesTo reports whether an item or category promotion covers a sales line
+func appliesTo(promotion model.Promotion, line model.SalesItem, categories map[int]int) bool {
+	switch promotion.Scope {
+	case model.PromotionScopeItem:
+		return line.ItemID == promotion.TargetID
+	case model.PromotionScopeCategory:
+		categoryID, ok := categories[line.ItemID]
+		return ok && categoryID == promotion.TargetID
+	}
+	return false
+}
+
+// lineDiscount returns what a promotion takes off a sales line, at most the line total
+func lineDiscount(promotion model.Promotion, line model.SalesItem) int {
+	discount := 0
+	switch promotion.Type {
+	case model.PromotionPercent:
+		discount = line.TotalAmount * promotion.Value / 100
+	case model.PromotionFixed:
+		discount = promotion.Value * line.Qty
+	case model.PromotionBuyXGetY:
+		if group := promotion.BuyQty + promotion.GetQty; group > 0 {
+			discount = line.Qty / group * promotion.GetQty * line.UnitPrice
+		}
+	}
+
+	if discount > line.TotalAmount {
+		return line.TotalAmount
+	}
+	return discount
+}
+
+// basketDiscount returns what a basket promotion takes off a subtotal, at most the subtotal
+func basketDiscount(promotion model.Promotion, subtotal int) int {
+	discount := 0
+	switch promotion.Type {
+	case model.PromotionPercent:
+		discount = subtotal * promotion.Value / 100
+	case model.PromotionFixed:
+		discount = promotion.Value
+	}
+
+	if discount > subtotal {
+		return subtotal
+	}
+	return discount
+}
+
+// discountLine takes a promotion's discount off a sales line and records it
+func discountLine(line *model.SalesItem, promotionID, discount int) {
+	line.Discount += discount
+	line.TotalAmount -= discount
+	line.Promotions = append(line.Promotions, model.SalesItemPromotion{
+		SalesItemID: line.ID,
+		PromotionID: promotionID,
+		Discount:    discount,
+	})
+}
@@ -0,0 +1,158 @@
+package pricing
+
+import (
+	"fmt"
+	"go-pos/model"
+)
+
+// OverReturnError reports a return of more units than are left to return on a sales line
+type OverReturnError struct {
+	SalesItemID int
+	Requested   int
+	Returnable  int
+}
+
+// Error implements the error interface
+func (e *OverReturnError) Error() string {
+	return fmt.Sprintf("cannot return %d of sales line %d, only %d left to return", e.Requested, e.SalesItemID, e.Returnable)
+}
+
+// RefundLine prices the return of qty units of a sales line, of which returnedBefore were
+// already returned. The refund is the line's share of what was actually charged for it, and
+// successive partial returns of a line add up to exactly its total. The tax refunded is
+// shared out the same way.
+func RefundLine(line model.SalesItem, returnedBefore, qty int) (model.SalesReturnItem, error) {
+	if qty <= 0 {
+		return model.SalesReturnItem{}, fmt.Errorf("return quantity for sales line %d must be greater than zero", line.ID)
+	}
+
+	returnable := line.Qty - returnedBefore
+	if qty > returnable {
+		return model.SalesReturnItem{}, &OverReturnError{SalesItemID: line.ID, Requested: qty, Returnable: returnable}
+	}
+
+	return model.SalesReturnItem{
+		SalesItemID: line.ID,
+		ItemID:      line.ItemID,
+		Qty:         qty,
+		UnitPrice:   line.UnitPrice,
+		TotalRefund: share(line.TotalAmount, returnedBefore+qty, line.Qty) - share(line.TotalAmount, returnedBefore, line.Qty),
+		TaxRefund:   share(line.TaxAmount, returnedBefore+qty, line.Qty) - share(line.TaxAmount, returnedBefore, line.Qty),
+	}, nil
+}
+
+// ReversePoints returns how many of the points a sale earned must be taken back for a refund.
+// Points are reversed in proportion to the part of the sale total refunded so far, less what
+// earlier returns already reversed, so a full refund reverses every earned point.
+func ReversePoints(earned, salesTotal, refundedBefore, refund, reversedBefore int) int {
+	if earned <= 0 || salesTotal <= 0 {
+		return 0
+	}
+
+	refunded := refundedBefore + refund
+	if refunded > salesTotal {
+		refunded = salesTotal
+	}
+
+	points := share(earned, refunded, salesTotal) - reversedBefore
+	if points < 0 {
+		return 0
+	}
+	return points
+}
+
+// ReverseSalePoints settles the points of a voided or refunded sale with a member holding
+// balance points. The spent points it gives back come first, then the earned points it takes
+// back, limited to the balance that leaves since points the member spent again cannot be taken
+// back. It returns the points reversed and the member's new balance.
+func ReverseSalePoints(balance, restored, earned int) (int, int) {
+	balance += restored
+	reversed := clamp(earned, 0, balance)
+	return reversed, balance - reversed
+}
+
+// share returns the part/whole fraction of amount, rounded down
+func share(amount, part, whole int) int {
+	if whole == 0 {
+		return 0
+	}
+	return amount * part / whole
+}
+
+// SplitRefund shares out the money part of a refund over the methods the sale was paid with,
+// in proportion to what each paid, so successive partial returns of a sale refund every method
+// exactly what it paid. refundedBefore holds what earlier returns already gave back per method.
+// Points tenders are left out; they are given back as points. A sale without money tenders is
+// refunded with the fallback method.
+func SplitRefund(tenders []model.SalesPayment, refundedBefore map[model.PaymentMethod]int, refund int, fallback model.PaymentMethod) []model.SalesReturnPayment {
+	if refund <= 0 {
+		return nil
+	}
+
+	var methods []model.PaymentMethod
+	paid := make(map[model.PaymentMethod]int)
+	paidTotal := 0
+	for _, tender := range tenders {
+		if tender.Method == model.PaymentMethodPoints {
+			continue
+		}
+		if _, seen := paid[tender.Method]; !seen {
+			methods = append(methods, tender.Method)
+		}
+		paid[tender.Method] += tender.Amount
+		paidTotal += tender.Amount
+	}
+	if paidTotal <= 0 {
+		return []model.SalesReturnPayment{{Method: fallback, Amount: refund}}
+	}
+
+	refunded := refund
+	for _, method := range methods {
+		refunded += refundedBefore[method]
+	}
+	if refunded > paidTotal {
+		refunded = paidTotal
+	}
+
+	// Each method first gets its proportional share, within what it has left to refund
+	amounts := make(map[model.PaymentMethod]int)
+	remaining := refund
+	for _, method := range methods {
+		amount := share(paid[method], refunded, paidTotal) - refundedBefore[method]
+		amount = clamp(amount, 0, paid[method]-refundedBefore[method])
+		if amount > remaining {
+			amount = remaining
+		}
+		amounts[method] = amount
+		remaining -= amount
+	}
+
+	// Rounding leftovers go to the methods with room left, the rest to the last method
+	for _, method := range methods {
+		room := paid[method] - refundedBefore[method] - amounts[method]
+		if take := clamp(room, 0, remaining); take > 0 {
+			amounts[method] += take
+			remaining -= take
+		}
+	}
+	amounts[methods[len(methods)-1]] += remaining
+
+	var payments []model.SalesReturnPayment
+	for _, method := range methods {
+		if amounts[method] > 0 {
+			payments = append(payments, model.SalesReturnPayment{Method: method, Amount: amounts[method]})
+		}
+	}
+	return payments
+}
+
+// clamp limits value to the range from low to high; low wins when high is below it
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
@@ -0,0 +1,46 @@
+package pricing
+
+import "go-pos/model"
+
+// Taxes is how the lines of a basket are taxed
+type Taxes struct {
+	Mode  model.TaxMode
+	Rates map[int]model.TaxRate // Tax rate of every taxed item, keyed by item ID; items without one are not taxed
+}
+
+// ApplyTax taxes discounted sales lines and returns the basket tax. Each line is taxed on its
+// own total, rounded to the nearest unit. With inclusive prices the tax is the part of the
+// line total it makes up; with exclusive prices it is added to the line total. Without taxes
+// the lines are left untaxed.
+func ApplyTax(lines []model.SalesItem, taxes *Taxes) int {
+	total := 0
+	for i := range lines {
+		line := &lines[i]
+		line.TaxRateID, line.TaxRate, line.TaxAmount = 0, 0, 0
+
+		if taxes == nil {
+			continue
+		}
+		rate, ok := taxes.Rates[line.ItemID]
+		if !ok || rate.Rate == 0 {
+			continue
+		}
+
+		line.TaxRateID = rate.ID
+		line.TaxRate = rate.Rate
+		if taxes.Mode == model.TaxExclusive {
+			line.TaxAmount = roundDiv(line.TotalAmount*rate.Rate, 10000)
+			line.TotalAmount += line.TaxAmount
+		} else {
+			line.TaxAmount = roundDiv(line.TotalAmount*rate.Rate, 10000+rate.Rate)
+		}
+		total += line.TaxAmount
+	}
+
+	return total
+}
+
+// roundDiv divides a non-negative amount, rounding halves up
+func roundDiv(amount, divisor int) int {
+	return (amount*2 + divisor) / (divisor * 2)
+}
@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"fmt"
+	"go-pos/model"
+)
+
+// ShortTenderError reports tenders that do not cover the sale total
+type ShortTenderError struct {
+	Total int
+	Due   int
+}
+
+// Error implements the error interface
+func (e *ShortTenderError) Error() string {
+	return fmt.Sprintf("payments leave %d of the %d total unpaid", e.Due, e.Total)
+}
+
+// SettleTenders applies tenders to a sale total in the order given and sets the amount each one
+// pays and the change it gives. Card tenders need a reference. Card and points tenders may not
+// exceed what is still due; only cash may, the excess being change. Together the tenders must
+// cover the total.
+func SettleTenders(total int, tenders []model.SalesPayment) error {
+	due := total
+
+	for i := range tenders {
+		tender := &tenders[i]
+
+		if !tender.Method.IsValid() {
+			return fmt.Errorf("unsupported payment method %q", tender.Method)
+		}
+		if tender.Tendered <= 0 {
+			return fmt.Errorf("tendered %s amount must be greater than zero", tender.Method)
+		}
+		if due == 0 {
+			return fmt.Errorf("%s payment of %d given after the total was covered", tender.Method, tender.Tendered)
+		}
+
+		if tender.Method.NeedsReference() && tender.Reference == "" {
+			return fmt.Errorf("%s payment needs a card reference", tender.Method)
+		}
+		if !tender.Method.GivesChange() {
+			if tender.Tendered > due {
+				return fmt.Errorf("%s payment of %d exceeds the %d still due", tender.Method, tender.Tendered, due)
+			}
+		}
+
+		tender.Amount = tender.Tendered
+		if tender.Amount > due {
+			tender.Amount = due
+		}
+		tender.Change = tender.Tendered - tender.Amount
+		due -= tender.Amount
+	}
+
+	if due > 0 {
+		return &ShortTenderError{Total: total, Due: due}
+	}
+	return nil
+}
+
+// PrimaryMethod returns the method of the tender that paid the largest part of a sale,
+// the first one on a tie, so single-method reports keep working for split payments
+func PrimaryMethod(tenders []model.SalesPayment) model.PaymentMethod {
+	var primary model.PaymentMethod
+	largest := -1
+	for _, tender := range tenders {
+		if tender.Amount > largest {
+			primary = tender.Method
+			largest = tender.Amount
+		}
+	}
+	return primary
+}
@@ -0,0 +1,57 @@
+package receipt
+
+import "bytes"
+
+// ESC/POS commands understood by common thermal receipt printers
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	escFeedLines   = []byte{0x1b, 'd', 4}
+	escPartialCut  = []byte{0x1d, 'V', 1}
+)
+
+// ESCPOS renders a receipt as a raw ESC/POS byte stream for a paper of the given width in
+// characters: it resets the printer, prints the rows with their alignment and emphasis, feeds
+// the paper past the cutter and cuts. Characters outside ASCII are printed as '?' since the
+// printer's code page is unknown.
+func ESCPOS(r *Receipt, columns int) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	for _, row := range layout(r, columns) {
+		if row.align == alignCenter {
+			b.Write(escAlignCenter)
+		} else {
+			b.Write(escAlignLeft)
+		}
+		if row.bold {
+			b.Write(escBoldOn)
+		}
+		b.WriteString(ascii(row.text))
+		b.WriteByte('\n')
+		if row.bold {
+			b.Write(escBoldOff)
+		}
+	}
+
+	b.Write(escAlignLeft)
+	b.Write(escFeedLines)
+	b.Write(escPartialCut)
+	return b.Bytes()
+}
+
+// ascii replaces every character outside printable ASCII with '?'
+func ascii(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			out = append(out, '?')
+			continue
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
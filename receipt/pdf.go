@@ -0,0 +1,82 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Page geometry in PDF points (1/72 inch)
+const (
+	pointsPerMM = 72 / 25.4
+	pdfMargin   = 8.0
+)
+
+// PDF renders a receipt as a single-page PDF the width of the paper roll, 58mm for up to 32
+// columns and 80mm otherwise, and as long as the receipt. It is set in the built-in Courier
+// font, sized so a row of the given width in characters fills the printable width.
+func PDF(r *Receipt, columns int) []byte {
+	paperMM := 80.0
+	if columns <= Columns58mm {
+		paperMM = 58.0
+	}
+	width := paperMM * pointsPerMM
+
+	// Courier glyphs are 0.6 em wide
+	fontSize := (width - 2*pdfMargin) / (float64(columns) * 0.6)
+	leading := fontSize * 1.2
+
+	rows := layout(r, columns)
+	height := 2*pdfMargin + float64(len(rows))*leading
+
+	var content strings.Builder
+	content.WriteString("BT\n")
+	font := ""
+	for i, row := range rows {
+		rowFont := "/F1"
+		if row.bold {
+			rowFont = "/F2"
+		}
+		if rowFont != font {
+			fmt.Fprintf(&content, "%s %.2f Tf\n", rowFont, fontSize)
+			font = rowFont
+		}
+
+		text := ascii(padRow(row, columns))
+		y := height - pdfMargin - float64(i+1)*leading + (leading-fontSize)/2
+		fmt.Fprintf(&content, "1 0 0 1 %.2f %.2f Tm (%s) Tj\n", pdfMargin, y, pdfEscape(text))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", width, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return b.Bytes()
+}
+
+// pdfEscape escapes the characters that end or escape a PDF string literal
+func pdfEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
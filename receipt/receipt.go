@@ -0,0 +1,292 @@
+// Package receipt renders completed sales as customer receipts for roll printers: plain text,
+// raw ESC/POS byte streams and PDF. Every format is laid out from the same rows, so a receipt
+// reads the same whichever way it is printed.
+package receipt
+
+import (
+	"fmt"
+	"go-pos/model"
+	"strings"
+	"time"
+)
+
+// Paper widths in characters of the printer's standard font
+const (
+	Columns58mm = 32
+	Columns80mm = 48
+)
+
+// Store is the header printed at the top of every receipt
+type Store struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxID   string
+	Footer  string
+}
+
+// Line is a sales line as printed
+type Line struct {
+	Name      string
+	Qty       int
+	UnitPrice int
+	Discount  int
+	Total     int
+}
+
+// Receipt is everything printed for one sale
+type Receipt struct {
+	Store    Store
+	SalesID  int
+	Invoice  string
+	Date     time.Time
+	Cashier  string
+	Register string
+	Lines    []Line
+	Subtotal int // Before discounts and added tax
+	Discount int
+	Tax      int
+	TaxMode  model.TaxMode
+	Total    int
+	Payments []model.SalesPayment
+	Change   int
+
+	// Member details, printed when the sale was made for a member
+	Member        string
+	PointsEarned  int
+	PointsBalance int
+
+	// Reprints are flagged as copies; Print counts the prints of the sale, starting at 1
+	Copy  bool
+	Print int
+}
+
+// New builds the receipt of a sale with its loaded lines and payments. names maps item IDs to
+// item names; member may be nil.
+func New(store Store, basket *model.SalesBasket, names map[int]string, cashier string, member *model.Member, pointsEarned int) *Receipt {
+	r := &Receipt{
+		Store:    store,
+		SalesID:  basket.ID,
+		Invoice:  basket.InvoiceNo,
+		Date:     time.Unix(int64(basket.SalesDate), 0),
+		Cashier:  cashier,
+		Register: basket.Register,
+		Tax:      basket.TaxAmount,
+		TaxMode:  basket.TaxMode,
+		Total:    basket.Total,
+		Payments: basket.Payments,
+	}
+
+	for _, item := range basket.Items {
+		name, ok := names[item.ItemID]
+		if !ok {
+			name = fmt.Sprintf("Item %d", item.ItemID)
+		}
+
+		gross := item.UnitPrice * item.Qty
+		r.Lines = append(r.Lines, Line{
+			Name:      name,
+			Qty:       item.Qty,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+			Total:     gross - item.Discount,
+		})
+		r.Subtotal += gross
+		r.Discount += item.Discount
+	}
+
+	for _, payment := range basket.Payments {
+		r.Change += payment.Change
+	}
+
+	if member != nil {
+		r.Member = member.Name
+		r.PointsEarned = pointsEarned
+		r.PointsBalance = member.Points
+	}
+
+	return r
+}
+
+// align is where a row sits on the paper
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+)
+
+// row is one printed line of a receipt
+type row struct {
+	text  string
+	align align
+	bold  bool
+}
+
+// layout lays a receipt out in rows of at most columns characters
+func layout(r *Receipt, columns int) []row {
+	var rows []row
+	center := func(text string, bold bool) {
+		for _, part := range wrap(text, columns) {
+			rows = append(rows, row{text: part, align: alignCenter, bold: bold})
+		}
+	}
+	left := func(text string) {
+		for _, part := range wrap(text, columns) {
+			rows = append(rows, row{text: part})
+		}
+	}
+	pair := func(label string, amount string, bold bool) {
+		rows = append(rows, row{text: spread(label, amount, columns), bold: bold})
+	}
+	rule := func() {
+		rows = append(rows, row{text: strings.Repeat("-", columns)})
+	}
+
+	center(r.Store.Name, true)
+	if r.Store.Address != "" {
+		center(r.Store.Address, false)
+	}
+	if r.Store.Phone != "" {
+		center("Tel. "+r.Store.Phone, false)
+	}
+	if r.Store.TaxID != "" {
+		center("NPWP "+r.Store.TaxID, false)
+	}
+	rule()
+
+	if r.Copy {
+		center(fmt.Sprintf("*** COPY (reprint %d) ***", r.Print-1), true)
+	}
+	if r.Invoice != "" {
+		left("Invoice  : " + r.Invoice)
+	}
+	left(fmt.Sprintf("Sale     : %d", r.SalesID))
+	left("Date     : " + r.Date.Format("02-01-2006 15:04"))
+	left("Cashier  : " + r.Cashier)
+	if r.Register != "" {
+		left("Register : " + r.Register)
+	}
+	rule()
+
+	for _, line := range r.Lines {
+		left(line.Name)
+		pair(fmt.Sprintf("  %d x %s", line.Qty, Amount(line.UnitPrice)), Amount(line.UnitPrice*line.Qty), false)
+		if line.Discount > 0 {
+			pair("  Discount", Amount(-line.Discount), false)
+		}
+	}
+	rule()
+
+	pair("Subtotal", Amount(r.Subtotal), false)
+	if r.Discount > 0 {
+		pair("Discount", Amount(-r.Discount), false)
+	}
+	if r.Tax > 0 {
+		if r.TaxMode == model.TaxExclusive {
+			pair("Tax", Amount(r.Tax), false)
+		} else {
+			pair("Tax included", Amount(r.Tax), false)
+		}
+	}
+	pair("TOTAL", Amount(r.Total), true)
+
+	for _, payment := range r.Payments {
+		pair(string(payment.Method), Amount(payment.Tendered), false)
+		if payment.Reference != "" {
+			left("  Ref " + payment.Reference)
+		}
+		if payment.Points > 0 {
+			left(fmt.Sprintf("  %d points", payment.Points))
+		}
+	}
+	if r.Change > 0 {
+		pair("Change", Amount(r.Change), false)
+	}
+
+	if r.Member != "" {
+		rule()
+		left("Member   : " + r.Member)
+		pair("Points earned", fmt.Sprintf("%d", r.PointsEarned), false)
+		pair("Points balance", fmt.Sprintf("%d", r.PointsBalance), false)
+	}
+
+	if r.Store.Footer != "" {
+		rule()
+		center(r.Store.Footer, false)
+	}
+
+	return rows
+}
+
+// Amount formats an amount of rupiah with dots between thousands, e.g. 1.250.000
+func Amount(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%d", amount)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
+// spread puts label on the left and value on the right of a row, shortening the label if they
+// do not fit
+func spread(label, value string, columns int) string {
+	labelRunes := []rune(label)
+	room := columns - len([]rune(value)) - 1
+	if room < 0 {
+		room = 0
+	}
+	if len(labelRunes) > room {
+		labelRunes = labelRunes[:room]
+	}
+	gap := columns - len(labelRunes) - len([]rune(value))
+	if gap < 1 {
+		gap = 1
+	}
+	return string(labelRunes) + strings.Repeat(" ", gap) + value
+}
+
+// wrap breaks text into pieces of at most columns characters, at spaces where possible
+func wrap(text string, columns int) []string {
+	if len([]rune(text)) <= columns {
+		return []string{text}
+	}
+
+	var parts []string
+	words := strings.Fields(text)
+	current := ""
+	for _, word := range words {
+		for len([]rune(word)) > columns {
+			if current != "" {
+				parts = append(parts, current)
+				current = ""
+			}
+			runes := []rune(word)
+			parts = append(parts, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case len([]rune(current))+1+len([]rune(word)) <= columns:
+			current += " " + word
+		default:
+			parts = append(parts, current)
+			current = word
+		}
+	}
+	if current != "" || len(parts) == 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
@@ -0,0 +1,25 @@
+package receipt
+
+import "strings"
+
+// Text renders a receipt as plain text for a paper of the given width in characters
+func Text(r *Receipt, columns int) string {
+	var b strings.Builder
+	for _, row := range layout(r, columns) {
+		b.WriteString(padRow(row, columns))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// padRow indents a centered row so it sits in the middle of the paper
+func padRow(row row, columns int) string {
+	if row.align != alignCenter {
+		return row.text
+	}
+	indent := (columns - len([]rune(row.text))) / 2
+	if indent <= 0 {
+		return row.text
+	}
+	return strings.Repeat(" ", indent) + row.text
+}
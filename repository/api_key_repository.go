@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// APIKeyRepository handles database operations for API keys and their scopes
+type APIKeyRepository struct{}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{}
+}
+
+const apiKeyColumns = `id_api_key, key_name, key_prefix, key_hash, id_user, created_by,
+	          created_at, last_used_at, expires_at, revoked_at`
+
+// CreateAPIKey inserts a new key together with its permissions and routes
+func (r *APIKeyRepository) CreateAPIKey(apiKey *model.APIKey) (*model.APIKey, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO api_key (key_name, key_prefix, key_hash, id_user, created_by, created_at, expires_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		apiKey.Name,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		apiKey.UserID,
+		apiKey.CreatedBy,
+		apiKey.CreatedAt,
+		nullableTime(apiKey.ExpiresAt))
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Get the last inserted ID
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	apiKey.ID = int(lastID)
+
+	for _, permission := range apiKey.Permissions {
+		result, err := tx.Exec(`INSERT INTO api_key_permission (id_api_key, id_permission)
+		                        SELECT ?, id_permission FROM permission WHERE code = ?`, apiKey.ID, permission.Code)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			tx.Rollback()
+			return nil, fmt.Errorf("unknown permission %q", permission.Code)
+		}
+	}
+
+	for _, route := range apiKey.Routes {
+		_, err := tx.Exec(`INSERT INTO api_key_route (id_api_key, method, path) VALUES (?, ?, ?)`,
+			apiKey.ID, route.Method, route.Path)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// GetAPIKey retrieves a key by ID together with its permissions and routes
+func (r *APIKeyRepository) GetAPIKey(id int) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key WHERE id_api_key = ?`
+
+	apiKey, err := r.scanAPIKey(database.DB.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("API key with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, r.loadScopes(apiKey)
+}
+
+// GetAPIKeyByHash retrieves a key by the digest of its secret together with its permissions and routes
+func (r *APIKeyRepository) GetAPIKeyByHash(keyHash string) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key WHERE key_hash = ?`
+
+	apiKey, err := r.scanAPIKey(database.DB.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, r.loadScopes(apiKey)
+}
+
+// GetAllAPIKeys retrieves every key, newest first, together with its permissions and routes
+func (r *APIKeyRepository) GetAllAPIKeys() ([]model.APIKey, error) {
+	var apiKeys []model.APIKey
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key ORDER BY created_at DESC`
+
+	rows, err := database.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		apiKey, err := r.scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		apiKeys = append(apiKeys, *apiKey)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range apiKeys {
+		if err := r.loadScopes(&apiKeys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return apiKeys, nil
+}
+
+// TouchAPIKey records the time a key was last used
+func (r *APIKeyRepository) TouchAPIKey(id int, lastUsedAt time.Time) error {
+	query := `UPDATE api_key SET last_used_at = ? WHERE id_api_key = ?`
+
+	_, err := database.DB.Exec(query, lastUsedAt, id)
+	return err
+}
+
+// RevokeAPIKey disables a key; revoked keys are kept for the audit trail
+func (r *APIKeyRepository) RevokeAPIKey(id int, revokedAt time.Time) error {
+	query := `UPDATE api_key SET revoked_at = ? WHERE id_api_key = ? AND revoked_at IS NULL`
+
+	_, err := database.DB.Exec(query, revokedAt, id)
+	return err
+}
+
+// loadScopes fills in the permissions and routes of a key
+func (r *APIKeyRepository) loadScopes(apiKey *model.APIKey) error {
+	permissions, err := NewRoleRepository().queryPermissions(`SELECT p.id_permission, p.code, p.description
+	          FROM permission p
+	          JOIN api_key_permission kp ON kp.id_permission = p.id_permission
+	          WHERE kp.id_api_key = ?
+	          ORDER BY p.code`, apiKey.ID)
+	if err != nil {
+		return err
+	}
+	apiKey.Permissions = permissions
+
+	rows, err := database.DB.Query(`SELECT method, path FROM api_key_route WHERE id_api_key = ? ORDER BY path, method`, apiKey.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	apiKey.Routes = nil
+	for rows.Next() {
+		var route model.APIKeyRoute
+		if err := rows.Scan(&route.Method, &route.Path); err != nil {
+			return err
+		}
+
+		apiKey.Routes = append(apiKey.Routes, route)
+	}
+
+	return rows.Err()
+}
+
+// scanAPIKey scans a single api_key row
+func (r *APIKeyRepository) scanAPIKey(row interface{ Scan(...interface{}) error }) (*model.APIKey, error) {
+	apiKey := &model.APIKey{}
+	var lastUsedAt, expiresAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&apiKey.ID,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.KeyHash,
+		&apiKey.UserID,
+		&apiKey.CreatedBy,
+		&apiKey.CreatedAt,
+		&lastUsedAt,
+		&expiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.LastUsedAt = lastUsedAt.Time
+	apiKey.ExpiresAt = expiresAt.Time
+	apiKey.RevokedAt = revokedAt.Time
+
+	return apiKey, nil
+}
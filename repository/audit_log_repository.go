@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"go-pos/database"
+	"go-pos/model"
+	"strings"
+)
+
+// AuditLogRepository handles database operations for the audit trail
+type AuditLogRepository struct{}
+
+// NewAuditLogRepository creates a new AuditLogRepository
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// CreateAuditLog inserts a new audit record into the database
+func (r *AuditLogRepository) CreateAuditLog(auditLog *model.AuditLog) (*model.AuditLog, error) {
+	query := `INSERT INTO audit_log (id_user, entity_type, entity_id, action, ip, user_agent, changes, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := database.DB.Exec(query,
+		auditLog.UserID,
+		auditLog.EntityType,
+		auditLog.EntityID,
+		auditLog.Action,
+		auditLog.IP,
+		auditLog.UserAgent,
+		auditLog.Changes,
+		auditLog.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the last inserted ID
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	auditLog.ID = int(lastID)
+	return auditLog, nil
+}
+
+// GetAuditLogs retrieves audit records matching the filter, newest first
+func (r *AuditLogRepository) GetAuditLogs(filter model.AuditLogFilter) ([]model.AuditLog, error) {
+	var auditLogs []model.AuditLog
+
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserID > 0 {
+		conditions = append(conditions, "id_user = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+
+	query := `SELECT id_audit, id_user, entity_type, entity_id, action, ip, user_agent, changes, created_at
+	          FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id_audit DESC LIMIT ?"
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var auditLog model.AuditLog
+		err := rows.Scan(
+			&auditLog.ID,
+			&auditLog.UserID,
+			&auditLog.EntityType,
+			&auditLog.EntityID,
+			&auditLog.Action,
+			&auditLog.IP,
+			&auditLog.UserAgent,
+			&auditLog.Changes,
+			&auditLog.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
@@ -17,9 +17,9 @@ func NewCategoryRepository() *CategoryRepository {
 
 // CreateCategory inserts a new category into the database
 func (r *CategoryRepository) CreateCategory(category *model.Category) (*model.Category, error) {
-	query := `INSERT INTO category (category_name) VALUES (?)`
+	query := `INSERT INTO category (category_name, id_tax_rate) VALUES (?, ?)`
 	          
-	result, err := database.DB.Exec(query, category.Name)
+	result, err := database.DB.Exec(query, category.Name, nullableID(category.TaxRateID))
 	if err != nil {
 		return nil, err
 	}
@@ -38,9 +38,9 @@ func (r *CategoryRepository) CreateCategory(category *model.Category) (*model.Ca
 func (r *CategoryRepository) GetCategory(id int) (*model.Category, error) {
 	category := &model.Category{}
 	
-	query := `SELECT id_category, category_name FROM category WHERE id_category = ?`
+	query := `SELECT id_category, category_name, COALESCE(id_tax_rate, 0) FROM category WHERE id_category = ?`
 	          
-	err := database.DB.QueryRow(query, id).Scan(&category.ID, &category.Name)
+	err := database.DB.QueryRow(query, id).Scan(&category.ID, &category.Name, &category.TaxRateID)
 	
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -56,7 +56,7 @@ func (r *CategoryRepository) GetCategory(id int) (*model.Category, error) {
 func (r *CategoryRepository) GetAllCategories() ([]model.Category, error) {
 	var categories []model.Category
 	
-	query := `SELECT id_category, category_name FROM category ORDER BY category_name`
+	query := `SELECT id_category, category_name, COALESCE(id_tax_rate, 0) FROM category ORDER BY category_name`
 	          
 	rows, err := database.DB.Query(query)
 	if err != nil {
@@ -66,7 +66,7 @@ func (r *CategoryRepository) GetAllCategories() ([]model.Category, error) {
 	
 	for rows.Next() {
 		var category model.Category
-		err := rows.Scan(&category.ID, &category.Name)
+		err := rows.Scan(&category.ID, &category.Name, &category.TaxRateID)
 		
 		if err != nil {
 			return nil, err
@@ -84,9 +84,9 @@ func (r *CategoryRepository) GetAllCategories() ([]model.Category, error) {
 
 // UpdateCategory updates an existing category in the database
 func (r *CategoryRepository) UpdateCategory(category *model.Category) (*model.Category, error) {
-	query := `UPDATE category SET category_name = ? WHERE id_category = ?`
+	query := `UPDATE category SET category_name = ?, id_tax_rate = ? WHERE id_category = ?`
 	          
-	_, err := database.DB.Exec(query, category.Name, category.ID)
+	_, err := database.DB.Exec(query, category.Name, nullableID(category.TaxRateID), category.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// DrawerMovementRepository handles database operations for cash drawer movements
+type DrawerMovementRepository struct{}
+
+// NewDrawerMovementRepository creates a new DrawerMovementRepository
+func NewDrawerMovementRepository() *DrawerMovementRepository {
+	return &DrawerMovementRepository{}
+}
+
+// CreateDrawerMovementTx inserts a drawer movement as part of a transaction
+func (r *DrawerMovementRepository) CreateDrawerMovementTx(tx *sql.Tx, movement *model.DrawerMovement) (*model.DrawerMovement, error) {
+	query := `INSERT INTO drawer_movement (id_shift, id_user, type, reason, amount, note, created_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		movement.ShiftID,
+		movement.UserID,
+		movement.Type,
+		movement.Reason,
+		movement.Amount,
+		movement.Note,
+		movement.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	movement.ID = int(lastID)
+	return movement, nil
+}
+
+// GetDrawerMovementsByShift retrieves the movements of a shift's drawer in the order they were made
+func (r *DrawerMovementRepository) GetDrawerMovementsByShift(shiftID int) ([]model.DrawerMovement, error) {
+	var movements []model.DrawerMovement
+
+	query := `SELECT id_movement, id_shift, id_user, type, reason, amount, note, created_at 
+	          FROM drawer_movement 
+	          WHERE id_shift = ? 
+	          ORDER BY id_movement`
+
+	rows, err := database.DB.Query(query, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement model.DrawerMovement
+		err := rows.Scan(
+			&movement.ID,
+			&movement.ShiftID,
+			&movement.UserID,
+			&movement.Type,
+			&movement.Reason,
+			&movement.Amount,
+			&movement.Note,
+			&movement.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// GetDrawerTotals sums the movements of a shift's drawer per type and reason
+func (r *DrawerMovementRepository) GetDrawerTotals(shiftID int) ([]model.DrawerMovementTotal, error) {
+	return drawerTotals(database.DB, shiftID)
+}
+
+// GetDrawerTotalsTx sums the movements of a shift's drawer per type and reason as part of a transaction
+func (r *DrawerMovementRepository) GetDrawerTotalsTx(tx *sql.Tx, shiftID int) ([]model.DrawerMovementTotal, error) {
+	return drawerTotals(tx, shiftID)
+}
+
+// drawerTotals sums the movements of a shift's drawer per type and reason
+func drawerTotals(q queryer, shiftID int) ([]model.DrawerMovementTotal, error) {
+	var totals []model.DrawerMovementTotal
+
+	query := `SELECT type, reason, COUNT(*), COALESCE(SUM(amount), 0)
+	          FROM drawer_movement
+	          WHERE id_shift = ?
+	          GROUP BY type, reason
+	          ORDER BY type, reason`
+
+	rows, err := q.Query(query, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total model.DrawerMovementTotal
+		if err := rows.Scan(&total.Type, &total.Reason, &total.Count, &total.Amount); err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, total)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// IdempotencyKeyRepository handles database operations for idempotency keys
+type IdempotencyKeyRepository struct{}
+
+// NewIdempotencyKeyRepository creates a new IdempotencyKeyRepository
+func NewIdempotencyKeyRepository() *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{}
+}
+
+// ReserveIdempotencyKey records a key as in progress. It returns false without error when the
+// user already holds the key, so of two concurrent requests with a key only one goes ahead.
+func (r *IdempotencyKeyRepository) ReserveIdempotencyKey(key *model.IdempotencyKey) (bool, error) {
+	query := `INSERT IGNORE INTO idempotency_key (id_user, idempotency_key, route, request_hash, status_code, created_at) 
+	          VALUES (?, ?, ?, ?, 0, ?)`
+
+	result, err := database.DB.Exec(query, key.UserID, key.Key, key.Route, key.RequestHash, key.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// GetIdempotencyKey retrieves a key a user has used
+func (r *IdempotencyKeyRepository) GetIdempotencyKey(userID int, key string) (*model.IdempotencyKey, error) {
+	idempotencyKey := &model.IdempotencyKey{}
+	var response sql.NullString
+
+	query := `SELECT id_user, idempotency_key, route, request_hash, status_code, response, created_at 
+	          FROM idempotency_key WHERE id_user = ? AND idempotency_key = ?`
+
+	err := database.DB.QueryRow(query, userID, key).Scan(
+		&idempotencyKey.UserID,
+		&idempotencyKey.Key,
+		&idempotencyKey.Route,
+		&idempotencyKey.RequestHash,
+		&idempotencyKey.StatusCode,
+		&response,
+		&idempotencyKey.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("idempotency key %q not found", key)
+		}
+		return nil, err
+	}
+
+	idempotencyKey.Response = response.String
+	return idempotencyKey, nil
+}
+
+// SaveIdempotencyResponse stores the response the request that reserved a key was answered with
+func (r *IdempotencyKeyRepository) SaveIdempotencyResponse(userID int, key string, statusCode int, response string) error {
+	query := `UPDATE idempotency_key SET status_code = ?, response = ? WHERE id_user = ? AND idempotency_key = ?`
+
+	_, err := database.DB.Exec(query, statusCode, response, userID, key)
+	return err
+}
+
+// DeleteIdempotencyKey releases a key so it can be used again
+func (r *IdempotencyKeyRepository) DeleteIdempotencyKey(userID int, key string) error {
+	query := `DELETE FROM idempotency_key WHERE id_user = ? AND idempotency_key = ?`
+
+	_, err := database.DB.Exec(query, userID, key)
+	return err
+}
+
+// DeleteIdempotencyKeysBefore removes keys reserved before the given time
+func (r *IdempotencyKeyRepository) DeleteIdempotencyKeysBefore(before time.Time) error {
+	query := `DELETE FROM idempotency_key WHERE created_at < ?`
+
+	_, err := database.DB.Exec(query, before)
+	return err
+}
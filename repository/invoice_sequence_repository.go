@@ -0,0 +1,27 @@
+package repository
+
+import "database/sql"
+
+// InvoiceSequenceRepository handles database operations for invoice number series
+type InvoiceSequenceRepository struct{}
+
+// NewInvoiceSequenceRepository creates a new InvoiceSequenceRepository
+func NewInvoiceSequenceRepository() *InvoiceSequenceRepository {
+	return &InvoiceSequenceRepository{}
+}
+
+// NextNumberTx hands out the next number of a series as part of a transaction. The series row
+// stays locked until the transaction ends, so concurrent sales wait their turn and a rolled back
+// sale gives its number back.
+func (r *InvoiceSequenceRepository) NextNumberTx(tx *sql.Tx, series string) (int, error) {
+	query := `INSERT INTO invoice_sequence (series, last_number) VALUES (?, 1)
+	          ON DUPLICATE KEY UPDATE last_number = last_number + 1`
+
+	if _, err := tx.Exec(query, series); err != nil {
+		return 0, err
+	}
+
+	var number int
+	err := tx.QueryRow(`SELECT last_number FROM invoice_sequence WHERE series = ?`, series).Scan(&number)
+	return number, err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go-pos/database"
 	"go-pos/model"
+	"time"
 )
 
 // ItemBatchRepository handles database operations for item batches
@@ -17,13 +18,14 @@ func NewItemBatchRepository() *ItemBatchRepository {
 
 // CreateItemBatch inserts a new item batch into the database
 func (r *ItemBatchRepository) CreateItemBatch(itemBatch *model.ItemBatch) (*model.ItemBatch, error) {
-	query := `INSERT INTO item_batch (id_item, date_in, date_out, batch_qty) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO item_batch (id_item, date_in, date_out, expiry_date, batch_qty) 
+	          VALUES (?, ?, ?, ?, ?)`
 	          
 	result, err := database.DB.Exec(query, 
 		itemBatch.ItemID, 
 		itemBatch.DateIn, 
 		itemBatch.DateOut, 
+		nullableTime(itemBatch.ExpiryDate), 
 		itemBatch.Qty)
 		
 	if err != nil {
@@ -43,8 +45,9 @@ func (r *ItemBatchRepository) CreateItemBatch(itemBatch *model.ItemBatch) (*mode
 // GetItemBatch retrieves an item batch by ID from the database
 func (r *ItemBatchRepository) GetItemBatch(id int) (*model.ItemBatch, error) {
 	itemBatch := &model.ItemBatch{}
+	var expiryDate sql.NullTime
 	
-	query := `SELECT id_batch, id_item, date_in, date_out, batch_qty 
+	query := `SELECT id_batch, id_item, date_in, date_out, expiry_date, batch_qty 
 	          FROM item_batch WHERE id_batch = ?`
 	          
 	err := database.DB.QueryRow(query, id).Scan(
@@ -52,6 +55,7 @@ func (r *ItemBatchRepository) GetItemBatch(id int) (*model.ItemBatch, error) {
 		&itemBatch.ItemID,
 		&itemBatch.DateIn,
 		&itemBatch.DateOut,
+		&expiryDate,
 		&itemBatch.Qty,
 	)
 	
@@ -61,6 +65,7 @@ func (r *ItemBatchRepository) GetItemBatch(id int) (*model.ItemBatch, error) {
 		}
 		return nil, err
 	}
+	itemBatch.ExpiryDate = expiryDate.Time
 	
 	return itemBatch, nil
 }
@@ -69,7 +74,7 @@ func (r *ItemBatchRepository) GetItemBatch(id int) (*model.ItemBatch, error) {
 func (r *ItemBatchRepository) GetAllItemBatches() ([]model.ItemBatch, error) {
 	var itemBatches []model.ItemBatch
 	
-	query := `SELECT id_batch, id_item, date_in, date_out, batch_qty 
+	query := `SELECT id_batch, id_item, date_in, date_out, expiry_date, batch_qty 
 	          FROM item_batch ORDER BY date_in DESC`
 	          
 	rows, err := database.DB.Query(query)
@@ -80,11 +85,13 @@ func (r *ItemBatchRepository) GetAllItemBatches() ([]model.ItemBatch, error) {
 	
 	for rows.Next() {
 		var itemBatch model.ItemBatch
+		var expiryDate sql.NullTime
 		err := rows.Scan(
 			&itemBatch.ID,
 			&itemBatch.ItemID,
 			&itemBatch.DateIn,
 			&itemBatch.DateOut,
+			&expiryDate,
 			&itemBatch.Qty,
 		)
 		
@@ -92,6 +99,7 @@ func (r *ItemBatchRepository) GetAllItemBatches() ([]model.ItemBatch, error) {
 			return nil, err
 		}
 		
+		itemBatch.ExpiryDate = expiryDate.Time
 		itemBatches = append(itemBatches, itemBatch)
 	}
 	
@@ -106,7 +114,7 @@ func (r *ItemBatchRepository) GetAllItemBatches() ([]model.ItemBatch, error) {
 func (r *ItemBatchRepository) GetItemBatchesByItem(itemID int) ([]model.ItemBatch, error) {
 	var itemBatches []model.ItemBatch
 	
-	query := `SELECT id_batch, id_item, date_in, date_out, batch_qty 
+	query := `SELECT id_batch, id_item, date_in, date_out, expiry_date, batch_qty 
 	          FROM item_batch 
 	          WHERE id_item = ? 
 	          ORDER BY date_in DESC`
@@ -119,11 +127,13 @@ func (r *ItemBatchRepository) GetItemBatchesByItem(itemID int) ([]model.ItemBatc
 	
 	for rows.Next() {
 		var itemBatch model.ItemBatch
+		var expiryDate sql.NullTime
 		err := rows.Scan(
 			&itemBatch.ID,
 			&itemBatch.ItemID,
 			&itemBatch.DateIn,
 			&itemBatch.DateOut,
+			&expiryDate,
 			&itemBatch.Qty,
 		)
 		
@@ -131,6 +141,7 @@ func (r *ItemBatchRepository) GetItemBatchesByItem(itemID int) ([]model.ItemBatc
 			return nil, err
 		}
 		
+		itemBatch.ExpiryDate = expiryDate.Time
 		itemBatches = append(itemBatches, itemBatch)
 	}
 	
@@ -147,6 +158,7 @@ func (r *ItemBatchRepository) UpdateItemBatch(itemBatch *model.ItemBatch) (*mode
 	          id_item = ?, 
 	          date_in = ?, 
 	          date_out = ?, 
+	          expiry_date = ?, 
 	          batch_qty = ? 
 	          WHERE id_batch = ?`
 	          
@@ -154,6 +166,7 @@ func (r *ItemBatchRepository) UpdateItemBatch(itemBatch *model.ItemBatch) (*mode
 		itemBatch.ItemID,
 		itemBatch.DateIn,
 		itemBatch.DateOut,
+		nullableTime(itemBatch.ExpiryDate),
 		itemBatch.Qty,
 		itemBatch.ID)
 
@@ -175,3 +188,68 @@ func (r *ItemBatchRepository) DeleteItemBatch(id int) error {
 	
 	return nil
 }
+
+// nullableTime maps the zero time to NULL for nullable DATETIME columns
+func nullableTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// GetItemBatchesForUpdateTx retrieves every batch of an item and locks them until the transaction ends
+func (r *ItemBatchRepository) GetItemBatchesForUpdateTx(tx *sql.Tx, itemID int) ([]model.ItemBatch, error) {
+	var itemBatches []model.ItemBatch
+
+	query := `SELECT id_batch, id_item, date_in, date_out, expiry_date, batch_qty
+	          FROM item_batch
+	          WHERE id_item = ?
+	          ORDER BY id_batch
+	          FOR UPDATE`
+
+	rows, err := tx.Query(query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemBatch model.ItemBatch
+		var expiryDate sql.NullTime
+		err := rows.Scan(
+			&itemBatch.ID,
+			&itemBatch.ItemID,
+			&itemBatch.DateIn,
+			&itemBatch.DateOut,
+			&expiryDate,
+			&itemBatch.Qty,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		itemBatch.ExpiryDate = expiryDate.Time
+		itemBatches = append(itemBatches, itemBatch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return itemBatches, nil
+}
+
+// AdjustQtyTx adds delta (negative to deduct) to a batch quantity as part of a transaction
+func (r *ItemBatchRepository) AdjustQtyTx(tx *sql.Tx, id int, delta int) error {
+	query := `UPDATE item_batch SET batch_qty = batch_qty + ? WHERE id_batch = ?`
+
+	_, err := tx.Exec(query, delta, id)
+	return err
+}
+
+// GetStockByItem returns the quantity of an item on hand over all its batches
+func (r *ItemBatchRepository) GetStockByItem(itemID int) (int, error) {
+	query := `SELECT COALESCE(SUM(batch_qty), 0) FROM item_batch WHERE id_item = ?`
+
+	var stock int
+	err := database.DB.QueryRow(query, itemID).Scan(&stock)
+	return stock, err
+}
@@ -2,11 +2,18 @@ package repository
 
 import (
     "database/sql"
+    "errors"
     "fmt"
     "go-pos/database"
     "go-pos/model"
+    "strings"
+
+    "github.com/go-sql-driver/mysql"
 )
 
+// mysqlDuplicateEntry is the MySQL error number for a unique index violation
+const mysqlDuplicateEntry = 1062
+
 // ItemRepository handles database operations for items
 type ItemRepository struct{}
 
@@ -15,36 +22,102 @@ func NewItemRepository() *ItemRepository {
     return &ItemRepository{}
 }
 
-// CreateItem inserts a new item into the database
+// DuplicateCodeError reports that an SKU or barcode is already used by another item
+type DuplicateCodeError struct {
+    Code   string
+    ItemID int // Zero when a unique index caught a concurrent save
+}
+
+// Error implements the error interface
+func (e *DuplicateCodeError) Error() string {
+    if e.ItemID == 0 {
+        return fmt.Sprintf("code %s is already used by another item", e.Code)
+    }
+    return fmt.Sprintf("code %s is already used by item %d", e.Code, e.ItemID)
+}
+
+// duplicateCode turns a unique index violation from saving code into a *DuplicateCodeError.
+// checkCodesTx takes no locks, so two saves of the same code can both pass it; the unique
+// indexes on item.sku and item_barcode.code stop the second one here.
+func duplicateCode(err error, code string) error {
+    var mysqlErr *mysql.MySQLError
+    if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+        return &DuplicateCodeError{Code: code}
+    }
+    return err
+}
+
+// itemColumns lists the item columns in the order scanItem reads them
+const itemColumns = `id_item, item_category, item_name, COALESCE(sku, ''), item_price, COALESCE(id_tax_rate, 0)`
+
+// scanItem reads one row selected with itemColumns
+func scanItem(row interface{ Scan(...interface{}) error }, item *model.Item) error {
+    return row.Scan(
+        &item.ID,
+        &item.CategoryID,
+        &item.Name,
+        &item.SKU,
+        &item.Price,
+        &item.TaxRateID,
+    )
+}
+
+// CreateItem inserts a new item and its barcodes into the database.
+// It returns a *DuplicateCodeError when another item already uses its SKU or one of its barcodes.
 func (r *ItemRepository) CreateItem(item *model.Item) (*model.Item, error) {
-    query := `INSERT INTO item (item_category, item_name, item_price) 
-              VALUES (?, ?, ?)`
+    tx, err := database.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    
+    if err := r.checkCodesTx(tx, item); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    
+    query := `INSERT INTO item (item_category, item_name, sku, item_price, id_tax_rate) 
+              VALUES (?, ?, ?, ?, ?)`
               
-    result, err := database.DB.Exec(query, 
+    result, err := tx.Exec(query, 
         item.CategoryID, 
         item.Name, 
-        item.Price)
+        nullableString(item.SKU), 
+        item.Price, 
+        nullableID(item.TaxRateID))
         
     if err != nil {
-        return nil, err
+        tx.Rollback()
+        return nil, duplicateCode(err, item.SKU)
     }
     
     // Get the last inserted ID
     lastID, err := result.LastInsertId()
     if err != nil {
+        tx.Rollback()
         return nil, err
     }
     
     item.ID = int(lastID)
+    
+    if err := r.saveBarcodesTx(tx, item); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    
+    if err := tx.Commit(); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    
     return item, nil
 }
 
-// GetItem retrieves an item by ID from the database
+// GetItem retrieves an item by ID, with its barcodes, from the database
 func (r *ItemRepository) GetItem(id int) (*model.Item, error) {
     item := &model.Item{}
     
-    query := "SELECT id_item, item_category, item_name, item_price FROM item WHERE id_item = ?"
-    err := database.DB.QueryRow(query, id).Scan(&item.ID, &item.CategoryID, &item.Name, &item.Price)
+    query := "SELECT " + itemColumns + " FROM item WHERE id_item = ?"
+    err := scanItem(database.DB.QueryRow(query, id), item)
     
     if err != nil {
         if err == sql.ErrNoRows {
@@ -53,14 +126,46 @@ func (r *ItemRepository) GetItem(id int) (*model.Item, error) {
         return nil, err
     }
     
+    item.Barcodes, err = itemBarcodes(database.DB, id)
+    if err != nil {
+        return nil, err
+    }
+    
     return item, nil
 }
 
+// GetItemByCode retrieves the item, with its barcodes, that a scanned code names: one of its
+// barcodes or its SKU. UPC-A codes are found whether they were saved or scanned as EAN-13.
+func (r *ItemRepository) GetItemByCode(code string) (*model.Item, error) {
+    codes := model.ScanCodes(code)
+    if len(codes) == 0 {
+        return nil, fmt.Errorf("item with code %s not found", code)
+    }
+    
+    // Codes are unique across items, so at most one item matches
+    in, args := codeList(codes)
+    query := `SELECT id_item FROM item_barcode WHERE code IN (` + in + `)
+              UNION
+              SELECT id_item FROM item WHERE sku IN (` + in + `)
+              LIMIT 1`
+              
+    var id int
+    err := database.DB.QueryRow(query, append(args, args...)...).Scan(&id)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, fmt.Errorf("item with code %s not found", code)
+        }
+        return nil, err
+    }
+    
+    return r.GetItem(id)
+}
+
 // GetAllItems retrieves all items from the database
 func (r *ItemRepository) GetAllItems() ([]model.Item, error) {
     var items []model.Item
     
-    query := `SELECT id_item, item_category, item_name, item_price 
+    query := `SELECT ` + itemColumns + ` 
               FROM item ORDER BY item_name`
               
     rows, err := database.DB.Query(query)
@@ -71,12 +176,7 @@ func (r *ItemRepository) GetAllItems() ([]model.Item, error) {
     
     for rows.Next() {
         var item model.Item
-        err := rows.Scan(
-            &item.ID,
-            &item.CategoryID,
-            &item.Name,
-            &item.Price,
-        )
+        err := scanItem(rows, &item)
         
         if err != nil {
             return nil, err
@@ -96,7 +196,7 @@ func (r *ItemRepository) GetAllItems() ([]model.Item, error) {
 func (r *ItemRepository) GetItemsByCategory(categoryID int) ([]model.Item, error) {
     var items []model.Item
     
-    query := `SELECT id_item, item_category, item_name, item_price 
+    query := `SELECT ` + itemColumns + ` 
               FROM item WHERE item_category = ? 
               ORDER BY item_name`
               
@@ -108,12 +208,7 @@ func (r *ItemRepository) GetItemsByCategory(categoryID int) ([]model.Item, error
     
     for rows.Next() {
         var item model.Item
-        err := rows.Scan(
-            &item.ID,
-            &item.CategoryID,
-            &item.Name,
-            &item.Price,
-        )
+        err := scanItem(rows, &item)
         
         if err != nil {
             return nil, err
@@ -129,21 +224,57 @@ func (r *ItemRepository) GetItemsByCategory(categoryID int) ([]model.Item, error
     return items, nil
 }
 
-// UpdateItem updates an existing item in the database
+// UpdateItem updates an existing item in the database. Its barcodes are replaced by the
+// item's when they are set and kept as saved when they are nil.
+// It returns a *DuplicateCodeError when another item already uses its SKU or one of its barcodes.
 func (r *ItemRepository) UpdateItem(item *model.Item) (*model.Item, error) {
+    tx, err := database.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    
+    if err := r.checkCodesTx(tx, item); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    
     query := `UPDATE item SET 
               item_category = ?, 
               item_name = ?, 
-              item_price = ? 
+              sku = ?, 
+              item_price = ?, 
+              id_tax_rate = ? 
               WHERE id_item = ?`
               
-    _, err := database.DB.Exec(query,
+    _, err = tx.Exec(query,
         item.CategoryID,
         item.Name,
+        nullableString(item.SKU),
         item.Price,
+        nullableID(item.TaxRateID),
         item.ID)
         
     if err != nil {
+        tx.Rollback()
+        return nil, duplicateCode(err, item.SKU)
+    }
+    
+    if item.Barcodes != nil {
+        if _, err := tx.Exec(`DELETE FROM item_barcode WHERE id_item = ?`, item.ID); err != nil {
+            tx.Rollback()
+            return nil, err
+        }
+        if err := r.saveBarcodesTx(tx, item); err != nil {
+            tx.Rollback()
+            return nil, err
+        }
+    } else if item.Barcodes, err = itemBarcodes(tx, item.ID); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    
+    if err := tx.Commit(); err != nil {
+        tx.Rollback()
         return nil, err
     }
     
@@ -161,3 +292,100 @@ func (r *ItemRepository) DeleteItem(id int) error {
     
     return nil
 }
+
+// GetBarcodesByItem retrieves the barcodes of an item
+func (r *ItemRepository) GetBarcodesByItem(itemID int) ([]model.ItemBarcode, error) {
+    return itemBarcodes(database.DB, itemID)
+}
+
+// checkCodesTx returns a *DuplicateCodeError when an item other than the given one already
+// uses the item's SKU or one of its barcodes, in any of the forms a scan could find them under.
+// Barcodes left nil on an update are already saved and are not checked again.
+func (r *ItemRepository) checkCodesTx(tx *sql.Tx, item *model.Item) error {
+    var codes []string
+    if item.SKU != "" {
+        codes = append(codes, item.SKU)
+    }
+    for _, barcode := range item.Barcodes {
+        codes = append(codes, barcode.Code)
+    }
+    
+    for _, code := range codes {
+        in, forms := codeList(model.ScanCodes(code))
+        query := `SELECT id_item FROM item_barcode WHERE code IN (` + in + `) AND id_item <> ?
+                  UNION
+                  SELECT id_item FROM item WHERE sku IN (` + in + `) AND id_item <> ?
+                  LIMIT 1`
+                  
+        args := append(append([]interface{}{}, forms...), item.ID)
+        args = append(append(args, forms...), item.ID)
+        
+        var owner int
+        err := tx.QueryRow(query, args...).Scan(&owner)
+        if err == nil {
+            return &DuplicateCodeError{Code: code, ItemID: owner}
+        }
+        if err != sql.ErrNoRows {
+            return err
+        }
+    }
+    
+    return nil
+}
+
+// saveBarcodesTx inserts the barcodes of an item as part of a transaction
+func (r *ItemRepository) saveBarcodesTx(tx *sql.Tx, item *model.Item) error {
+    query := `INSERT INTO item_barcode (id_item, code, type) VALUES (?, ?, ?)`
+    
+    for i := range item.Barcodes {
+        barcode := &item.Barcodes[i]
+        barcode.ItemID = item.ID
+        
+        result, err := tx.Exec(query, barcode.ItemID, barcode.Code, barcode.Type)
+        if err != nil {
+            return duplicateCode(err, barcode.Code)
+        }
+        
+        lastID, err := result.LastInsertId()
+        if err != nil {
+            return err
+        }
+        barcode.ID = int(lastID)
+    }
+    
+    return nil
+}
+
+// itemBarcodes retrieves the barcodes of an item from either the database or a transaction
+func itemBarcodes(q queryer, itemID int) ([]model.ItemBarcode, error) {
+    barcodes := []model.ItemBarcode{}
+    
+    query := `SELECT id_barcode, id_item, code, type FROM item_barcode WHERE id_item = ? ORDER BY id_barcode`
+    
+    rows, err := q.Query(query, itemID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    
+    for rows.Next() {
+        var barcode model.ItemBarcode
+        if err := rows.Scan(&barcode.ID, &barcode.ItemID, &barcode.Code, &barcode.Type); err != nil {
+            return nil, err
+        }
+        barcodes = append(barcodes, barcode)
+    }
+    
+    return barcodes, rows.Err()
+}
+
+// codeList returns the placeholders and arguments of an IN list of codes
+func codeList(codes []string) (string, []interface{}) {
+    placeholders := make([]string, len(codes))
+    args := make([]interface{}, len(codes))
+    for i, code := range codes {
+        placeholders[i] = "?"
+        args[i] = code
+    }
+    return strings.Join(placeholders, ", "), args
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// LoginAttemptRepository handles database operations for failed login counters
+type LoginAttemptRepository struct{}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{}
+}
+
+// GetLoginAttempt retrieves the counter for a key.
+// A key with no recorded failures yields an empty attempt rather than an error.
+func (r *LoginAttemptRepository) GetLoginAttempt(key string) (*model.LoginAttempt, error) {
+	attempt := &model.LoginAttempt{Key: key}
+	var lockedUntil sql.NullTime
+
+	query := `SELECT failures, last_failed_at, locked_until FROM login_attempt WHERE attempt_key = ?`
+
+	err := database.DB.QueryRow(query, key).Scan(
+		&attempt.Failures,
+		&attempt.LastFailedAt,
+		&lockedUntil,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return attempt, nil
+		}
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		attempt.LockedUntil = lockedUntil.Time
+	}
+
+	return attempt, nil
+}
+
+// SaveLoginAttempt inserts or replaces the counter for a key
+func (r *LoginAttemptRepository) SaveLoginAttempt(attempt *model.LoginAttempt) error {
+	var lockedUntil sql.NullTime
+	if !attempt.LockedUntil.IsZero() {
+		lockedUntil = sql.NullTime{Time: attempt.LockedUntil, Valid: true}
+	}
+
+	query := `INSERT INTO login_attempt (attempt_key, failures, last_failed_at, locked_until)
+	          VALUES (?, ?, ?, ?)
+	          ON DUPLICATE KEY UPDATE
+	          failures = VALUES(failures),
+	          last_failed_at = VALUES(last_failed_at),
+	          locked_until = VALUES(locked_until)`
+
+	_, err := database.DB.Exec(query, attempt.Key, attempt.Failures, attempt.LastFailedAt, lockedUntil)
+	return err
+}
+
+// GetLockedAttempts retrieves every key that is locked at the given time
+func (r *LoginAttemptRepository) GetLockedAttempts(now time.Time) ([]model.LoginAttempt, error) {
+	var attempts []model.LoginAttempt
+
+	query := `SELECT attempt_key, failures, last_failed_at, locked_until
+	          FROM login_attempt
+	          WHERE locked_until > ?
+	          ORDER BY locked_until DESC`
+
+	rows, err := database.DB.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt model.LoginAttempt
+		err := rows.Scan(
+			&attempt.Key,
+			&attempt.Failures,
+			&attempt.LastFailedAt,
+			&attempt.LockedUntil,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// DeleteLoginAttempt clears the counter and any lock for a key
+func (r *LoginAttemptRepository) DeleteLoginAttempt(key string) error {
+	query := `DELETE FROM login_attempt WHERE attempt_key = ?`
+
+	_, err := database.DB.Exec(query, key)
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// LoginChallengeRepository handles database operations for pending two-factor login steps
+type LoginChallengeRepository struct{}
+
+// NewLoginChallengeRepository creates a new LoginChallengeRepository
+func NewLoginChallengeRepository() *LoginChallengeRepository {
+	return &LoginChallengeRepository{}
+}
+
+// CreateLoginChallenge inserts a new challenge into the database
+func (r *LoginChallengeRepository) CreateLoginChallenge(challenge *model.LoginChallenge) (*model.LoginChallenge, error) {
+	query := `INSERT INTO login_challenge (id_challenge, id_user, token_hash, device, created_at, expires_at)
+	          VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := database.DB.Exec(query,
+		challenge.ID,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.Device,
+		challenge.CreatedAt,
+		challenge.ExpiresAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// GetLoginChallengeByTokenHash retrieves a challenge by the digest of its token
+func (r *LoginChallengeRepository) GetLoginChallengeByTokenHash(tokenHash string) (*model.LoginChallenge, error) {
+	challenge := &model.LoginChallenge{}
+
+	query := `SELECT id_challenge, id_user, token_hash, device, created_at, expires_at
+	          FROM login_challenge WHERE token_hash = ?`
+
+	err := database.DB.QueryRow(query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Device,
+		&challenge.CreatedAt,
+		&challenge.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("login challenge not found")
+		}
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// DeleteLoginChallenge removes a challenge once it has been redeemed or has expired
+func (r *LoginChallengeRepository) DeleteLoginChallenge(id string) error {
+	query := `DELETE FROM login_challenge WHERE id_challenge = ?`
+
+	_, err := database.DB.Exec(query, id)
+	return err
+}
@@ -18,11 +18,12 @@ func NewMemberPointRepository() *MemberPointRepository {
 
 // CreateMemberPoint inserts a new member point transaction into the database
 func (r *MemberPointRepository) CreateMemberPoint(memberPoint *model.MemberPoint) (*model.MemberPoint, error) {
-	query := `INSERT INTO member_point (id_member, type, points, transaction_date) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO member_point (id_member, id_sales, type, points, transaction_date) 
+	          VALUES (?, ?, ?, ?, ?)`
 	          
 	result, err := database.DB.Exec(query, 
 		memberPoint.MemberID,
+		nullableID(memberPoint.SalesID),
 		memberPoint.Type,
 		memberPoint.Points,
 		time.Now())
@@ -43,11 +44,12 @@ func (r *MemberPointRepository) CreateMemberPoint(memberPoint *model.MemberPoint
 
 // CreateMemberPointTx inserts a new member point transaction as part of a transaction
 func (r *MemberPointRepository) CreateMemberPointTx(tx *sql.Tx, memberPoint *model.MemberPoint) (*model.MemberPoint, error) {
-	query := `INSERT INTO member_point (id_member, type, points, transaction_date) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO member_point (id_member, id_sales, type, points, transaction_date) 
+	          VALUES (?, ?, ?, ?, ?)`
 	          
 	result, err := tx.Exec(query, 
 		memberPoint.MemberID,
+		nullableID(memberPoint.SalesID),
 		memberPoint.Type,
 		memberPoint.Points,
 		time.Now())
@@ -70,13 +72,14 @@ func (r *MemberPointRepository) CreateMemberPointTx(tx *sql.Tx, memberPoint *mod
 func (r *MemberPointRepository) GetMemberPoint(id int) (*model.MemberPoint, error) {
 	memberPoint := &model.MemberPoint{}
 	
-	query := `SELECT id_point, id_member, type, points, transaction_date 
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points, transaction_date 
 	          FROM member_point WHERE id_point = ?`
 	          
 	var transactionDate time.Time
 	err := database.DB.QueryRow(query, id).Scan(
 		&memberPoint.ID,
 		&memberPoint.MemberID,
+		&memberPoint.SalesID,
 		&memberPoint.Type,
 		&memberPoint.Points,
 		&transactionDate,
@@ -92,11 +95,36 @@ func (r *MemberPointRepository) GetMemberPoint(id int) (*model.MemberPoint, erro
 	return memberPoint, nil
 }
 
+// GetMemberPointForUpdateTx retrieves and locks a member point transaction as part of a transaction
+func (r *MemberPointRepository) GetMemberPointForUpdateTx(tx *sql.Tx, id int) (*model.MemberPoint, error) {
+	memberPoint := &model.MemberPoint{}
+	
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points 
+	          FROM member_point WHERE id_point = ? FOR UPDATE`
+	          
+	err := tx.QueryRow(query, id).Scan(
+		&memberPoint.ID,
+		&memberPoint.MemberID,
+		&memberPoint.SalesID,
+		&memberPoint.Type,
+		&memberPoint.Points,
+	)
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("member point transaction with ID %d not found", id)
+		}
+		return nil, err
+	}
+	
+	return memberPoint, nil
+}
+
 // GetAllMemberPoints retrieves all member point transactions
 func (r *MemberPointRepository) GetAllMemberPoints() ([]model.MemberPoint, error) {
 	var memberPoints []model.MemberPoint
 	
-	query := `SELECT id_point, id_member, type, points, transaction_date 
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points, transaction_date 
 	          FROM member_point ORDER BY transaction_date DESC`
 	          
 	rows, err := database.DB.Query(query)
@@ -111,6 +139,7 @@ func (r *MemberPointRepository) GetAllMemberPoints() ([]model.MemberPoint, error
 		err := rows.Scan(
 			&memberPoint.ID,
 			&memberPoint.MemberID,
+			&memberPoint.SalesID,
 			&memberPoint.Type,
 			&memberPoint.Points,
 			&transactionDate,
@@ -134,7 +163,7 @@ func (r *MemberPointRepository) GetAllMemberPoints() ([]model.MemberPoint, error
 func (r *MemberPointRepository) GetMemberPointsByMember(memberID int) ([]model.MemberPoint, error) {
 	var memberPoints []model.MemberPoint
 	
-	query := `SELECT id_point, id_member, type, points, transaction_date 
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points, transaction_date 
 	          FROM member_point 
 	          WHERE id_member = ? 
 	          ORDER BY transaction_date DESC`
@@ -151,6 +180,7 @@ func (r *MemberPointRepository) GetMemberPointsByMember(memberID int) ([]model.M
 		err := rows.Scan(
 			&memberPoint.ID,
 			&memberPoint.MemberID,
+			&memberPoint.SalesID,
 			&memberPoint.Type,
 			&memberPoint.Points,
 			&transactionDate,
@@ -174,7 +204,7 @@ func (r *MemberPointRepository) GetMemberPointsByMember(memberID int) ([]model.M
 func (r *MemberPointRepository) GetMemberPointsByType(pointType model.PointType) ([]model.MemberPoint, error) {
 	var memberPoints []model.MemberPoint
 	
-	query := `SELECT id_point, id_member, type, points, transaction_date 
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points, transaction_date 
 	          FROM member_point 
 	          WHERE type = ? 
 	          ORDER BY transaction_date DESC`
@@ -191,6 +221,7 @@ func (r *MemberPointRepository) GetMemberPointsByType(pointType model.PointType)
 		err := rows.Scan(
 			&memberPoint.ID,
 			&memberPoint.MemberID,
+			&memberPoint.SalesID,
 			&memberPoint.Type,
 			&memberPoint.Points,
 			&transactionDate,
@@ -214,7 +245,7 @@ func (r *MemberPointRepository) GetMemberPointsByType(pointType model.PointType)
 func (r *MemberPointRepository) GetMemberPointsByMemberAndType(memberID int, pointType model.PointType) ([]model.MemberPoint, error) {
 	var memberPoints []model.MemberPoint
 	
-	query := `SELECT id_point, id_member, type, points, transaction_date 
+	query := `SELECT id_point, id_member, COALESCE(id_sales, 0), type, points, transaction_date 
 	          FROM member_point 
 	          WHERE id_member = ? AND type = ? 
 	          ORDER BY transaction_date DESC`
@@ -231,6 +262,7 @@ func (r *MemberPointRepository) GetMemberPointsByMemberAndType(memberID int, poi
 		err := rows.Scan(
 			&memberPoint.ID,
 			&memberPoint.MemberID,
+			&memberPoint.SalesID,
 			&memberPoint.Type,
 			&memberPoint.Points,
 			&transactionDate,
@@ -303,3 +335,26 @@ func (r *MemberPointRepository) UpdateMemberPoint(memberPoint *model.MemberPoint
 	
 	return memberPoint, nil
 }
+
+// GetEarnedPointsBySales sums the points a sale earned
+func (r *MemberPointRepository) GetEarnedPointsBySales(salesID int) (int, error) {
+	query := `SELECT COALESCE(SUM(points), 0) FROM member_point WHERE id_sales = ? AND type = ?`
+
+	var points int
+	err := database.DB.QueryRow(query, salesID, model.PointTypeEarned).Scan(&points)
+	return points, err
+}
+
+// GetEarnedPointsBySalesTx sums the points a sale earned as part of a transaction
+func (r *MemberPointRepository) GetEarnedPointsBySalesTx(tx *sql.Tx, salesID int) (int, error) {
+	query := `SELECT COALESCE(SUM(points), 0) FROM member_point WHERE id_sales = ? AND type = ?`
+
+	var points int
+	err := tx.QueryRow(query, salesID, model.PointTypeEarned).Scan(&points)
+	return points, err
+}
+
+// nullableID maps a zero ID to NULL for optional foreign key columns
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
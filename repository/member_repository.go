@@ -17,14 +17,15 @@ func NewMemberRepository() *MemberRepository {
 
 // CreateMember inserts a new member into the database
 func (r *MemberRepository) CreateMember(member *model.Member) (*model.Member, error) {
-	query := `INSERT INTO member (member_name, member_phone, join_date, member_points) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO member (member_name, member_phone, join_date, member_points, password_hash) 
+	          VALUES (?, ?, ?, ?, ?)`
 	          
 	result, err := database.DB.Exec(query, 
 		member.Name,
 		member.Phone,
 		member.JoinDate,
-		member.Points)
+		member.Points,
+		member.PasswordHash)
 		
 	if err != nil {
 		return nil, err
@@ -207,3 +208,90 @@ func (r *MemberRepository) UpdateMemberTx(tx *sql.Tx, member *model.Member) (*mo
     return member, nil
 }
 
+// GetMemberCredentialsByPhone finds a member by phone number, including the password hash and token
+func (r *MemberRepository) GetMemberCredentialsByPhone(phone int) (*model.Member, error) {
+	member := &model.Member{}
+	
+	query := `SELECT id_member, member_name, member_phone, join_date, member_points, password_hash, token 
+	          FROM member WHERE member_phone = ?`
+	          
+	err := database.DB.QueryRow(query, phone).Scan(
+		&member.ID,
+		&member.Name,
+		&member.Phone,
+		&member.JoinDate,
+		&member.Points,
+		&member.PasswordHash,
+		&member.Token,
+	)
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("member with phone %d not found", phone)
+		}
+		return nil, err
+	}
+	
+	return member, nil
+}
+
+// GetMemberByTokenHash finds a member by the digest of their login token
+func (r *MemberRepository) GetMemberByTokenHash(tokenHash string) (*model.Member, error) {
+	member := &model.Member{}
+	
+	query := `SELECT id_member, member_name, member_phone, join_date, member_points 
+	          FROM member WHERE token = ?`
+	          
+	err := database.DB.QueryRow(query, tokenHash).Scan(
+		&member.ID,
+		&member.Name,
+		&member.Phone,
+		&member.JoinDate,
+		&member.Points,
+	)
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("member with token not found")
+		}
+		return nil, err
+	}
+	
+	return member, nil
+}
+
+// UpdateMemberToken stores the digest of a member's login token; an empty value logs the member out
+func (r *MemberRepository) UpdateMemberToken(id int, tokenHash string) error {
+	query := `UPDATE member SET token = ? WHERE id_member = ?`
+	
+	_, err := database.DB.Exec(query, tokenHash, id)
+	return err
+}
+
+// UpdateMemberPassword stores a new password hash for a member
+func (r *MemberRepository) UpdateMemberPassword(id int, passwordHash string) error {
+	query := `UPDATE member SET password_hash = ? WHERE id_member = ?`
+	
+	_, err := database.DB.Exec(query, passwordHash, id)
+	return err
+}
+
+// GetPointsForUpdateTx reads and locks a member's points balance as part of a transaction
+func (r *MemberRepository) GetPointsForUpdateTx(tx *sql.Tx, id int) (int, error) {
+	query := `SELECT member_points FROM member WHERE id_member = ? FOR UPDATE`
+
+	var points int
+	err := tx.QueryRow(query, id).Scan(&points)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("member with ID %d not found", id)
+	}
+	return points, err
+}
+
+// AdjustPointsTx adds delta to a member's points balance as part of a transaction
+func (r *MemberRepository) AdjustPointsTx(tx *sql.Tx, id int, delta int) error {
+	query := `UPDATE member SET member_points = member_points + ? WHERE id_member = ?`
+
+	_, err := tx.Exec(query, delta, id)
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// PasswordResetRepository handles database operations for password reset codes
+type PasswordResetRepository struct{}
+
+// NewPasswordResetRepository creates a new PasswordResetRepository
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{}
+}
+
+// CreatePasswordReset stores a new reset code, replacing any unused code of the same user
+func (r *PasswordResetRepository) CreatePasswordReset(reset *model.PasswordReset) (*model.PasswordReset, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM password_reset WHERE id_user = ? AND used_at IS NULL`, reset.UserID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	query := `INSERT INTO password_reset (id_user, code_hash, created_by, created_at, expires_at)
+	          VALUES (?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		reset.UserID,
+		reset.CodeHash,
+		reset.CreatedBy,
+		reset.CreatedAt,
+		reset.ExpiresAt)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Get the last inserted ID
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	reset.ID = int(lastID)
+	return reset, nil
+}
+
+// GetActivePasswordReset finds an unused, unexpired reset code of a user by its digest
+func (r *PasswordResetRepository) GetActivePasswordReset(userID int, codeHash string, now time.Time) (*model.PasswordReset, error) {
+	reset := &model.PasswordReset{}
+
+	query := `SELECT id_reset, id_user, code_hash, created_by, created_at, expires_at
+	          FROM password_reset
+	          WHERE id_user = ? AND code_hash = ? AND used_at IS NULL AND expires_at > ?`
+
+	err := database.DB.QueryRow(query, userID, codeHash, now).Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.CodeHash,
+		&reset.CreatedBy,
+		&reset.CreatedAt,
+		&reset.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reset code not found or expired")
+		}
+		return nil, err
+	}
+
+	return reset, nil
+}
+
+// MarkPasswordResetUsed consumes a reset code so it cannot be used again
+func (r *PasswordResetRepository) MarkPasswordResetUsed(id int, usedAt time.Time) error {
+	query := `UPDATE password_reset SET used_at = ? WHERE id_reset = ?`
+
+	_, err := database.DB.Exec(query, usedAt, id)
+	return err
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// PromotionRepository handles database operations for promotions
+type PromotionRepository struct{}
+
+// NewPromotionRepository creates a new PromotionRepository
+func NewPromotionRepository() *PromotionRepository {
+	return &PromotionRepository{}
+}
+
+// promotionColumns lists the promotion columns in the order scanPromotion reads them
+const promotionColumns = `id_promotion, promotion_name, type, scope, target_id, value, buy_qty, get_qty, 
+	          min_spend, starts_at, ends_at, daily_from, daily_to, active`
+
+// CreatePromotion inserts a new promotion into the database
+func (r *PromotionRepository) CreatePromotion(promotion *model.Promotion) (*model.Promotion, error) {
+	query := `INSERT INTO promotion (promotion_name, type, scope, target_id, value, buy_qty, get_qty, 
+	          min_spend, starts_at, ends_at, daily_from, daily_to, active) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := database.DB.Exec(query,
+		promotion.Name,
+		promotion.Type,
+		promotion.Scope,
+		promotion.TargetID,
+		promotion.Value,
+		promotion.BuyQty,
+		promotion.GetQty,
+		promotion.MinSpend,
+		nullableTime(promotion.StartsAt),
+		nullableTime(promotion.EndsAt),
+		promotion.DailyFrom,
+		promotion.DailyTo,
+		promotion.Active)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.ID = int(lastID)
+	return promotion, nil
+}
+
+// GetPromotion retrieves a promotion by ID from the database
+func (r *PromotionRepository) GetPromotion(id int) (*model.Promotion, error) {
+	promotion := &model.Promotion{}
+
+	query := `SELECT ` + promotionColumns + ` FROM promotion WHERE id_promotion = ?`
+
+	err := scanPromotion(database.DB.QueryRow(query, id), promotion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("promotion with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// GetAllPromotions retrieves all promotions from the database
+func (r *PromotionRepository) GetAllPromotions() ([]model.Promotion, error) {
+	return r.queryPromotions(`SELECT ` + promotionColumns + ` FROM promotion ORDER BY id_promotion`)
+}
+
+// GetActivePromotions retrieves the promotions that are switched on; whether they run at a
+// given moment is left to their date range and daily window
+func (r *PromotionRepository) GetActivePromotions() ([]model.Promotion, error) {
+	return r.queryPromotions(`SELECT ` + promotionColumns + ` FROM promotion WHERE active = 1 ORDER BY id_promotion`)
+}
+
+// UpdatePromotion updates an existing promotion in the database
+func (r *PromotionRepository) UpdatePromotion(promotion *model.Promotion) (*model.Promotion, error) {
+	query := `UPDATE promotion SET 
+	          promotion_name = ?, 
+	          type = ?, 
+	          scope = ?, 
+	          target_id = ?, 
+	          value = ?, 
+	          buy_qty = ?, 
+	          get_qty = ?, 
+	          min_spend = ?, 
+	          starts_at = ?, 
+	          ends_at = ?, 
+	          daily_from = ?, 
+	          daily_to = ?, 
+	          active = ? 
+	          WHERE id_promotion = ?`
+
+	_, err := database.DB.Exec(query,
+		promotion.Name,
+		promotion.Type,
+		promotion.Scope,
+		promotion.TargetID,
+		promotion.Value,
+		promotion.BuyQty,
+		promotion.GetQty,
+		promotion.MinSpend,
+		nullableTime(promotion.StartsAt),
+		nullableTime(promotion.EndsAt),
+		promotion.DailyFrom,
+		promotion.DailyTo,
+		promotion.Active,
+		promotion.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// DeletePromotion deletes a promotion from the database
+func (r *PromotionRepository) DeletePromotion(id int) error {
+	query := `DELETE FROM promotion WHERE id_promotion = ?`
+
+	_, err := database.DB.Exec(query, id)
+	return err
+}
+
+// PromotionHasSales reports whether a promotion was ever applied to a sales line
+func (r *PromotionRepository) PromotionHasSales(id int) (bool, error) {
+	var exists bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM sales_item_promotion WHERE id_promotion = ?)`
+
+	err := database.DB.QueryRow(query, id).Scan(&exists)
+	return exists, err
+}
+
+// GetPromotionUsage sums up the discounts every promotion gave on completed and refunded sales
+// dated from up to but excluding to, as Unix timestamps; a zero bound is left open
+func (r *PromotionRepository) GetPromotionUsage(from, to int) ([]model.PromotionUsage, error) {
+	var usage []model.PromotionUsage
+
+	query := `SELECT p.id_promotion, p.promotion_name, COUNT(DISTINCT si.id_sales), COUNT(*), SUM(sip.discount)
+	          FROM sales_item_promotion sip
+	          JOIN promotion p ON p.id_promotion = sip.id_promotion
+	          JOIN sales_item si ON si.id_sales_item = sip.id_sales_item
+	          JOIN sales_basket sb ON sb.id_sales = si.id_sales
+	          WHERE sb.status IN (?, ?)
+	            AND (? = 0 OR sb.sales_date >= ?)
+	            AND (? = 0 OR sb.sales_date < ?)
+	          GROUP BY p.id_promotion, p.promotion_name
+	          ORDER BY SUM(sip.discount) DESC`
+
+	rows, err := database.DB.Query(query,
+		model.SalesStatusCompleted, model.SalesStatusRefunded,
+		from, from,
+		to, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.PromotionUsage
+		if err := rows.Scan(&row.PromotionID, &row.Name, &row.Sales, &row.Lines, &row.Discount); err != nil {
+			return nil, err
+		}
+
+		usage = append(usage, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// queryPromotions runs a query selecting promotionColumns and scans every row
+func (r *PromotionRepository) queryPromotions(query string, args ...interface{}) ([]model.Promotion, error) {
+	var promotions []model.Promotion
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var promotion model.Promotion
+		if err := scanPromotion(rows, &promotion); err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// scanPromotion scans a row selected with promotionColumns
+func scanPromotion(row interface{ Scan(...interface{}) error }, promotion *model.Promotion) error {
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&promotion.Type,
+		&promotion.Scope,
+		&promotion.TargetID,
+		&promotion.Value,
+		&promotion.BuyQty,
+		&promotion.GetQty,
+		&promotion.MinSpend,
+		&startsAt,
+		&endsAt,
+		&promotion.DailyFrom,
+		&promotion.DailyTo,
+		&promotion.Active,
+	)
+	if err != nil {
+		return err
+	}
+
+	promotion.StartsAt = startsAt.Time
+	promotion.EndsAt = endsAt.Time
+	return nil
+}
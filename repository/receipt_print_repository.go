@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"database/sql"
+	"go-pos/model"
+)
+
+// ReceiptPrintRepository handles database operations for receipt prints
+type ReceiptPrintRepository struct{}
+
+// NewReceiptPrintRepository creates a new ReceiptPrintRepository
+func NewReceiptPrintRepository() *ReceiptPrintRepository {
+	return &ReceiptPrintRepository{}
+}
+
+// CreateReceiptPrintTx records a print of a sale's receipt as part of a transaction
+func (r *ReceiptPrintRepository) CreateReceiptPrintTx(tx *sql.Tx, receiptPrint *model.ReceiptPrint) (*model.ReceiptPrint, error) {
+	query := `INSERT INTO receipt_print (id_sales, id_user, format, is_copy, printed_at) 
+	          VALUES (?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		receiptPrint.SalesID,
+		receiptPrint.UserID,
+		receiptPrint.Format,
+		receiptPrint.IsCopy,
+		receiptPrint.PrintedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	receiptPrint.ID = int(lastID)
+	return receiptPrint, nil
+}
+
+// CountReceiptPrintsTx counts the receipts already printed for a sale as part of a transaction
+func (r *ReceiptPrintRepository) CountReceiptPrintsTx(tx *sql.Tx, salesID int) (int, error) {
+	query := `SELECT COUNT(*) FROM receipt_print WHERE id_sales = ?`
+
+	var count int
+	err := tx.QueryRow(query, salesID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// RoleRepository handles database operations for roles and permissions
+type RoleRepository struct{}
+
+// NewRoleRepository creates a new RoleRepository
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{}
+}
+
+// CreateRole inserts a new role into the database
+func (r *RoleRepository) CreateRole(role *model.Role) (*model.Role, error) {
+	query := `INSERT INTO role (role_name, description) VALUES (?, ?)`
+
+	result, err := database.DB.Exec(query, role.Name, role.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the last inserted ID
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	role.ID = int(lastID)
+	return role, nil
+}
+
+// GetRole retrieves a role by ID from the database
+func (r *RoleRepository) GetRole(id int) (*model.Role, error) {
+	role := &model.Role{}
+
+	query := `SELECT id_role, role_name, description FROM role WHERE id_role = ?`
+
+	err := database.DB.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.Description)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("role with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// GetAllRoles retrieves all roles from the database
+func (r *RoleRepository) GetAllRoles() ([]model.Role, error) {
+	query := `SELECT id_role, role_name, description FROM role ORDER BY role_name`
+
+	return r.queryRoles(query)
+}
+
+// GetUserRoles retrieves all roles assigned to a user
+func (r *RoleRepository) GetUserRoles(userID int) ([]model.Role, error) {
+	query := `SELECT r.id_role, r.role_name, r.description
+	          FROM role r
+	          JOIN user_role ur ON ur.id_role = r.id_role
+	          WHERE ur.id_user = ?
+	          ORDER BY r.role_name`
+
+	return r.queryRoles(query, userID)
+}
+
+// queryRoles runs a role query and scans the resulting rows
+func (r *RoleRepository) queryRoles(query string, args ...interface{}) ([]model.Role, error) {
+	var roles []model.Role
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role model.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// UpdateRole updates an existing role in the database
+func (r *RoleRepository) UpdateRole(role *model.Role) (*model.Role, error) {
+	query := `UPDATE role SET role_name = ?, description = ? WHERE id_role = ?`
+
+	_, err := database.DB.Exec(query, role.Name, role.Description, role.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DeleteRole deletes a role together with its permission and user assignments
+func (r *RoleRepository) DeleteRole(id int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM role_permission WHERE id_role = ?`,
+		`DELETE FROM user_role WHERE id_role = ?`,
+		`DELETE FROM role WHERE id_role = ?`,
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAllPermissions retrieves every known permission
+func (r *RoleRepository) GetAllPermissions() ([]model.Permission, error) {
+	query := `SELECT id_permission, code, description FROM permission ORDER BY code`
+
+	return r.queryPermissions(query)
+}
+
+// GetRolePermissions retrieves the permissions granted to a role
+func (r *RoleRepository) GetRolePermissions(roleID int) ([]model.Permission, error) {
+	query := `SELECT p.id_permission, p.code, p.description
+	          FROM permission p
+	          JOIN role_permission rp ON rp.id_permission = p.id_permission
+	          WHERE rp.id_role = ?
+	          ORDER BY p.code`
+
+	return r.queryPermissions(query, roleID)
+}
+
+// queryPermissions runs a permission query and scans the resulting rows
+func (r *RoleRepository) queryPermissions(query string, args ...interface{}) ([]model.Permission, error) {
+	var permissions []model.Permission
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var permission model.Permission
+		if err := rows.Scan(&permission.ID, &permission.Code, &permission.Description); err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// SetRolePermissions replaces the permissions granted to a role
+func (r *RoleRepository) SetRolePermissions(roleID int, codes []model.PermissionCode) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM role_permission WHERE id_role = ?`, roleID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, code := range codes {
+		result, err := tx.Exec(`INSERT INTO role_permission (id_role, id_permission)
+		                        SELECT ?, id_permission FROM permission WHERE code = ?`, roleID, code)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			tx.Rollback()
+			return fmt.Errorf("unknown permission %q", code)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetUserRoles replaces the roles assigned to a user
+func (r *RoleRepository) SetUserRoles(userID int, roleIDs []int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_role WHERE id_user = ?`, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, roleID := range roleIDs {
+		if _, err := tx.Exec(`INSERT INTO user_role (id_user, id_role) VALUES (?, ?)`, userID, roleID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UserHasPermission checks whether any of the user's roles grants the permission
+func (r *RoleRepository) UserHasPermission(userID int, code model.PermissionCode) (bool, error) {
+	var count int
+
+	query := `SELECT COUNT(*)
+	          FROM user_role ur
+	          JOIN role_permission rp ON rp.id_role = ur.id_role
+	          JOIN permission p ON p.id_permission = rp.id_permission
+	          WHERE ur.id_user = ? AND p.code = ?`
+
+	err := database.DB.QueryRow(query, userID, code).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"go-pos/database"
 	"go-pos/model"
+	"strings"
 )
 
 // SalesBasketRepository handles database operations for sales baskets
 type SalesBasketRepository struct{}
 
+// salesBasketColumns lists the sales_basket columns in the order scanSalesBasket reads them
+const salesBasketColumns = `id_sales, COALESCE(invoice_no, ''), id_user, COALESCE(id_shift, 0), id_member, sales_date, payment_method, total_amount, tax_amount, tax_mode, status, register, 
+	          void_reason, COALESCE(voided_by, 0), COALESCE(void_approved_by, 0), voided_at`
+
 // NewSalesBasketRepository creates a new SalesBasketRepository
 func NewSalesBasketRepository() *SalesBasketRepository {
 	return &SalesBasketRepository{}
@@ -17,15 +22,21 @@ func NewSalesBasketRepository() *SalesBasketRepository {
 
 // CreateSalesBasketTx inserts a new sales basket as part of a transaction
 func (r *SalesBasketRepository) CreateSalesBasketTx(tx *sql.Tx, basket *model.SalesBasket) (*model.SalesBasket, error) {
-	query := `INSERT INTO sales_basket (id_user, id_member, sales_date, payment_method, total_amount) 
-	          VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO sales_basket (invoice_no, id_user, id_shift, id_member, sales_date, payment_method, total_amount, tax_amount, tax_mode, status, register) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	          
 	result, err := tx.Exec(query, 
+		nullableString(basket.InvoiceNo),
 		basket.UserID, 
+		nullableID(basket.ShiftID),
 		basket.MemberID, 
 		basket.SalesDate, 
 		basket.PaymentMethod,
-		basket.Total)
+		basket.Total,
+		basket.TaxAmount,
+		basket.TaxMode,
+		basket.Status,
+		basket.Register)
 		
 	if err != nil {
 		return nil, err
@@ -45,17 +56,10 @@ func (r *SalesBasketRepository) CreateSalesBasketTx(tx *sql.Tx, basket *model.Sa
 func (r *SalesBasketRepository) GetSalesBasket(id int) (*model.SalesBasket, error) {
 	basket := &model.SalesBasket{}
 	
-	query := `SELECT id_sales, id_user, id_member, sales_date, payment_method, total_amount 
+	query := `SELECT ` + salesBasketColumns + ` 
 	          FROM sales_basket WHERE id_sales = ?`
 	          
-	err := database.DB.QueryRow(query, id).Scan(
-		&basket.ID,
-		&basket.UserID,
-		&basket.MemberID,
-		&basket.SalesDate,
-		&basket.PaymentMethod,
-		&basket.Total,
-	)
+	err := scanSalesBasket(database.DB.QueryRow(query, id), basket)
 	
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -69,159 +73,73 @@ func (r *SalesBasketRepository) GetSalesBasket(id int) (*model.SalesBasket, erro
 
 // GetAllSalesBaskets retrieves all sales baskets from the database
 func (r *SalesBasketRepository) GetAllSalesBaskets() ([]model.SalesBasket, error) {
-	var baskets []model.SalesBasket
-	
-	query := `SELECT id_sales, id_user, id_member, sales_date, payment_method, total_amount 
-	          FROM sales_basket ORDER BY sales_date DESC`
-	          
-	rows, err := database.DB.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	for rows.Next() {
-		var basket model.SalesBasket
-		err := rows.Scan(
-			&basket.ID,
-			&basket.UserID,
-			&basket.MemberID,
-			&basket.SalesDate,
-			&basket.PaymentMethod,
-			&basket.Total,
-		)
-		
-		if err != nil {
-			return nil, err
-		}
-		
-		baskets = append(baskets, basket)
-	}
-	
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-	
-	return baskets, nil
+	return r.GetSalesBaskets(model.SalesBasketFilter{})
 }
 
 // GetSalesBasketsByUser retrieves all sales baskets for a specific user
 func (r *SalesBasketRepository) GetSalesBasketsByUser(userID int) ([]model.SalesBasket, error) {
-	var baskets []model.SalesBasket
-	
-	query := `SELECT id_sales, id_user, id_member, sales_date, payment_method, total_amount 
-	          FROM sales_basket 
-	          WHERE id_user = ? 
-	          ORDER BY sales_date DESC`
-	          
-	rows, err := database.DB.Query(query, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	for rows.Next() {
-		var basket model.SalesBasket
-		err := rows.Scan(
-			&basket.ID,
-			&basket.UserID,
-			&basket.MemberID,
-			&basket.SalesDate,
-			&basket.PaymentMethod,
-			&basket.Total,
-		)
-		
-		if err != nil {
-			return nil, err
-		}
-		
-		baskets = append(baskets, basket)
-	}
-	
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-	
-	return baskets, nil
+	return r.GetSalesBaskets(model.SalesBasketFilter{UserID: userID})
 }
 
 // GetSalesBasketsByMember retrieves all sales baskets for a specific member
 func (r *SalesBasketRepository) GetSalesBasketsByMember(memberID int) ([]model.SalesBasket, error) {
-	var baskets []model.SalesBasket
-	
-	query := `SELECT id_sales, id_user, id_member, sales_date, payment_method, total_amount 
-	          FROM sales_basket 
-	          WHERE id_member = ? 
-	          ORDER BY sales_date DESC`
-	          
-	rows, err := database.DB.Query(query, memberID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	for rows.Next() {
-		var basket model.SalesBasket
-		err := rows.Scan(
-			&basket.ID,
-			&basket.UserID,
-			&basket.MemberID,
-			&basket.SalesDate,
-			&basket.PaymentMethod,
-			&basket.Total,
-		)
-		
-		if err != nil {
-			return nil, err
-		}
-		
-		baskets = append(baskets, basket)
-	}
-	
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-	
-	return baskets, nil
+	return r.GetSalesBaskets(model.SalesBasketFilter{MemberID: memberID})
 }
 
 // GetSalesBasketsByUserAndMember retrieves all sales baskets for a specific user and member
 func (r *SalesBasketRepository) GetSalesBasketsByUserAndMember(userID, memberID int) ([]model.SalesBasket, error) {
+	return r.GetSalesBaskets(model.SalesBasketFilter{UserID: userID, MemberID: memberID})
+}
+
+// GetSalesBaskets retrieves the sales baskets matching the filter, newest first
+func (r *SalesBasketRepository) GetSalesBaskets(filter model.SalesBasketFilter) ([]model.SalesBasket, error) {
 	var baskets []model.SalesBasket
-	
-	query := `SELECT id_sales, id_user, id_member, sales_date, payment_method, total_amount 
-	          FROM sales_basket 
-	          WHERE id_user = ? AND id_member = ? 
-	          ORDER BY sales_date DESC`
-	          
-	rows, err := database.DB.Query(query, userID, memberID)
+
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserID > 0 {
+		conditions = append(conditions, "id_user = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.MemberID > 0 {
+		conditions = append(conditions, "id_member = ?")
+		args = append(args, filter.MemberID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Invoice != "" {
+		conditions = append(conditions, `invoice_no LIKE ? ESCAPE '\\'`)
+		args = append(args, escapeLike(filter.Invoice)+"%")
+	}
+
+	query := `SELECT ` + salesBasketColumns + ` FROM sales_basket`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY sales_date DESC"
+
+	rows, err := database.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
+
 	for rows.Next() {
 		var basket model.SalesBasket
-		err := rows.Scan(
-			&basket.ID,
-			&basket.UserID,
-			&basket.MemberID,
-			&basket.SalesDate,
-			&basket.PaymentMethod,
-			&basket.Total,
-		)
-		
-		if err != nil {
+		if err := scanSalesBasket(rows, &basket); err != nil {
 			return nil, err
 		}
-		
+
 		baskets = append(baskets, basket)
 	}
-	
+
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	
+
 	return baskets, nil
 }
 
@@ -250,6 +168,28 @@ func (r *SalesBasketRepository) UpdateSalesBasket(basket *model.SalesBasket) (*m
 	return basket, nil
 }
 
+// RecalculateTotal sets the basket total and tax to the sums of its stored line totals and taxes
+func (r *SalesBasketRepository) RecalculateTotal(id int) error {
+	query := `UPDATE sales_basket SET 
+	          total_amount = (SELECT COALESCE(SUM(total_item_sales), 0) FROM sales_item WHERE id_sales = ?), 
+	          tax_amount = (SELECT COALESCE(SUM(tax_amount), 0) FROM sales_item WHERE id_sales = ?) 
+	          WHERE id_sales = ?`
+	
+	_, err := database.DB.Exec(query, id, id, id)
+	return err
+}
+
+// RecalculateTotalTx sets the basket total and tax to the sums of its stored line totals and taxes as part of a transaction
+func (r *SalesBasketRepository) RecalculateTotalTx(tx *sql.Tx, id int) error {
+	query := `UPDATE sales_basket SET 
+	          total_amount = (SELECT COALESCE(SUM(total_item_sales), 0) FROM sales_item WHERE id_sales = ?), 
+	          tax_amount = (SELECT COALESCE(SUM(tax_amount), 0) FROM sales_item WHERE id_sales = ?) 
+	          WHERE id_sales = ?`
+
+	_, err := tx.Exec(query, id, id, id)
+	return err
+}
+
 // DeleteSalesBasketTx deletes a sales basket as part of a transaction
 func (r *SalesBasketRepository) DeleteSalesBasketTx(tx *sql.Tx, id int) error {
 	query := `DELETE FROM sales_basket WHERE id_sales = ?`
@@ -261,3 +201,94 @@ func (r *SalesBasketRepository) DeleteSalesBasketTx(tx *sql.Tx, id int) error {
 	
 	return nil
 }
+
+// GetSalesBasketForUpdateTx retrieves and locks a sales basket as part of a transaction,
+// so that concurrent changes against the same sale are applied one after the other
+func (r *SalesBasketRepository) GetSalesBasketForUpdateTx(tx *sql.Tx, id int) (*model.SalesBasket, error) {
+	basket := &model.SalesBasket{}
+
+	query := `SELECT ` + salesBasketColumns + ` 
+	          FROM sales_basket WHERE id_sales = ? FOR UPDATE`
+
+	err := scanSalesBasket(tx.QueryRow(query, id), basket)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sales basket with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// UpdateStatusTx moves a sales basket to a new status, with the fields that change along with it, as part of a transaction
+func (r *SalesBasketRepository) UpdateStatusTx(tx *sql.Tx, basket *model.SalesBasket) error {
+	query := `UPDATE sales_basket SET 
+	          invoice_no = ?, 
+	          status = ?, 
+	          register = ?, 
+	          id_user = ?, 
+	          id_shift = ?, 
+	          sales_date = ?, 
+	          payment_method = ?, 
+	          void_reason = ?, 
+	          voided_by = ?, 
+	          void_approved_by = ?, 
+	          voided_at = ? 
+	          WHERE id_sales = ?`
+
+	_, err := tx.Exec(query,
+		nullableString(basket.InvoiceNo),
+		basket.Status,
+		basket.Register,
+		basket.UserID,
+		nullableID(basket.ShiftID),
+		basket.SalesDate,
+		basket.PaymentMethod,
+		basket.VoidReason,
+		nullableID(basket.VoidedBy),
+		nullableID(basket.VoidApprovedBy),
+		nullableTime(basket.VoidedAt),
+		basket.ID)
+	return err
+}
+
+// scanSalesBasket scans a row selected with salesBasketColumns
+func scanSalesBasket(row interface{ Scan(...interface{}) error }, basket *model.SalesBasket) error {
+	var voidedAt sql.NullTime
+	err := row.Scan(
+		&basket.ID,
+		&basket.InvoiceNo,
+		&basket.UserID,
+		&basket.ShiftID,
+		&basket.MemberID,
+		&basket.SalesDate,
+		&basket.PaymentMethod,
+		&basket.Total,
+		&basket.TaxAmount,
+		&basket.TaxMode,
+		&basket.Status,
+		&basket.Register,
+		&basket.VoidReason,
+		&basket.VoidedBy,
+		&basket.VoidApprovedBy,
+		&voidedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	basket.VoidedAt = voidedAt.Time
+	return nil
+}
+
+// nullableString maps an empty string to NULL for optional unique columns
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// escapeLike escapes the LIKE wildcards in a search term so it matches literally
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
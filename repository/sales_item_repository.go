@@ -17,13 +17,18 @@ func NewSalesItemRepository() *SalesItemRepository {
 
 // CreateSalesItem inserts a new sales item into the database
 func (r *SalesItemRepository) CreateSalesItem(item *model.SalesItem) (*model.SalesItem, error) {
-	query := `INSERT INTO sales_item (id_sales, id_item, qty, total_item_sales) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO sales_item (id_sales, id_item, qty, unit_price, discount, id_tax_rate, tax_rate, tax_amount, total_item_sales) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	          
 	result, err := database.DB.Exec(query, 
 		item.SalesID, 
 		item.ItemID, 
 		item.Qty, 
+		item.UnitPrice, 
+		item.Discount, 
+		nullableID(item.TaxRateID), 
+		item.TaxRate, 
+		item.TaxAmount, 
 		item.TotalAmount)
 		
 	if err != nil {
@@ -42,13 +47,18 @@ func (r *SalesItemRepository) CreateSalesItem(item *model.SalesItem) (*model.Sal
 
 // CreateSalesItemTx inserts a new sales item as part of a transaction
 func (r *SalesItemRepository) CreateSalesItemTx(tx *sql.Tx, item *model.SalesItem) (*model.SalesItem, error) {
-	query := `INSERT INTO sales_item (id_sales, id_item, qty, total_item_sales) 
-	          VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO sales_item (id_sales, id_item, qty, unit_price, discount, id_tax_rate, tax_rate, tax_amount, total_item_sales) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	          
 	result, err := tx.Exec(query, 
 		item.SalesID, 
 		item.ItemID, 
 		item.Qty, 
+		item.UnitPrice, 
+		item.Discount, 
+		nullableID(item.TaxRateID), 
+		item.TaxRate, 
+		item.TaxAmount, 
 		item.TotalAmount)
 		
 	if err != nil {
@@ -69,7 +79,7 @@ func (r *SalesItemRepository) CreateSalesItemTx(tx *sql.Tx, item *model.SalesIte
 func (r *SalesItemRepository) GetSalesItem(id int) (*model.SalesItem, error) {
 	salesItem := &model.SalesItem{}
 	
-	query := `SELECT id_sales_item, id_sales, id_item, qty, total_item_sales 
+	query := `SELECT id_sales_item, id_sales, id_item, qty, unit_price, discount, COALESCE(id_tax_rate, 0), tax_rate, tax_amount, total_item_sales 
 	          FROM sales_item WHERE id_sales_item = ?`
 	          
 	err := database.DB.QueryRow(query, id).Scan(
@@ -77,6 +87,11 @@ func (r *SalesItemRepository) GetSalesItem(id int) (*model.SalesItem, error) {
 		&salesItem.SalesID,
 		&salesItem.ItemID,
 		&salesItem.Qty,
+		&salesItem.UnitPrice,
+		&salesItem.Discount,
+		&salesItem.TaxRateID,
+		&salesItem.TaxRate,
+		&salesItem.TaxAmount,
 		&salesItem.TotalAmount,
 	)
 	
@@ -92,13 +107,23 @@ func (r *SalesItemRepository) GetSalesItem(id int) (*model.SalesItem, error) {
 
 // GetSalesItemsBySales retrieves all sales items for a specific sales basket
 func (r *SalesItemRepository) GetSalesItemsBySales(salesID int) ([]model.SalesItem, error) {
+	return salesItemsBySales(database.DB, salesID)
+}
+
+// GetSalesItemsBySalesTx retrieves all sales items for a specific sales basket as part of a transaction
+func (r *SalesItemRepository) GetSalesItemsBySalesTx(tx *sql.Tx, salesID int) ([]model.SalesItem, error) {
+	return salesItemsBySales(tx, salesID)
+}
+
+// salesItemsBySales retrieves the sales items of a basket from either the database or a transaction
+func salesItemsBySales(q queryer, salesID int) ([]model.SalesItem, error) {
 	var salesItems []model.SalesItem
 	
-	query := `SELECT id_sales_item, id_sales, id_item, qty, total_item_sales 
+	query := `SELECT id_sales_item, id_sales, id_item, qty, unit_price, discount, COALESCE(id_tax_rate, 0), tax_rate, tax_amount, total_item_sales 
 	          FROM sales_item 
 	          WHERE id_sales = ?`
 	          
-	rows, err := database.DB.Query(query, salesID)
+	rows, err := q.Query(query, salesID)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +136,11 @@ func (r *SalesItemRepository) GetSalesItemsBySales(salesID int) ([]model.SalesIt
 			&salesItem.SalesID,
 			&salesItem.ItemID,
 			&salesItem.Qty,
+			&salesItem.UnitPrice,
+			&salesItem.Discount,
+			&salesItem.TaxRateID,
+			&salesItem.TaxRate,
+			&salesItem.TaxAmount,
 			&salesItem.TotalAmount,
 		)
 		
@@ -132,7 +162,7 @@ func (r *SalesItemRepository) GetSalesItemsBySales(salesID int) ([]model.SalesIt
 func (r *SalesItemRepository) GetAllSalesItems() ([]model.SalesItem, error) {
 	var salesItems []model.SalesItem
 	
-	query := `SELECT id_sales_item, id_sales, id_item, qty, total_item_sales 
+	query := `SELECT id_sales_item, id_sales, id_item, qty, unit_price, discount, COALESCE(id_tax_rate, 0), tax_rate, tax_amount, total_item_sales 
 	          FROM sales_item`
 	          
 	rows, err := database.DB.Query(query)
@@ -148,6 +178,11 @@ func (r *SalesItemRepository) GetAllSalesItems() ([]model.SalesItem, error) {
 			&salesItem.SalesID,
 			&salesItem.ItemID,
 			&salesItem.Qty,
+			&salesItem.UnitPrice,
+			&salesItem.Discount,
+			&salesItem.TaxRateID,
+			&salesItem.TaxRate,
+			&salesItem.TaxAmount,
 			&salesItem.TotalAmount,
 		)
 		
@@ -171,6 +206,11 @@ func (r *SalesItemRepository) UpdateSalesItem(salesItem *model.SalesItem) (*mode
 	          id_sales = ?, 
 	          id_item = ?, 
 	          qty = ?, 
+	          unit_price = ?, 
+	          discount = ?, 
+	          id_tax_rate = ?, 
+	          tax_rate = ?, 
+	          tax_amount = ?, 
 	          total_item_sales = ? 
 	          WHERE id_sales_item = ?`
 	          
@@ -178,6 +218,11 @@ func (r *SalesItemRepository) UpdateSalesItem(salesItem *model.SalesItem) (*mode
 		salesItem.SalesID,
 		salesItem.ItemID,
 		salesItem.Qty,
+		salesItem.UnitPrice,
+		salesItem.Discount,
+		nullableID(salesItem.TaxRateID),
+		salesItem.TaxRate,
+		salesItem.TaxAmount,
 		salesItem.TotalAmount,
 		salesItem.ID)
 		
@@ -188,16 +233,31 @@ func (r *SalesItemRepository) UpdateSalesItem(salesItem *model.SalesItem) (*mode
 	return salesItem, nil
 }
 
-// DeleteSalesItem deletes a sales item from the database
+// DeleteSalesItem deletes a sales item from the database and returns the stock it drew to its batches
 func (r *SalesItemRepository) DeleteSalesItem(id int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	
+	restore := `UPDATE item_batch b
+	            JOIN sales_item_batch sib ON sib.id_batch = b.id_batch
+	            SET b.batch_qty = b.batch_qty + sib.qty
+	            WHERE sib.id_sales_item = ?`
+	
+	if _, err := tx.Exec(restore, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	
 	query := `DELETE FROM sales_item WHERE id_sales_item = ?`
 	
-	_, err := database.DB.Exec(query, id)
-	if err != nil {
+	if _, err := tx.Exec(query, id); err != nil {
+		tx.Rollback()
 		return err
 	}
 	
-	return nil
+	return tx.Commit()
 }
 
 // DeleteSalesItemsBySalesTx deletes all sales items for a specific sales basket as part of a transaction
@@ -211,3 +271,137 @@ func (r *SalesItemRepository) DeleteSalesItemsBySalesTx(tx *sql.Tx, salesID int)
 	
 	return nil
 }
+
+// CreateSalesItemBatchTx records the quantity a sales line drew from a batch as part of a transaction
+func (r *SalesItemRepository) CreateSalesItemBatchTx(tx *sql.Tx, allocation *model.SalesItemBatch) error {
+	query := `INSERT INTO sales_item_batch (id_sales_item, id_batch, qty) VALUES (?, ?, ?)`
+
+	_, err := tx.Exec(query, allocation.SalesItemID, allocation.BatchID, allocation.Qty)
+	return err
+}
+
+// GetSalesItemBatchesBySales retrieves the batch allocations of every line of a sale
+func (r *SalesItemRepository) GetSalesItemBatchesBySales(salesID int) ([]model.SalesItemBatch, error) {
+	return salesItemBatches(database.DB, salesID)
+}
+
+// GetSalesItemBatchesBySalesTx retrieves the batch allocations of every line of a sale as part of a transaction
+func (r *SalesItemRepository) GetSalesItemBatchesBySalesTx(tx *sql.Tx, salesID int) ([]model.SalesItemBatch, error) {
+	return salesItemBatches(tx, salesID)
+}
+
+// salesItemBatches retrieves the batch allocations of a sale from either the database or a transaction
+func salesItemBatches(q queryer, salesID int) ([]model.SalesItemBatch, error) {
+	var allocations []model.SalesItemBatch
+
+	query := `SELECT sib.id_sales_item, sib.id_batch, sib.qty
+	          FROM sales_item_batch sib
+	          JOIN sales_item si ON si.id_sales_item = sib.id_sales_item
+	          WHERE si.id_sales = ?
+	          ORDER BY sib.id_sales_item, sib.id_batch`
+
+	rows, err := q.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var allocation model.SalesItemBatch
+		if err := rows.Scan(&allocation.SalesItemID, &allocation.BatchID, &allocation.Qty); err != nil {
+			return nil, err
+		}
+
+		allocations = append(allocations, allocation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return allocations, nil
+}
+
+// RestoreStockBySalesTx returns the quantities drawn by every line of a sale to their batches
+func (r *SalesItemRepository) RestoreStockBySalesTx(tx *sql.Tx, salesID int) error {
+	query := `UPDATE item_batch b
+	          JOIN (SELECT sib.id_batch, SUM(sib.qty) AS qty
+	                FROM sales_item_batch sib
+	                JOIN sales_item si ON si.id_sales_item = sib.id_sales_item
+	                WHERE si.id_sales = ?
+	                GROUP BY sib.id_batch) drawn ON drawn.id_batch = b.id_batch
+	          SET b.batch_qty = b.batch_qty + drawn.qty`
+
+	_, err := tx.Exec(query, salesID)
+	return err
+}
+
+// UpdateSalesItemPricingTx saves the discount, tax and total of a repriced sales line as part of a transaction
+func (r *SalesItemRepository) UpdateSalesItemPricingTx(tx *sql.Tx, salesItem *model.SalesItem) error {
+	query := `UPDATE sales_item SET 
+	          discount = ?, 
+	          id_tax_rate = ?, 
+	          tax_rate = ?, 
+	          tax_amount = ?, 
+	          total_item_sales = ? 
+	          WHERE id_sales_item = ?`
+
+	_, err := tx.Exec(query,
+		salesItem.Discount,
+		nullableID(salesItem.TaxRateID),
+		salesItem.TaxRate,
+		salesItem.TaxAmount,
+		salesItem.TotalAmount,
+		salesItem.ID)
+	return err
+}
+
+// DeleteSalesItemPromotionsBySalesTx forgets the promotions applied to every line of a sale as part of a transaction
+func (r *SalesItemRepository) DeleteSalesItemPromotionsBySalesTx(tx *sql.Tx, salesID int) error {
+	query := `DELETE sip FROM sales_item_promotion sip
+	          JOIN sales_item si ON si.id_sales_item = sip.id_sales_item
+	          WHERE si.id_sales = ?`
+
+	_, err := tx.Exec(query, salesID)
+	return err
+}
+
+// CreateSalesItemPromotionTx records the discount a promotion gave a sales line as part of a transaction
+func (r *SalesItemRepository) CreateSalesItemPromotionTx(tx *sql.Tx, applied *model.SalesItemPromotion) error {
+	query := `INSERT INTO sales_item_promotion (id_sales_item, id_promotion, discount) VALUES (?, ?, ?)`
+
+	_, err := tx.Exec(query, applied.SalesItemID, applied.PromotionID, applied.Discount)
+	return err
+}
+
+// GetSalesItemPromotionsBySales retrieves the promotions applied to every line of a sale
+func (r *SalesItemRepository) GetSalesItemPromotionsBySales(salesID int) ([]model.SalesItemPromotion, error) {
+	var promotions []model.SalesItemPromotion
+
+	query := `SELECT sip.id_sales_item, sip.id_promotion, sip.discount
+	          FROM sales_item_promotion sip
+	          JOIN sales_item si ON si.id_sales_item = sip.id_sales_item
+	          WHERE si.id_sales = ?
+	          ORDER BY sip.id_sales_item, sip.id_promotion`
+
+	rows, err := database.DB.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var applied model.SalesItemPromotion
+		if err := rows.Scan(&applied.SalesItemID, &applied.PromotionID, &applied.Discount); err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, applied)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
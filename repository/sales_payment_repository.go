@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// SalesPaymentRepository handles database operations for sales payments
+type SalesPaymentRepository struct{}
+
+// NewSalesPaymentRepository creates a new SalesPaymentRepository
+func NewSalesPaymentRepository() *SalesPaymentRepository {
+	return &SalesPaymentRepository{}
+}
+
+// CreateSalesPaymentTx inserts a tender of a sale as part of a transaction
+func (r *SalesPaymentRepository) CreateSalesPaymentTx(tx *sql.Tx, payment *model.SalesPayment) (*model.SalesPayment, error) {
+	query := `INSERT INTO sales_payment (id_sales, payment_method, amount, tendered, change_due, reference, points, created_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		payment.SalesID,
+		payment.Method,
+		payment.Amount,
+		payment.Tendered,
+		payment.Change,
+		payment.Reference,
+		payment.Points,
+		payment.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ID = int(lastID)
+	return payment, nil
+}
+
+// GetSalesPaymentsBySales retrieves the tenders of a sale in the order they were given
+func (r *SalesPaymentRepository) GetSalesPaymentsBySales(salesID int) ([]model.SalesPayment, error) {
+	return salesPayments(database.DB, salesID)
+}
+
+// GetSalesPaymentsBySalesTx retrieves the tenders of a sale in the order they were given, as part of a transaction
+func (r *SalesPaymentRepository) GetSalesPaymentsBySalesTx(tx *sql.Tx, salesID int) ([]model.SalesPayment, error) {
+	return salesPayments(tx, salesID)
+}
+
+// salesPayments retrieves the tenders of a sale from either the database or a transaction
+func salesPayments(q queryer, salesID int) ([]model.SalesPayment, error) {
+	var payments []model.SalesPayment
+
+	query := `SELECT id_payment, id_sales, payment_method, amount, tendered, change_due, reference, points, created_at 
+	          FROM sales_payment 
+	          WHERE id_sales = ? 
+	          ORDER BY id_payment`
+
+	rows, err := q.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment model.SalesPayment
+		err := rows.Scan(
+			&payment.ID,
+			&payment.SalesID,
+			&payment.Method,
+			&payment.Amount,
+			&payment.Tendered,
+			&payment.Change,
+			&payment.Reference,
+			&payment.Points,
+			&payment.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// GetPointsPaidBySalesTx sums the member points spent on a sale and the part of its total they
+// paid, as part of a transaction
+func (r *SalesPaymentRepository) GetPointsPaidBySalesTx(tx *sql.Tx, salesID int) (int, int, error) {
+	query := `SELECT COALESCE(SUM(points), 0), COALESCE(SUM(amount), 0)
+	          FROM sales_payment WHERE id_sales = ? AND payment_method = ?`
+
+	var points, amount int
+	err := tx.QueryRow(query, salesID, model.PaymentMethodPoints).Scan(&points, &amount)
+	return points, amount, err
+}
+
+// CreateSalesVoidPaymentTx records money a void handed back with one payment method as part of a transaction
+func (r *SalesPaymentRepository) CreateSalesVoidPaymentTx(tx *sql.Tx, payment *model.SalesVoidPayment) (*model.SalesVoidPayment, error) {
+	query := `INSERT INTO sales_void_payment (id_sales, id_shift, payment_method, amount) VALUES (?, ?, ?, ?)`
+
+	result, err := tx.Exec(query, payment.SalesID, nullableID(payment.ShiftID), payment.Method, payment.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ID = int(lastID)
+	return payment, nil
+}
@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+)
+
+// SalesReturnRepository handles database operations for sales returns
+type SalesReturnRepository struct{}
+
+// NewSalesReturnRepository creates a new SalesReturnRepository
+func NewSalesReturnRepository() *SalesReturnRepository {
+	return &SalesReturnRepository{}
+}
+
+// CreateSalesReturnTx inserts a new sales return as part of a transaction
+func (r *SalesReturnRepository) CreateSalesReturnTx(tx *sql.Tx, salesReturn *model.SalesReturn) (*model.SalesReturn, error) {
+	query := `INSERT INTO sales_return (id_sales, id_user, id_shift, return_date, reason, payment_method, total_refund, points_reversed, points_restored, points_refund) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		salesReturn.SalesID,
+		salesReturn.UserID,
+		nullableID(salesReturn.ShiftID),
+		salesReturn.ReturnDate,
+		salesReturn.Reason,
+		salesReturn.PaymentMethod,
+		salesReturn.TotalRefund,
+		salesReturn.PointsReversed,
+		salesReturn.PointsRestored,
+		salesReturn.PointsRefund)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	salesReturn.ID = int(lastID)
+	return salesReturn, nil
+}
+
+// CreateSalesReturnItemTx inserts a returned line as part of a transaction
+func (r *SalesReturnRepository) CreateSalesReturnItemTx(tx *sql.Tx, item *model.SalesReturnItem) (*model.SalesReturnItem, error) {
+	query := `INSERT INTO sales_return_item (id_return, id_sales_item, id_item, qty, unit_price, total_refund, tax_refund) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		item.ReturnID,
+		item.SalesItemID,
+		item.ItemID,
+		item.Qty,
+		item.UnitPrice,
+		item.TotalRefund,
+		item.TaxRefund)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	item.ID = int(lastID)
+	return item, nil
+}
+
+// CreateSalesReturnItemBatchTx records the quantity a returned line put back into a batch as part of a transaction
+func (r *SalesReturnRepository) CreateSalesReturnItemBatchTx(tx *sql.Tx, batch *model.SalesReturnItemBatch) error {
+	query := `INSERT INTO sales_return_item_batch (id_return_item, id_batch, qty) VALUES (?, ?, ?)`
+
+	_, err := tx.Exec(query, batch.ReturnItemID, batch.BatchID, batch.Qty)
+	return err
+}
+
+// CreateSalesReturnPaymentTx records the part of a refund given back with one payment method as part of a transaction
+func (r *SalesReturnRepository) CreateSalesReturnPaymentTx(tx *sql.Tx, payment *model.SalesReturnPayment) (*model.SalesReturnPayment, error) {
+	query := `INSERT INTO sales_return_payment (id_return, payment_method, amount) VALUES (?, ?, ?)`
+
+	result, err := tx.Exec(query, payment.ReturnID, payment.Method, payment.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ID = int(lastID)
+	return payment, nil
+}
+
+// GetRefundedByMethodBySalesTx sums the money earlier returns of a sale gave back, keyed by payment method
+func (r *SalesReturnRepository) GetRefundedByMethodBySalesTx(tx *sql.Tx, salesID int) (map[model.PaymentMethod]int, error) {
+	query := `SELECT rp.payment_method, SUM(rp.amount)
+	          FROM sales_return_payment rp
+	          JOIN sales_return sr ON sr.id_return = rp.id_return
+	          WHERE sr.id_sales = ?
+	          GROUP BY rp.payment_method`
+
+	rows, err := tx.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunded := make(map[model.PaymentMethod]int)
+	for rows.Next() {
+		var method model.PaymentMethod
+		var amount int
+		if err := rows.Scan(&method, &amount); err != nil {
+			return nil, err
+		}
+		refunded[method] = amount
+	}
+
+	return refunded, rows.Err()
+}
+
+// GetReturnedQtyBySalesTx sums the quantity already returned per sales line of a sale, keyed by sales item ID
+func (r *SalesReturnRepository) GetReturnedQtyBySalesTx(tx *sql.Tx, salesID int) (map[int]int, error) {
+	query := `SELECT ri.id_sales_item, SUM(ri.qty)
+	          FROM sales_return_item ri
+	          JOIN sales_return sr ON sr.id_return = ri.id_return
+	          WHERE sr.id_sales = ?
+	          GROUP BY ri.id_sales_item`
+
+	rows, err := tx.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returned := make(map[int]int)
+	for rows.Next() {
+		var salesItemID, qty int
+		if err := rows.Scan(&salesItemID, &qty); err != nil {
+			return nil, err
+		}
+		returned[salesItemID] = qty
+	}
+
+	return returned, rows.Err()
+}
+
+// GetRestockedBySalesTx sums the quantity earlier returns put back into each batch per sales line of a sale,
+// keyed by sales item ID and then batch ID
+func (r *SalesReturnRepository) GetRestockedBySalesTx(tx *sql.Tx, salesID int) (map[int]map[int]int, error) {
+	query := `SELECT ri.id_sales_item, rb.id_batch, SUM(rb.qty)
+	          FROM sales_return_item_batch rb
+	          JOIN sales_return_item ri ON ri.id_return_item = rb.id_return_item
+	          JOIN sales_return sr ON sr.id_return = ri.id_return
+	          WHERE sr.id_sales = ?
+	          GROUP BY ri.id_sales_item, rb.id_batch`
+
+	rows, err := tx.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	restocked := make(map[int]map[int]int)
+	for rows.Next() {
+		var salesItemID, batchID, qty int
+		if err := rows.Scan(&salesItemID, &batchID, &qty); err != nil {
+			return nil, err
+		}
+		if restocked[salesItemID] == nil {
+			restocked[salesItemID] = make(map[int]int)
+		}
+		restocked[salesItemID][batchID] = qty
+	}
+
+	return restocked, rows.Err()
+}
+
+// GetRefundTotalsBySalesTx sums the amount refunded and points reversed by earlier returns of a sale
+func (r *SalesReturnRepository) GetRefundTotalsBySalesTx(tx *sql.Tx, salesID int) (int, int, error) {
+	query := `SELECT COALESCE(SUM(total_refund), 0), COALESCE(SUM(points_reversed), 0)
+	          FROM sales_return WHERE id_sales = ?`
+
+	var refunded, reversed int
+	err := tx.QueryRow(query, salesID).Scan(&refunded, &reversed)
+	return refunded, reversed, err
+}
+
+// GetRestoredBySalesTx sums the spent points earlier returns of a sale gave back and the part of
+// their refunds those points made up
+func (r *SalesReturnRepository) GetRestoredBySalesTx(tx *sql.Tx, salesID int) (int, int, error) {
+	query := `SELECT COALESCE(SUM(points_restored), 0), COALESCE(SUM(points_refund), 0)
+	          FROM sales_return WHERE id_sales = ?`
+
+	var restored, value int
+	err := tx.QueryRow(query, salesID).Scan(&restored, &value)
+	return restored, value, err
+}
+
+// GetSalesReturn retrieves a sales return by ID with its lines, restocked batches and refund split
+func (r *SalesReturnRepository) GetSalesReturn(id int) (*model.SalesReturn, error) {
+	salesReturn := &model.SalesReturn{}
+
+	query := `SELECT id_return, id_sales, id_user, COALESCE(id_shift, 0), return_date, reason, payment_method, total_refund, points_reversed, points_restored, points_refund 
+	          FROM sales_return WHERE id_return = ?`
+
+	err := database.DB.QueryRow(query, id).Scan(
+		&salesReturn.ID,
+		&salesReturn.SalesID,
+		&salesReturn.UserID,
+		&salesReturn.ShiftID,
+		&salesReturn.ReturnDate,
+		&salesReturn.Reason,
+		&salesReturn.PaymentMethod,
+		&salesReturn.TotalRefund,
+		&salesReturn.PointsReversed,
+		&salesReturn.PointsRestored,
+		&salesReturn.PointsRefund,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sales return with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	salesReturn.Items, err = r.getSalesReturnItems(id)
+	if err != nil {
+		return nil, err
+	}
+
+	salesReturn.Payments, err = r.getSalesReturnPayments(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return salesReturn, nil
+}
+
+// GetSalesReturnsBySales retrieves all returns of a sale, oldest first, without their lines
+func (r *SalesReturnRepository) GetSalesReturnsBySales(salesID int) ([]model.SalesReturn, error) {
+	var salesReturns []model.SalesReturn
+
+	query := `SELECT id_return, id_sales, id_user, COALESCE(id_shift, 0), return_date, reason, payment_method, total_refund, points_reversed, points_restored, points_refund 
+	          FROM sales_return WHERE id_sales = ? ORDER BY id_return`
+
+	rows, err := database.DB.Query(query, salesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var salesReturn model.SalesReturn
+		err := rows.Scan(
+			&salesReturn.ID,
+			&salesReturn.SalesID,
+			&salesReturn.UserID,
+			&salesReturn.ShiftID,
+			&salesReturn.ReturnDate,
+			&salesReturn.Reason,
+			&salesReturn.PaymentMethod,
+			&salesReturn.TotalRefund,
+			&salesReturn.PointsReversed,
+			&salesReturn.PointsRestored,
+			&salesReturn.PointsRefund,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		salesReturns = append(salesReturns, salesReturn)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return salesReturns, nil
+}
+
+// SalesHasReturns reports whether any return was taken against a sale
+func (r *SalesReturnRepository) SalesHasReturns(salesID int) (bool, error) {
+	query := `SELECT COUNT(*) FROM sales_return WHERE id_sales = ?`
+
+	var count int
+	if err := database.DB.QueryRow(query, salesID).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// getSalesReturnPayments loads how the money of a return was given back, per payment method
+func (r *SalesReturnRepository) getSalesReturnPayments(returnID int) ([]model.SalesReturnPayment, error) {
+	var payments []model.SalesReturnPayment
+
+	query := `SELECT id_return_payment, id_return, payment_method, amount 
+	          FROM sales_return_payment WHERE id_return = ? ORDER BY id_return_payment`
+
+	rows, err := database.DB.Query(query, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment model.SalesReturnPayment
+		if err := rows.Scan(&payment.ID, &payment.ReturnID, &payment.Method, &payment.Amount); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// getSalesReturnItems loads the lines of a return together with the batches each restocked
+func (r *SalesReturnRepository) getSalesReturnItems(returnID int) ([]model.SalesReturnItem, error) {
+	var items []model.SalesReturnItem
+
+	query := `SELECT id_return_item, id_return, id_sales_item, id_item, qty, unit_price, total_refund, tax_refund 
+	          FROM sales_return_item WHERE id_return = ? ORDER BY id_return_item`
+
+	rows, err := database.DB.Query(query, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.SalesReturnItem
+		err := rows.Scan(
+			&item.ID,
+			&item.ReturnID,
+			&item.SalesItemID,
+			&item.ItemID,
+			&item.Qty,
+			&item.UnitPrice,
+			&item.TotalRefund,
+			&item.TaxRefund,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	batchQuery := `SELECT rb.id_return_item, rb.id_batch, rb.qty
+	               FROM sales_return_item_batch rb
+	               JOIN sales_return_item ri ON ri.id_return_item = rb.id_return_item
+	               WHERE ri.id_return = ?
+	               ORDER BY rb.id_return_item, rb.id_batch`
+
+	batchRows, err := database.DB.Query(batchQuery, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer batchRows.Close()
+
+	for batchRows.Next() {
+		var batch model.SalesReturnItemBatch
+		if err := batchRows.Scan(&batch.ReturnItemID, &batch.BatchID, &batch.Qty); err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			if items[i].ID == batch.ReturnItemID {
+				items[i].Batches = append(items[i].Batches, batch)
+			}
+		}
+	}
+
+	return items, batchRows.Err()
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"strings"
+)
+
+// ShiftRepository handles database operations for cashier shifts
+type ShiftRepository struct{}
+
+// NewShiftRepository creates a new ShiftRepository
+func NewShiftRepository() *ShiftRepository {
+	return &ShiftRepository{}
+}
+
+// shiftColumns lists the cash_shift columns in the order scanShift reads them
+const shiftColumns = `id_shift, id_user, register, status, opening_float, opened_at, 
+	          expected_cash, counted_cash, COALESCE(closed_by, 0), closed_at, close_note`
+
+// queryer is what the report queries need from either the database or a transaction
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// CreateShiftTx inserts a new shift as part of a transaction
+func (r *ShiftRepository) CreateShiftTx(tx *sql.Tx, shift *model.Shift) (*model.Shift, error) {
+	query := `INSERT INTO cash_shift (id_user, register, status, opening_float, opened_at) 
+	          VALUES (?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query,
+		shift.UserID,
+		shift.Register,
+		shift.Status,
+		shift.OpeningFloat,
+		shift.OpenedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	shift.ID = int(lastID)
+	return shift, nil
+}
+
+// GetShift retrieves a shift by ID
+func (r *ShiftRepository) GetShift(id int) (*model.Shift, error) {
+	shift := &model.Shift{}
+
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift WHERE id_shift = ?`
+
+	err := scanShift(database.DB.QueryRow(query, id), shift)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("shift with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// GetShiftForUpdateTx retrieves a shift and locks it until the transaction ends
+func (r *ShiftRepository) GetShiftForUpdateTx(tx *sql.Tx, id int) (*model.Shift, error) {
+	shift := &model.Shift{}
+
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift WHERE id_shift = ? FOR UPDATE`
+
+	err := scanShift(tx.QueryRow(query, id), shift)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("shift with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// GetOpenShiftByUser retrieves the shift a user has open, or nil when they have none
+func (r *ShiftRepository) GetOpenShiftByUser(userID int) (*model.Shift, error) {
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift WHERE id_user = ? AND status = ?`
+
+	return r.openShift(database.DB.QueryRow(query, userID, model.ShiftStatusOpen))
+}
+
+// GetOpenShiftByUserForUpdateTx retrieves the shift a user has open, or nil when they have
+// none, and locks the user's open shifts until the transaction ends so no second one is opened
+func (r *ShiftRepository) GetOpenShiftByUserForUpdateTx(tx *sql.Tx, userID int) (*model.Shift, error) {
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift WHERE id_user = ? AND status = ? FOR UPDATE`
+
+	return r.openShift(tx.QueryRow(query, userID, model.ShiftStatusOpen))
+}
+
+// GetOpenShiftByUserForShareTx retrieves the shift a user has open, or nil when they have none,
+// and keeps it from being closed until the transaction ends
+func (r *ShiftRepository) GetOpenShiftByUserForShareTx(tx *sql.Tx, userID int) (*model.Shift, error) {
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift WHERE id_user = ? AND status = ? LOCK IN SHARE MODE`
+
+	return r.openShift(tx.QueryRow(query, userID, model.ShiftStatusOpen))
+}
+
+// GetShifts retrieves the shifts matching the filter, newest first
+func (r *ShiftRepository) GetShifts(filter model.ShiftFilter) ([]model.Shift, error) {
+	var shifts []model.Shift
+
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserID > 0 {
+		conditions = append(conditions, "id_user = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := `SELECT ` + shiftColumns + ` FROM cash_shift`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY opened_at DESC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shift model.Shift
+		if err := scanShift(rows, &shift); err != nil {
+			return nil, err
+		}
+
+		shifts = append(shifts, shift)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+// CloseShiftTx records the close of a shift as part of a transaction
+func (r *ShiftRepository) CloseShiftTx(tx *sql.Tx, shift *model.Shift) error {
+	query := `UPDATE cash_shift SET 
+	          status = ?, 
+	          expected_cash = ?, 
+	          counted_cash = ?, 
+	          closed_by = ?, 
+	          closed_at = ?, 
+	          close_note = ? 
+	          WHERE id_shift = ?`
+
+	_, err := tx.Exec(query,
+		shift.Status,
+		shift.ExpectedCash,
+		shift.CountedCash,
+		nullableID(shift.ClosedBy),
+		nullableTime(shift.ClosedAt),
+		shift.CloseNote,
+		shift.ID)
+	return err
+}
+
+// GetShiftTakings sums the tenders of the sales paid on a shift per payment method
+func (r *ShiftRepository) GetShiftTakings(shiftID int) ([]model.PaymentTotal, error) {
+	return shiftTakings(database.DB, shiftID)
+}
+
+// GetShiftTakingsTx sums the tenders of the sales paid on a shift per payment method as part of a transaction
+func (r *ShiftRepository) GetShiftTakingsTx(tx *sql.Tx, shiftID int) ([]model.PaymentTotal, error) {
+	return shiftTakings(tx, shiftID)
+}
+
+// GetShiftRefunds sums the money returns and voids on a shift gave back per payment method
+func (r *ShiftRepository) GetShiftRefunds(shiftID int) ([]model.PaymentTotal, error) {
+	return shiftRefunds(database.DB, shiftID)
+}
+
+// GetShiftRefundsTx sums the money returns and voids on a shift gave back per payment method as part of a transaction
+func (r *ShiftRepository) GetShiftRefundsTx(tx *sql.Tx, shiftID int) ([]model.PaymentTotal, error) {
+	return shiftRefunds(tx, shiftID)
+}
+
+// CountShiftSales counts the sales paid on a shift and those voided
+func (r *ShiftRepository) CountShiftSales(shiftID int) (int, int, error) {
+	return countShiftSales(database.DB, shiftID)
+}
+
+// CountShiftSalesTx counts the sales paid on a shift and those voided as part of a transaction
+func (r *ShiftRepository) CountShiftSalesTx(tx *sql.Tx, shiftID int) (int, int, error) {
+	return countShiftSales(tx, shiftID)
+}
+
+// openShift scans the open shift selected by row, mapping no row to a nil shift
+func (r *ShiftRepository) openShift(row *sql.Row) (*model.Shift, error) {
+	shift := &model.Shift{}
+	if err := scanShift(row, shift); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return shift, nil
+}
+
+// shiftTakings sums the tenders of the sales paid on a shift. Sales voided since stay in: what
+// a void handed back counts with the refunds of the voiding cashier's shift.
+func shiftTakings(q queryer, shiftID int) ([]model.PaymentTotal, error) {
+	query := `SELECT p.payment_method, COUNT(*), COALESCE(SUM(p.amount), 0)
+	          FROM sales_payment p
+	          JOIN sales_basket s ON s.id_sales = p.id_sales
+	          WHERE s.id_shift = ? AND s.status IN (?, ?, ?)
+	          GROUP BY p.payment_method
+	          ORDER BY p.payment_method`
+
+	return queryPaymentTotals(q, query, shiftID, model.SalesStatusCompleted, model.SalesStatusRefunded, model.SalesStatusVoided)
+}
+
+// shiftRefunds sums the money returns and voids on a shift gave back per method, split the way
+// each sale was paid; the part refunded as points is left out
+func shiftRefunds(q queryer, shiftID int) ([]model.PaymentTotal, error) {
+	query := `SELECT r.payment_method, COUNT(*), COALESCE(SUM(r.amount), 0)
+	          FROM (
+	              SELECT rp.payment_method, rp.amount
+	              FROM sales_return_payment rp
+	              JOIN sales_return sr ON sr.id_return = rp.id_return
+	              WHERE sr.id_shift = ?
+	              UNION ALL
+	              SELECT payment_method, amount FROM sales_void_payment WHERE id_shift = ?
+	          ) r
+	          GROUP BY r.payment_method
+	          ORDER BY r.payment_method`
+
+	return queryPaymentTotals(q, query, shiftID, shiftID)
+}
+
+// countShiftSales counts the sales paid on a shift and those voided
+func countShiftSales(q queryer, shiftID int) (int, int, error) {
+	query := `SELECT COALESCE(SUM(status IN (?, ?)), 0), COALESCE(SUM(status = ?), 0)
+	          FROM sales_basket WHERE id_shift = ?`
+
+	var sales, voided int
+	err := q.QueryRow(query, model.SalesStatusCompleted, model.SalesStatusRefunded, model.SalesStatusVoided, shiftID).Scan(&sales, &voided)
+	return sales, voided, err
+}
+
+// queryPaymentTotals runs a query selecting a method, count and amount and scans every row
+func queryPaymentTotals(q queryer, query string, args ...interface{}) ([]model.PaymentTotal, error) {
+	var totals []model.PaymentTotal
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total model.PaymentTotal
+		if err := rows.Scan(&total.Method, &total.Count, &total.Amount); err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, total)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// scanShift scans a row selected with shiftColumns
+func scanShift(row interface{ Scan(...interface{}) error }, shift *model.Shift) error {
+	var closedAt sql.NullTime
+	err := row.Scan(
+		&shift.ID,
+		&shift.UserID,
+		&shift.Register,
+		&shift.Status,
+		&shift.OpeningFloat,
+		&shift.OpenedAt,
+		&shift.ExpectedCash,
+		&shift.CountedCash,
+		&shift.ClosedBy,
+		&closedAt,
+		&shift.CloseNote,
+	)
+	if err != nil {
+		return err
+	}
+
+	shift.ClosedAt = closedAt.Time
+	return nil
+}
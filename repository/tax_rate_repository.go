@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"strings"
+)
+
+// TaxRateRepository handles database operations for tax rates
+type TaxRateRepository struct{}
+
+// NewTaxRateRepository creates a new TaxRateRepository
+func NewTaxRateRepository() *TaxRateRepository {
+	return &TaxRateRepository{}
+}
+
+// CreateTaxRate inserts a new tax rate into the database
+func (r *TaxRateRepository) CreateTaxRate(taxRate *model.TaxRate) (*model.TaxRate, error) {
+	query := `INSERT INTO tax_rate (tax_name, rate) VALUES (?, ?)`
+
+	result, err := database.DB.Exec(query, taxRate.Name, taxRate.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	taxRate.ID = int(lastID)
+	return taxRate, nil
+}
+
+// GetTaxRate retrieves a tax rate by ID from the database
+func (r *TaxRateRepository) GetTaxRate(id int) (*model.TaxRate, error) {
+	taxRate := &model.TaxRate{}
+
+	query := `SELECT id_tax_rate, tax_name, rate FROM tax_rate WHERE id_tax_rate = ?`
+
+	err := database.DB.QueryRow(query, id).Scan(&taxRate.ID, &taxRate.Name, &taxRate.Rate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tax rate with ID %d not found", id)
+		}
+		return nil, err
+	}
+
+	return taxRate, nil
+}
+
+// GetAllTaxRates retrieves all tax rates from the database
+func (r *TaxRateRepository) GetAllTaxRates() ([]model.TaxRate, error) {
+	var taxRates []model.TaxRate
+
+	query := `SELECT id_tax_rate, tax_name, rate FROM tax_rate ORDER BY tax_name`
+
+	rows, err := database.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taxRate model.TaxRate
+		if err := rows.Scan(&taxRate.ID, &taxRate.Name, &taxRate.Rate); err != nil {
+			return nil, err
+		}
+
+		taxRates = append(taxRates, taxRate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return taxRates, nil
+}
+
+// UpdateTaxRate updates an existing tax rate in the database
+func (r *TaxRateRepository) UpdateTaxRate(taxRate *model.TaxRate) (*model.TaxRate, error) {
+	query := `UPDATE tax_rate SET tax_name = ?, rate = ? WHERE id_tax_rate = ?`
+
+	_, err := database.DB.Exec(query, taxRate.Name, taxRate.Rate, taxRate.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return taxRate, nil
+}
+
+// IsTaxRateInUse checks if a tax rate is assigned to any category or item
+func (r *TaxRateRepository) IsTaxRateInUse(id int) (bool, error) {
+	var inUse bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM category WHERE id_tax_rate = ?) 
+	          OR EXISTS(SELECT 1 FROM item WHERE id_tax_rate = ?)`
+
+	err := database.DB.QueryRow(query, id, id).Scan(&inUse)
+	return inUse, err
+}
+
+// DeleteTaxRate deletes a tax rate from the database
+func (r *TaxRateRepository) DeleteTaxRate(id int) error {
+	query := `DELETE FROM tax_rate WHERE id_tax_rate = ?`
+
+	_, err := database.DB.Exec(query, id)
+	return err
+}
+
+// GetTaxRatesByItems looks up the tax rate of every given item, keyed by item ID: the item's
+// own rate, or else its category's. Untaxed items are left out.
+func (r *TaxRateRepository) GetTaxRatesByItems(itemIDs []int) (map[int]model.TaxRate, error) {
+	taxRates := make(map[int]model.TaxRate)
+	if len(itemIDs) == 0 {
+		return taxRates, nil
+	}
+
+	placeholders := make([]string, len(itemIDs))
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `SELECT i.id_item, t.id_tax_rate, t.tax_name, t.rate
+	          FROM item i
+	          LEFT JOIN category c ON c.id_category = i.item_category
+	          JOIN tax_rate t ON t.id_tax_rate = COALESCE(i.id_tax_rate, c.id_tax_rate)
+	          WHERE i.id_item IN (` + strings.Join(placeholders, ", ") + `)`
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int
+		var taxRate model.TaxRate
+		if err := rows.Scan(&itemID, &taxRate.ID, &taxRate.Name, &taxRate.Rate); err != nil {
+			return nil, err
+		}
+		taxRates[itemID] = taxRate
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return taxRates, nil
+}
+
+// GetTaxSummary sums up, per tax rate, the tax charged on completed and refunded sales dated
+// from up to but excluding to, and the tax refunded by returns made in the same period.
+// Bounds are Unix timestamps; a zero bound is left open.
+func (r *TaxRateRepository) GetTaxSummary(from, to int) ([]model.TaxSummary, error) {
+	var summary []model.TaxSummary
+
+	// Lines are grouped by the rate they were taxed at, so a rate changed mid-period shows twice
+	query := `SELECT taxed.id_tax_rate, COALESCE(t.tax_name, ''), taxed.rate,
+	                 SUM(taxed.taxable), SUM(taxed.tax), SUM(taxed.refunded_taxable), SUM(taxed.refunded_tax)
+	          FROM (
+	              SELECT COALESCE(si.id_tax_rate, 0) AS id_tax_rate, si.tax_rate AS rate,
+	                     si.total_item_sales - si.tax_amount AS taxable, si.tax_amount AS tax,
+	                     0 AS refunded_taxable, 0 AS refunded_tax
+	              FROM sales_item si
+	              JOIN sales_basket sb ON sb.id_sales = si.id_sales
+	              WHERE sb.status IN (?, ?)
+	                AND (? = 0 OR sb.sales_date >= ?)
+	                AND (? = 0 OR sb.sales_date < ?)
+	              UNION ALL
+	              SELECT COALESCE(si.id_tax_rate, 0), si.tax_rate,
+	                     0, 0,
+	                     ri.total_refund - ri.tax_refund, ri.tax_refund
+	              FROM sales_return_item ri
+	              JOIN sales_return sr ON sr.id_return = ri.id_return
+	              JOIN sales_item si ON si.id_sales_item = ri.id_sales_item
+	              WHERE (? = 0 OR sr.return_date >= FROM_UNIXTIME(?))
+	                AND (? = 0 OR sr.return_date < FROM_UNIXTIME(?))
+	          ) taxed
+	          LEFT JOIN tax_rate t ON t.id_tax_rate = taxed.id_tax_rate
+	          GROUP BY taxed.id_tax_rate, t.tax_name, taxed.rate
+	          ORDER BY taxed.rate DESC, taxed.id_tax_rate`
+
+	rows, err := database.DB.Query(query,
+		model.SalesStatusCompleted, model.SalesStatusRefunded,
+		from, from, to, to,
+		from, from, to, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.TaxSummary
+		err := rows.Scan(
+			&row.TaxRateID,
+			&row.Name,
+			&row.Rate,
+			&row.Taxable,
+			&row.Tax,
+			&row.RefundedTaxable,
+			&row.RefundedTax,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		summary = append(summary, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
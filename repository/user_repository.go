@@ -17,8 +17,8 @@ func NewUserRepository() *UserRepository {
 
 // CreateUser inserts a new user into the database
 func (r *UserRepository) CreateUser(user *model.User) (*model.User, error) {
-	query := `INSERT INTO user (nik, name, address, phone, gender, password_hash, is_admin, token) 
-		      VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO user (nik, name, address, phone, gender, password_hash, is_admin) 
+		      VALUES (?, ?, ?, ?, ?, ?, ?)`
 		      
 	result, err := database.DB.Exec(query, 
 		user.NIK, 
@@ -27,8 +27,7 @@ func (r *UserRepository) CreateUser(user *model.User) (*model.User, error) {
 		user.Phone, 
 		user.Gender, 
 		user.PasswordHash,
-		user.IsAdmin,
-		user.Token)
+		user.IsAdmin)
 		
 	if err != nil {
 		return nil, err
@@ -48,7 +47,7 @@ func (r *UserRepository) CreateUser(user *model.User) (*model.User, error) {
 func (r *UserRepository) GetUser(id int) (*model.User, error) {
 	user := &model.User{}
 	
-	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin, token 
+	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin 
 	          FROM user WHERE id_user = ?`
 	          
 	err := database.DB.QueryRow(query, id).Scan(
@@ -60,7 +59,6 @@ func (r *UserRepository) GetUser(id int) (*model.User, error) {
 		&user.Gender,
 		&user.PasswordHash,
 		&user.IsAdmin,
-		&user.Token,
 	)
 	
 	if err != nil {
@@ -77,7 +75,7 @@ func (r *UserRepository) GetUser(id int) (*model.User, error) {
 func (r *UserRepository) GetAllUsers() ([]model.User, error) {
 	var users []model.User
 	
-	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin, token 
+	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin 
 	          FROM user`
 	          
 	rows, err := database.DB.Query(query)
@@ -97,7 +95,6 @@ func (r *UserRepository) GetAllUsers() ([]model.User, error) {
 			&user.Gender,
 			&user.PasswordHash,
 			&user.IsAdmin,
-			&user.Token,
 		)
 		
 		if err != nil {
@@ -123,8 +120,7 @@ func (r *UserRepository) UpdateUser(user *model.User) (*model.User, error) {
 	          phone = ?, 
 	          gender = ?, 
 	          password_hash = ?, 
-	          is_admin = ?, 
-	          token = ? 
+	          is_admin = ? 
 	          WHERE id_user = ?`
 	          
 	_, err := database.DB.Exec(query,
@@ -135,7 +131,6 @@ func (r *UserRepository) UpdateUser(user *model.User) (*model.User, error) {
 		user.Gender,
 		user.PasswordHash,
 		user.IsAdmin,
-		user.Token,
 		user.ID)
 		
 	if err != nil {
@@ -162,7 +157,7 @@ func (r *UserRepository) DeleteUser(id int) error {
 func (r *UserRepository) GetUserByNIK(nik int) (*model.User, error) {
 	user := &model.User{}
 	
-	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin, token 
+	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin 
 	          FROM user WHERE nik = ?`
 	          
 	err := database.DB.QueryRow(query, nik).Scan(
@@ -174,7 +169,6 @@ func (r *UserRepository) GetUserByNIK(nik int) (*model.User, error) {
 		&user.Gender,
 		&user.PasswordHash,
 		&user.IsAdmin,
-		&user.Token,
 	)
 	
 	if err != nil {
@@ -187,31 +181,10 @@ func (r *UserRepository) GetUserByNIK(nik int) (*model.User, error) {
 	return user, nil
 }
 
-// GetUserByToken finds a user by token
-func (r *UserRepository) GetUserByToken(token string) (*model.User, error) {
-	user := &model.User{}
-	
-	query := `SELECT id_user, nik, name, address, phone, gender, password_hash, is_admin, token 
-	          FROM user WHERE token = ?`
-	          
-	err := database.DB.QueryRow(query, token).Scan(
-		&user.ID,
-		&user.NIK,
-		&user.Name,
-		&user.Address,
-		&user.Phone,
-		&user.Gender,
-		&user.PasswordHash,
-		&user.IsAdmin,
-		&user.Token,
-	)
-	
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with token not found")
-		}
-		return nil, err
-	}
+// UpdatePassword stores a new password hash for a user
+func (r *UserRepository) UpdatePassword(id int, passwordHash string) error {
+	query := `UPDATE user SET password_hash = ? WHERE id_user = ?`
 	
-	return user, nil
+	_, err := database.DB.Exec(query, passwordHash, id)
+	return err
 }
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// UserSessionRepository handles database operations for user sessions
+type UserSessionRepository struct{}
+
+// NewUserSessionRepository creates a new UserSessionRepository
+func NewUserSessionRepository() *UserSessionRepository {
+	return &UserSessionRepository{}
+}
+
+const userSessionColumns = `id_session, id_user, device, token_hash, refresh_token_hash,
+	          created_at, last_seen_at, expires_at, refresh_expires_at`
+
+// CreateSession inserts a new session into the database
+func (r *UserSessionRepository) CreateSession(session *model.UserSession) (*model.UserSession, error) {
+	query := `INSERT INTO user_session (` + userSessionColumns + `)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := database.DB.Exec(query,
+		session.ID,
+		session.UserID,
+		session.Device,
+		session.TokenHash,
+		session.RefreshTokenHash,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.RefreshExpiresAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetSession retrieves a session by ID
+func (r *UserSessionRepository) GetSession(id string) (*model.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE id_session = ?`
+
+	session, err := r.scanSession(database.DB.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session %s not found", id)
+	}
+	return session, err
+}
+
+// GetSessionByTokenHash retrieves a session by the digest of its access token
+func (r *UserSessionRepository) GetSessionByTokenHash(tokenHash string) (*model.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE token_hash = ?`
+
+	session, err := r.scanSession(database.DB.QueryRow(query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session with token not found")
+	}
+	return session, err
+}
+
+// GetSessionByRefreshTokenHash retrieves a session by the digest of its refresh token
+func (r *UserSessionRepository) GetSessionByRefreshTokenHash(refreshTokenHash string) (*model.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE refresh_token_hash = ?`
+
+	session, err := r.scanSession(database.DB.QueryRow(query, refreshTokenHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session with refresh token not found")
+	}
+	return session, err
+}
+
+// GetSessionsByUser retrieves all sessions of a user, most recently used first
+func (r *UserSessionRepository) GetSessionsByUser(userID int) ([]model.UserSession, error) {
+	var sessions []model.UserSession
+
+	query := `SELECT ` + userSessionColumns + `
+	          FROM user_session
+	          WHERE id_user = ?
+	          ORDER BY last_seen_at DESC`
+
+	rows, err := database.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := r.scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// scanSession scans a single session row
+func (r *UserSessionRepository) scanSession(row interface{ Scan(...interface{}) error }) (*model.UserSession, error) {
+	session := &model.UserSession{}
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Device,
+		&session.TokenHash,
+		&session.RefreshTokenHash,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RefreshExpiresAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// TouchSession records activity on a session and slides its expiry forward
+func (r *UserSessionRepository) TouchSession(id string, lastSeen, expiresAt time.Time) error {
+	query := `UPDATE user_session SET last_seen_at = ?, expires_at = ? WHERE id_session = ?`
+
+	_, err := database.DB.Exec(query, lastSeen, expiresAt, id)
+	return err
+}
+
+// RotateTokens replaces both tokens of a session after a refresh
+func (r *UserSessionRepository) RotateTokens(session *model.UserSession) (*model.UserSession, error) {
+	query := `UPDATE user_session SET
+	          token_hash = ?,
+	          refresh_token_hash = ?,
+	          last_seen_at = ?,
+	          expires_at = ?,
+	          refresh_expires_at = ?
+	          WHERE id_session = ?`
+
+	_, err := database.DB.Exec(query,
+		session.TokenHash,
+		session.RefreshTokenHash,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.RefreshExpiresAt,
+		session.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// DeleteSession revokes a single session
+func (r *UserSessionRepository) DeleteSession(id string) error {
+	query := `DELETE FROM user_session WHERE id_session = ?`
+
+	_, err := database.DB.Exec(query, id)
+	return err
+}
+
+// DeleteSessionsByUser revokes every session of a user, optionally keeping one
+func (r *UserSessionRepository) DeleteSessionsByUser(userID int, exceptID string) error {
+	query := `DELETE FROM user_session WHERE id_user = ? AND id_session <> ?`
+
+	_, err := database.DB.Exec(query, userID, exceptID)
+	return err
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go-pos/database"
+	"go-pos/model"
+	"time"
+)
+
+// UserTOTPRepository handles database operations for TOTP enrollments and recovery codes
+type UserTOTPRepository struct{}
+
+// NewUserTOTPRepository creates a new UserTOTPRepository
+func NewUserTOTPRepository() *UserTOTPRepository {
+	return &UserTOTPRepository{}
+}
+
+// GetUserTOTP retrieves the TOTP enrollment of a user
+func (r *UserTOTPRepository) GetUserTOTP(userID int) (*model.UserTOTP, error) {
+	totp := &model.UserTOTP{}
+	var confirmedAt sql.NullTime
+
+	query := `SELECT id_user, secret, enabled, last_used_step, created_at, confirmed_at
+	          FROM user_totp WHERE id_user = ?`
+
+	err := database.DB.QueryRow(query, userID).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.Enabled,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+		&confirmedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %d has no TOTP enrollment", userID)
+		}
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		totp.ConfirmedAt = confirmedAt.Time
+	}
+
+	return totp, nil
+}
+
+// IsTOTPEnabled reports whether a user has a confirmed TOTP enrollment
+func (r *UserTOTPRepository) IsTOTPEnabled(userID int) (bool, error) {
+	var enabled bool
+
+	query := `SELECT enabled FROM user_totp WHERE id_user = ?`
+
+	err := database.DB.QueryRow(query, userID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// SavePendingTOTP stores a new, unconfirmed secret for a user, replacing any earlier pending one
+func (r *UserTOTPRepository) SavePendingTOTP(totp *model.UserTOTP) error {
+	query := `INSERT INTO user_totp (id_user, secret, enabled, last_used_step, created_at)
+	          VALUES (?, ?, FALSE, 0, ?)
+	          ON DUPLICATE KEY UPDATE
+	          secret = VALUES(secret),
+	          enabled = FALSE,
+	          last_used_step = 0,
+	          created_at = VALUES(created_at),
+	          confirmed_at = NULL`
+
+	_, err := database.DB.Exec(query, totp.UserID, totp.Secret, totp.CreatedAt)
+	return err
+}
+
+// EnableTOTP confirms the enrollment of a user and replaces their recovery codes
+func (r *UserTOTPRepository) EnableTOTP(userID int, step int64, confirmedAt time.Time, recoveryCodeHashes []string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE user_totp SET enabled = TRUE, last_used_step = ?, confirmed_at = ? WHERE id_user = ?`
+
+	if _, err := tx.Exec(query, step, confirmedAt, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := r.replaceRecoveryCodesTx(tx, userID, recoveryCodeHashes); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep records the time step of an accepted code. It returns false when the step,
+// or a later one, has already been used, so every code is accepted at most once.
+func (r *UserTOTPRepository) UseStep(userID int, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = ? WHERE id_user = ? AND last_used_step < ?`
+
+	result, err := database.DB.Exec(query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteUserTOTP removes the enrollment and recovery codes of a user
+func (r *UserTOTPRepository) DeleteUserTOTP(userID int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_recovery_code WHERE id_user = ?`, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE id_user = ?`, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes discards every recovery code of a user and stores the given digests
+func (r *UserTOTPRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := r.replaceRecoveryCodesTx(tx, userID, codeHashes); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceRecoveryCodesTx replaces the recovery codes of a user within a transaction
+func (r *UserTOTPRepository) replaceRecoveryCodesTx(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM user_recovery_code WHERE id_user = ?`, userID); err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err := tx.Exec(`INSERT INTO user_recovery_code (id_user, code_hash) VALUES (?, ?)`, userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode consumes an unused recovery code of a user by its digest.
+// It returns false when no such unused code exists.
+func (r *UserTOTPRepository) UseRecoveryCode(userID int, codeHash string, usedAt time.Time) (bool, error) {
+	query := `UPDATE user_recovery_code SET used_at = ?
+	          WHERE id_user = ? AND code_hash = ? AND used_at IS NULL`
+
+	result, err := database.DB.Exec(query, usedAt, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (r *UserTOTPRepository) CountRecoveryCodes(userID int) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM user_recovery_code WHERE id_user = ? AND used_at IS NULL`
+
+	err := database.DB.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
@@ -2,17 +2,28 @@ package routers
 
 import (
 	"go-pos/controllers"
+	"go-pos/filters"
 	
 	beego "github.com/beego/beego/v2/server/web"
 )
 
 func init() {
+	// Every API call must carry a valid token, except the public auth routes
+	beego.InsertFilter("/api/*", beego.BeforeRouter, filters.Auth)
+	
+	// Member self-service routes use member tokens instead of staff sessions
+	beego.InsertFilter("/api/*", beego.BeforeRouter, filters.MemberAuth)
+	
+	// Successful mutating calls are written to the audit trail after the response
+	beego.InsertFilter("/api/*", beego.FinishRouter, filters.Audit, beego.WithReturnOnOutput(false))
+	
 	// Category routes
 	beego.Router("/api/categories", &controllers.CategoryController{}, "get:GetAll;post:Create")
 	beego.Router("/api/categories/:id", &controllers.CategoryController{}, "get:Get;put:Update;delete:Delete")
 	
 	// Item routes
 	beego.Router("/api/items", &controllers.ItemController{}, "get:GetAll;post:Create")
+	beego.Router("/api/items/scan/*", &controllers.ItemController{}, "get:Scan")
 	beego.Router("/api/items/:id", &controllers.ItemController{}, "get:Get;put:Update;delete:Delete")
 	
 	// ItemBatch routes
@@ -31,6 +42,33 @@ func init() {
 	// SalesBasket routes
 	beego.Router("/api/sales", &controllers.SalesBasketController{}, "get:GetAll;post:Create")
 	beego.Router("/api/sales/:id", &controllers.SalesBasketController{}, "get:Get;put:Update;delete:Delete")
+	beego.Router("/api/sales/:id/hold", &controllers.SalesBasketController{}, "post:Hold")
+	beego.Router("/api/sales/:id/resume", &controllers.SalesBasketController{}, "post:Resume")
+	beego.Router("/api/sales/:id/complete", &controllers.SalesBasketController{}, "post:Complete")
+	beego.Router("/api/sales/:id/void", &controllers.SalesBasketController{}, "post:Void")
+	beego.Router("/api/sales/:id/receipt", &controllers.SalesBasketController{}, "get:Receipt")
+	
+	// SalesReturn routes
+	beego.Router("/api/sales/:id/returns", &controllers.SalesReturnController{}, "get:GetAllBySales;post:Create")
+	beego.Router("/api/returns/:id", &controllers.SalesReturnController{}, "get:Get")
+	
+	// Shift routes
+	beego.Router("/api/shifts", &controllers.ShiftController{}, "get:GetAll;post:Open")
+	beego.Router("/api/shifts/current", &controllers.ShiftController{}, "get:GetCurrent")
+	beego.Router("/api/shifts/:id", &controllers.ShiftController{}, "get:Get")
+	beego.Router("/api/shifts/:id/movements", &controllers.ShiftController{}, "get:GetMovements;post:AddMovement")
+	beego.Router("/api/shifts/:id/x-report", &controllers.ShiftController{}, "get:XReport")
+	beego.Router("/api/shifts/:id/z-report", &controllers.ShiftController{}, "post:ZReport")
+	
+	// Promotion routes
+	beego.Router("/api/promotions", &controllers.PromotionController{}, "get:GetAll;post:Create")
+	beego.Router("/api/promotions/usage", &controllers.PromotionController{}, "get:GetUsage")
+	beego.Router("/api/promotions/:id", &controllers.PromotionController{}, "get:Get;put:Update;delete:Delete")
+	
+	// TaxRate routes
+	beego.Router("/api/tax-rates", &controllers.TaxRateController{}, "get:GetAll;post:Create")
+	beego.Router("/api/tax-rates/summary", &controllers.TaxRateController{}, "get:GetSummary")
+	beego.Router("/api/tax-rates/:id", &controllers.TaxRateController{}, "get:Get;put:Update;delete:Delete")
 	
 	// SalesItem routes
 	beego.Router("/api/sales-items", &controllers.SalesItemController{}, "get:GetAll;post:Create")
@@ -39,6 +77,21 @@ func init() {
 	// User routes
 	beego.Router("/api/users", &controllers.UserController{}, "get:GetAll;post:Create")
 	beego.Router("/api/users/:id", &controllers.UserController{}, "get:Get;put:Update;delete:Delete")
+	beego.Router("/api/users/:id/unlock", &controllers.UserController{}, "post:Unlock")
+	beego.Router("/api/users/:id/reset-code", &controllers.UserController{}, "post:IssueResetCode")
+	beego.Router("/api/login-lockouts", &controllers.UserController{}, "get:GetLockouts")
+	beego.Router("/api/login-lockouts/:key", &controllers.UserController{}, "delete:DeleteLockout")
+	
+	// Role routes
+	beego.Router("/api/roles", &controllers.RoleController{}, "get:GetAll;post:Create")
+	beego.Router("/api/roles/:id", &controllers.RoleController{}, "get:Get;put:Update;delete:Delete")
+	beego.Router("/api/roles/:id/permissions", &controllers.RoleController{}, "put:SetPermissions")
+	beego.Router("/api/permissions", &controllers.RoleController{}, "get:GetAllPermissions")
+	beego.Router("/api/users/:id/roles", &controllers.RoleController{}, "get:GetUserRoles;put:SetUserRoles")
+	
+	// APIKey routes
+	beego.Router("/api/api-keys", &controllers.APIKeyController{}, "get:GetAll;post:Create")
+	beego.Router("/api/api-keys/:id", &controllers.APIKeyController{}, "get:Get;delete:Delete")
 	
 	// UserLog routes
 	beego.Router("/api/user-logs", &controllers.UserLogController{}, "get:GetAll;post:Create")
@@ -48,7 +101,29 @@ func init() {
 	beego.Router("/api/user-members", &controllers.UserMemberController{}, "get:GetAll;post:Create")
 	beego.Router("/api/user-members/:id", &controllers.UserMemberController{}, "get:Get;put:Update;delete:Delete")
 	
+	// Audit trail routes
+	beego.Router("/api/audit", &controllers.AuditController{}, "get:GetAll")
+	
 	// Authentication routes
 	beego.Router("/api/auth/login", &controllers.AuthController{}, "post:Login")
+	beego.Router("/api/auth/login/verify", &controllers.AuthController{}, "post:VerifyLogin")
 	beego.Router("/api/auth/logout", &controllers.AuthController{}, "post:Logout")
+	beego.Router("/api/auth/refresh", &controllers.AuthController{}, "post:Refresh")
+	beego.Router("/api/auth/sessions", &controllers.AuthController{}, "get:GetSessions;delete:RevokeAllSessions")
+	beego.Router("/api/auth/sessions/:id", &controllers.AuthController{}, "delete:RevokeSession")
+	beego.Router("/api/auth/change-password", &controllers.AuthController{}, "post:ChangePassword")
+	beego.Router("/api/auth/reset-password", &controllers.AuthController{}, "post:ResetPassword")
+	
+	// Two-factor authentication routes
+	beego.Router("/api/auth/2fa", &controllers.TwoFactorController{}, "get:Get;delete:Delete")
+	beego.Router("/api/auth/2fa/enroll", &controllers.TwoFactorController{}, "post:Enroll")
+	beego.Router("/api/auth/2fa/confirm", &controllers.TwoFactorController{}, "post:Confirm")
+	beego.Router("/api/auth/2fa/recovery-codes", &controllers.TwoFactorController{}, "post:RegenerateRecoveryCodes")
+	
+	// Member self-service routes
+	beego.Router("/api/member-auth/login", &controllers.MemberAuthController{}, "post:Login")
+	beego.Router("/api/member-auth/logout", &controllers.MemberAuthController{}, "post:Logout")
+	beego.Router("/api/me", &controllers.MeController{}, "get:Get")
+	beego.Router("/api/me/points", &controllers.MeController{}, "get:GetPoints")
+	beego.Router("/api/me/sales", &controllers.MeController{}, "get:GetSales")
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// codeAlphabet omits characters that are easily confused when read aloud or typed (0/O, 1/I/L)
+const codeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// NewToken returns a random 256-bit token encoded as hex
+func NewToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// HashToken returns the SHA-256 hex digest of a token.
+// Only digests are stored so a database leak does not expose usable credentials.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewCode returns a random human-typeable code of the given length
+func NewCode(length int) string {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code)
+}
+
+// APIKeyPrefix marks API keys so they can be told apart from session tokens
+const APIKeyPrefix = "gpk_"
+
+// NewAPIKey returns a random API key
+func NewAPIKey() string {
+	return APIKeyPrefix + NewToken()
+}
+
+// IsAPIKey reports whether a credential has the API key format
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
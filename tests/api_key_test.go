@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/model"
+	"go-pos/security"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestAPIKeyScopes checks how API keys are limited to their routes, permissions and lifetime
+func TestAPIKeyScopes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	apiKey := &model.APIKey{
+		Permissions: []model.Permission{{Code: model.PermissionReportsView}},
+		Routes: []model.APIKeyRoute{
+			{Method: "GET", Path: "/api/items/*"},
+			{Method: "*", Path: "/api/sales"},
+		},
+	}
+
+	Convey("Subject: API key scopes\n", t, func() {
+		Convey("Prefix routes cover the base path and everything below it", func() {
+			So(apiKey.AllowsRoute("GET", "/api/items"), ShouldBeTrue)
+			So(apiKey.AllowsRoute("get", "/api/items/7"), ShouldBeTrue)
+			So(apiKey.AllowsRoute("PUT", "/api/items/7"), ShouldBeFalse)
+			So(apiKey.AllowsRoute("GET", "/api/items-export"), ShouldBeFalse)
+		})
+
+		Convey("Exact routes only cover their own path", func() {
+			So(apiKey.AllowsRoute("POST", "/api/sales"), ShouldBeTrue)
+			So(apiKey.AllowsRoute("GET", "/api/sales/3"), ShouldBeFalse)
+		})
+
+		Convey("Only granted permissions are held", func() {
+			So(apiKey.HasPermission(model.PermissionReportsView), ShouldBeTrue)
+			So(apiKey.HasPermission(model.PermissionSalesVoid), ShouldBeFalse)
+		})
+
+		Convey("Revoked and expired keys are inactive", func() {
+			So(apiKey.IsActive(now), ShouldBeTrue)
+			So((&model.APIKey{ExpiresAt: now}).IsActive(now), ShouldBeFalse)
+			So((&model.APIKey{RevokedAt: now.Add(-time.Hour)}).IsActive(now), ShouldBeFalse)
+		})
+
+		Convey("Keys are distinguishable from session tokens", func() {
+			So(security.IsAPIKey(security.NewAPIKey()), ShouldBeTrue)
+			So(security.IsAPIKey(security.NewToken()), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/audit"
+	"go-pos/dto"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestAuditDiff checks that only changed fields are recorded in the audit trail
+func TestAuditDiff(t *testing.T) {
+	before := dto.NewItemResponse(&model.Item{ID: 1, Name: "Tea", Price: 5000})
+	after := dto.NewItemResponse(&model.Item{ID: 1, Name: "Tea", Price: 6000})
+
+	Convey("Subject: Audit diff\n", t, func() {
+		Convey("An update records only the changed fields", func() {
+			changes, err := audit.Diff(before, after)
+			So(err, ShouldBeNil)
+			So(changes, ShouldContainKey, "item_price")
+			So(changes, ShouldNotContainKey, "item_name")
+			So(changes["item_price"].Before, ShouldEqual, float64(5000))
+			So(changes["item_price"].After, ShouldEqual, float64(6000))
+		})
+
+		Convey("A deletion records every field with an empty after value", func() {
+			changes, err := audit.Diff(before, nil)
+			So(err, ShouldBeNil)
+			So(changes, ShouldContainKey, "item_name")
+			So(changes["item_name"].After, ShouldBeNil)
+		})
+
+		Convey("Identical snapshots record no changes", func() {
+			changes, err := audit.Diff(before, before)
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	beego "github.com/beego/beego/v2/server/web"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestAuthFilterRejectsMissingToken checks that protected routes answer 401 without a token
+func TestAuthFilterRejectsMissingToken(t *testing.T) {
+	Convey("Subject: Auth filter on protected routes\n", t, func() {
+		for _, path := range []string{"/api/users", "/api/sales", "/api/member-points", "/api/me", "/api/me/points"} {
+			r, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			beego.BeeApp.Handlers.ServeHTTP(w, r)
+
+			Convey("Status Code Should Be 401 for "+path, func() {
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-pos/controllers"
+	"go-pos/dto"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestItemBarcodes checks how item SKUs and barcodes are validated and looked up by scan
+func TestItemBarcodes(t *testing.T) {
+	Convey("Subject: Item barcodes and SKUs\n", t, func() {
+		Convey("EAN-13 and UPC-A codes need a correct check digit", func() {
+			So((&model.ItemBarcode{Code: "4006381333931", Type: model.BarcodeEAN13}).Validate(), ShouldBeNil)
+			So((&model.ItemBarcode{Code: "036000291452", Type: model.BarcodeUPCA}).Validate(), ShouldBeNil)
+			So((&model.ItemBarcode{Code: "4006381333932", Type: model.BarcodeEAN13}).Validate(), ShouldNotBeNil)
+			So((&model.ItemBarcode{Code: "036000291452", Type: model.BarcodeEAN13}).Validate(), ShouldNotBeNil)
+			So((&model.ItemBarcode{Code: "03600029145A", Type: model.BarcodeUPCA}).Validate(), ShouldNotBeNil)
+		})
+
+		Convey("Code128 takes printable ASCII and internal codes letters, digits, '-' and '.'", func() {
+			So((&model.ItemBarcode{Code: "ABC 12/x", Type: model.BarcodeCode128}).Validate(), ShouldBeNil)
+			So((&model.ItemBarcode{Code: "café", Type: model.BarcodeCode128}).Validate(), ShouldNotBeNil)
+			So((&model.ItemBarcode{Code: "ST-0042.1", Type: model.BarcodeInternal}).Validate(), ShouldBeNil)
+			So((&model.ItemBarcode{Code: "ST 0042", Type: model.BarcodeInternal}).Validate(), ShouldNotBeNil)
+			So((&model.ItemBarcode{Code: "4006381333931", Type: "QR"}).Validate(), ShouldNotBeNil)
+		})
+
+		Convey("An item's codes are trimmed and may not repeat", func() {
+			item := &model.Item{
+				SKU: " TEA-500 ",
+				Barcodes: []model.ItemBarcode{
+					{Code: "4006381333931 ", Type: model.BarcodeEAN13},
+					{Code: "TEA-500", Type: model.BarcodeInternal},
+				},
+			}
+			So(item.ValidateCodes(), ShouldNotBeNil)
+			So(item.SKU, ShouldEqual, "TEA-500")
+			So(item.Barcodes[0].Code, ShouldEqual, "4006381333931")
+
+			item.Barcodes = item.Barcodes[:1]
+			So(item.ValidateCodes(), ShouldBeNil)
+
+			item.SKU = "TEA 500"
+			So(item.ValidateCodes(), ShouldNotBeNil)
+		})
+
+		Convey("A UPC-A code is looked up in both its UPC-A and EAN-13 forms", func() {
+			So(model.ScanCodes(" 036000291452"), ShouldResemble, []string{"036000291452", "0036000291452"})
+			So(model.ScanCodes("0036000291452"), ShouldResemble, []string{"0036000291452", "036000291452"})
+			So(model.ScanCodes("4006381333931"), ShouldResemble, []string{"4006381333931"})
+			So(model.ScanCodes("TEA-500"), ShouldResemble, []string{"TEA-500"})
+			So(model.ScanCodes(" "), ShouldBeEmpty)
+		})
+
+		Convey("A scan returns the item with its price, codes and stock", func() {
+			item := &model.Item{
+				ID:       7,
+				Name:     "Green tea 500ml",
+				SKU:      "TEA-500",
+				Price:    8500,
+				Barcodes: []model.ItemBarcode{{ID: 3, ItemID: 7, Code: "4006381333931", Type: model.BarcodeEAN13}},
+			}
+			response := dto.NewItemScanResponse(item, 24)
+			So(response.ID, ShouldEqual, 7)
+			So(response.Price, ShouldEqual, 8500)
+			So(response.SKU, ShouldEqual, "TEA-500")
+			So(response.Barcodes, ShouldResemble, []dto.BarcodeResponse{{Code: "4006381333931", Type: model.BarcodeEAN13}})
+			So(response.Stock, ShouldEqual, 24)
+		})
+
+		Convey("An update that leaves out the SKU, tax rate and barcodes keeps them", func() {
+			saved := &model.Item{
+				ID:        7,
+				SKU:       "TEA-500",
+				TaxRateID: 2,
+				Barcodes:  []model.ItemBarcode{{ID: 3, ItemID: 7, Code: "4006381333931", Type: model.BarcodeEAN13}},
+			}
+
+			var request controllers.ItemRequest
+			So(json.Unmarshal([]byte(`{"item_name": "Green tea 500ml", "item_price": 9000}`), &request), ShouldBeNil)
+			item := request.ToItem(saved)
+			So(item.Name, ShouldEqual, "Green tea 500ml")
+			So(item.Price, ShouldEqual, 9000)
+			So(item.SKU, ShouldEqual, "TEA-500")
+			So(item.TaxRateID, ShouldEqual, 2)
+			So(item.Barcodes, ShouldBeNil)
+
+			Convey("while an empty SKU or barcode list or a tax rate of 0 clears them", func() {
+				var request controllers.ItemRequest
+				So(json.Unmarshal([]byte(`{"item_name": "Green tea 500ml", "sku": "", "id_tax_rate": 0, "barcodes": []}`), &request), ShouldBeNil)
+				item := request.ToItem(saved)
+				So(item.SKU, ShouldBeEmpty)
+				So(item.TaxRateID, ShouldEqual, 0)
+				So(item.Barcodes, ShouldNotBeNil)
+				So(item.Barcodes, ShouldBeEmpty)
+			})
+
+			Convey("and a new item without them has none", func() {
+				item := request.ToItem(nil)
+				So(item.SKU, ShouldBeEmpty)
+				So(item.TaxRateID, ShouldEqual, 0)
+				So(item.Barcodes, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go-pos/controllers"
+	"go-pos/dto"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	secretHash  = "$2a$10$secretsecretsecretsecretsecretsecretsecretsecret"
+	secretToken = "0123456789abcdef-secret-token"
+)
+
+// TestResponsesNeverLeakSecrets checks that no public response carries password hashes or tokens
+func TestResponsesNeverLeakSecrets(t *testing.T) {
+	user := model.User{ID: 1, NIK: 1001, Name: "Cashier", PasswordHash: secretHash}
+	member := model.Member{ID: 2, Name: "Member", PasswordHash: secretHash, Token: secretToken}
+	session := model.UserSession{ID: "s1", UserID: 1, TokenHash: secretToken, RefreshTokenHash: secretToken}
+	basket := model.SalesBasket{
+		ID:     3,
+		User:   &user,
+		Member: &member,
+		Items:  []model.SalesItem{{ID: 4, SalesID: 3, Sales: &model.SalesBasket{ID: 3, User: &user}}},
+	}
+	memberPoint := model.MemberPoint{ID: 5, MemberID: 2, Member: &member}
+	userLog := model.UserLog{ID: 6, UserID: 1, User: &user}
+	userMember := model.UserMember{ID: 7, MemberID: 2, Member: &member}
+	apiKey := model.APIKey{ID: 8, Name: "sync", Prefix: "gpk_01234567", KeyHash: secretToken, UserID: 1}
+
+	responses := map[string]interface{}{
+		"user":         dto.NewUserResponse(&user),
+		"users":        dto.NewUserResponses([]model.User{user}),
+		"member":       dto.NewMemberResponse(&member),
+		"members":      dto.NewMemberResponses([]model.Member{member}),
+		"sessions":     dto.NewSessionResponses([]model.UserSession{session}),
+		"sales basket": dto.NewSalesBasketResponse(&basket),
+		"member point": dto.NewMemberPointResponse(&memberPoint),
+		"user log":     dto.NewUserLogResponse(&userLog),
+		"user member":  dto.NewUserMemberResponse(&userMember),
+		"api key":      dto.NewAPIKeyResponse(&apiKey),
+	}
+
+	Convey("Subject: Public response DTOs\n", t, func() {
+		for name, data := range responses {
+			body, err := json.Marshal(controllers.Response{Status: 200, Message: "ok", Data: data})
+			So(err, ShouldBeNil)
+
+			Convey("The "+name+" response should not contain secrets", func() {
+				So(string(body), ShouldNotContainSubstring, "password_hash")
+				So(string(body), ShouldNotContainSubstring, `"token"`)
+				So(string(body), ShouldNotContainSubstring, secretHash)
+				So(string(body), ShouldNotContainSubstring, secretToken)
+			})
+		}
+
+		Convey("Relation stubs should not be serialized", func() {
+			body, _ := json.Marshal(dto.NewSalesBasketResponse(&basket))
+			So(strings.Contains(string(body), `"user"`), ShouldBeFalse)
+			So(strings.Contains(string(body), `"member"`), ShouldBeFalse)
+			So(strings.Contains(string(body), `"sales"`), ShouldBeFalse)
+		})
+	})
+}
+
+// TestMemberRequestPassword checks that a member's password is only taken from the password field
+func TestMemberRequestPassword(t *testing.T) {
+	Convey("Subject: Member request bodies\n", t, func() {
+		var request controllers.MemberRequest
+		body := `{"name_member": "Member", "password": "plain-secret", "password_hash": "` + secretHash + `"}`
+		So(json.Unmarshal([]byte(body), &request), ShouldBeNil)
+
+		Convey("The plaintext password is read from the password field", func() {
+			So(request.Name, ShouldEqual, "Member")
+			So(request.Password, ShouldEqual, "plain-secret")
+		})
+
+		Convey("A password hash is never bound from the request", func() {
+			So(request.PasswordHash, ShouldBeEmpty)
+		})
+	})
+}
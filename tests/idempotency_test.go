@@ -0,0 +1,35 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/model"
+	"go-pos/security"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestIdempotencyKey checks when a stored idempotency key replays its response
+func TestIdempotencyKey(t *testing.T) {
+	Convey("Subject: Idempotency keys\n", t, func() {
+		body := `{"id_member":3,"items":[{"id_item":1,"qty":2}]}`
+		key := &model.IdempotencyKey{
+			UserID:      7,
+			Key:         "register-2-0001",
+			Route:       "POST /api/sales",
+			RequestHash: security.HashToken(body),
+		}
+
+		Convey("A key is pending until its first request is answered", func() {
+			So(key.IsPending(), ShouldBeTrue)
+			key.StatusCode = 201
+			So(key.IsPending(), ShouldBeFalse)
+		})
+
+		Convey("Only the same request on the same route is a retry", func() {
+			So(key.Matches("POST /api/sales", security.HashToken(body)), ShouldBeTrue)
+			So(key.Matches("POST /api/sales", security.HashToken(`{"id_member":3}`)), ShouldBeFalse)
+			So(key.Matches("POST /api/member-points", security.HashToken(body)), ShouldBeFalse)
+		})
+	})
+}
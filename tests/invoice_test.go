@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/config"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestInvoiceNumbers checks how invoice numbers are laid out and grouped into series
+func TestInvoiceNumbers(t *testing.T) {
+	Convey("Subject: Invoice numbering\n", t, func() {
+		day := time.Date(2026, 3, 2, 23, 59, 0, 0, time.Local)
+		policy := &config.InvoicePolicy{
+			Format:          "{store}-{register}-{date}-{seq}",
+			StoreCode:       "TM",
+			DefaultRegister: "00",
+			Digits:          4,
+		}
+
+		Convey("Numbers carry the store, register, day and a padded sequence", func() {
+			So(policy.Number("R1", day, 7), ShouldEqual, "TM-R1-20260302-0007")
+			So(policy.Number("R1", day, 12345), ShouldEqual, "TM-R1-20260302-12345")
+		})
+
+		Convey("Each register and day is its own series", func() {
+			So(policy.Series("R1", day), ShouldEqual, "TM-R1-20260302-")
+			So(policy.Series("R2", day), ShouldNotEqual, policy.Series("R1", day))
+			So(policy.Series("R1", day.Add(time.Minute)), ShouldNotEqual, policy.Series("R1", day))
+		})
+
+		Convey("Sales without a register use the default one", func() {
+			So(policy.Number("", day, 1), ShouldEqual, "TM-00-20260302-0001")
+		})
+
+		Convey("Formats without a sequence still number uniquely", func() {
+			policy.Format = "INV/{date}"
+			So(policy.Number("R1", day, 3), ShouldEqual, "INV/20260302-0003")
+			So(policy.Series("R1", day), ShouldEqual, "INV/20260302-")
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/config"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestLoginAttemptLockout checks the failure counting and lockout rules
+func TestLoginAttemptLockout(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+	lockout := 10 * time.Minute
+
+	Convey("Subject: Login attempt counters\n", t, func() {
+		Convey("Reaching the maximum locks the key", func() {
+			attempt := &model.LoginAttempt{Key: "nik:1"}
+			for i := 0; i < 3; i++ {
+				attempt.RegisterFailure(now, window, 3, lockout)
+			}
+			So(attempt.Failures, ShouldEqual, 3)
+			So(attempt.IsLocked(now), ShouldBeTrue)
+			So(attempt.IsLocked(now.Add(lockout)), ShouldBeFalse)
+		})
+
+		Convey("Failures outside the window start a new count", func() {
+			attempt := &model.LoginAttempt{Key: "nik:1", Failures: 2, LastFailedAt: now.Add(-time.Hour)}
+			attempt.RegisterFailure(now, window, 3, lockout)
+			So(attempt.Failures, ShouldEqual, 1)
+			So(attempt.IsLocked(now), ShouldBeFalse)
+		})
+
+		Convey("The progressive delay is capped", func() {
+			policy := &config.LoginPolicy{DelayStep: time.Second, MaxDelay: 3 * time.Second}
+			So(policy.Delay(0), ShouldEqual, 0)
+			So(policy.Delay(2), ShouldEqual, 2*time.Second)
+			So(policy.Delay(10), ShouldEqual, 3*time.Second)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestEarnPoints checks how many loyalty points a completed sale earns
+func TestEarnPoints(t *testing.T) {
+	Convey("Subject: Loyalty point accrual\n", t, func() {
+		lines := []model.SalesItem{
+			{ItemID: 1, Qty: 2, TotalAmount: 27750, TaxAmount: 2750},
+			{ItemID: 2, Qty: 1, TotalAmount: 15000},
+			{ItemID: 3, Qty: 1, TotalAmount: 40000},
+		}
+
+		Convey("One point is earned per N rupiah spent before tax, rounded down", func() {
+			rule := pricing.EarningRule{RupiahPerPoint: 10000}
+			So(pricing.EarnPoints(lines, rule), ShouldEqual, 8)
+		})
+
+		Convey("Category multipliers scale the spend and excluded items earn nothing", func() {
+			rule := pricing.EarningRule{
+				RupiahPerPoint:      10000,
+				CategoryMultipliers: map[int]int{5: 200, 6: 150},
+				ExcludedItems:       map[int]bool{3: true},
+				Categories:          map[int]int{1: 5, 2: 6, 3: 5},
+			}
+			// 25.000 x 2 + 15.000 x 1.5 = 72.500
+			So(pricing.EarnPoints(lines, rule), ShouldEqual, 7)
+		})
+
+		Convey("Earning is off without a rupiah amount per point", func() {
+			So(pricing.EarnPoints(lines, pricing.EarningRule{}), ShouldEqual, 0)
+		})
+
+		Convey("The part of the sale paid with points earns nothing", func() {
+			// 80.000 before tax of an 82.750 sale, of which 41.375 paid with points
+			rule := pricing.EarningRule{RupiahPerPoint: 10000, PointsPaid: 41375}
+			So(pricing.EarnPoints(lines, rule), ShouldEqual, 4)
+		})
+	})
+}
+
+// TestRedeemPoints checks points spent as a tender against the redemption rule
+func TestRedeemPoints(t *testing.T) {
+	Convey("Subject: Paying with loyalty points\n", t, func() {
+		rule := pricing.RedemptionRule{RupiahPerPoint: 100, MinimumPoints: 100, MaxShare: 50}
+
+		Convey("Points are valued at the rule's rate and settle with the other tenders", func() {
+			tenders := []model.SalesPayment{
+				{Method: model.PaymentMethodPoints, Points: 300},
+				{Method: model.PaymentMethodCash, Tendered: 100000},
+			}
+			points, err := pricing.RedeemPoints(80000, tenders, rule)
+			So(err, ShouldBeNil)
+			So(points, ShouldEqual, 300)
+			So(tenders[0].Tendered, ShouldEqual, 30000)
+
+			So(pricing.SettleTenders(80000, tenders), ShouldBeNil)
+			So(tenders[0].Amount, ShouldEqual, 30000)
+			So(tenders[1].Change, ShouldEqual, 50000)
+		})
+
+		Convey("Fewer points than the minimum are rejected", func() {
+			tenders := []model.SalesPayment{{Method: model.PaymentMethodPoints, Points: 50}}
+			_, err := pricing.RedeemPoints(80000, tenders, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Points may not pay more than the maximum share of the total", func() {
+			tenders := []model.SalesPayment{{Method: model.PaymentMethodPoints, Points: 401}}
+			_, err := pricing.RedeemPoints(80000, tenders, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A points tender needs its points and redemption turned on", func() {
+			_, err := pricing.RedeemPoints(80000, []model.SalesPayment{{Method: model.PaymentMethodPoints, Tendered: 10000}}, rule)
+			So(err, ShouldNotBeNil)
+
+			_, err = pricing.RedeemPoints(80000, []model.SalesPayment{{Method: model.PaymentMethodPoints, Points: 200}}, pricing.RedemptionRule{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Sales without points tenders redeem nothing", func() {
+			points, err := pricing.RedeemPoints(80000, []model.SalesPayment{{Method: model.PaymentMethodCash, Tendered: 80000}}, rule)
+			So(err, ShouldBeNil)
+			So(points, ShouldEqual, 0)
+		})
+
+		Convey("A void gives spent points back before taking earned ones the member spent again", func() {
+			// 300 points paid part of the sale, which earned 50; the member has since spent everything
+			reversed, balance := pricing.ReverseSalePoints(0, 300, 50)
+			So(reversed, ShouldEqual, 50)
+			So(balance, ShouldEqual, 250)
+
+			// Spent points that do not cover the earned ones leave the rest with the member
+			reversed, balance = pricing.ReverseSalePoints(0, 30, 50)
+			So(reversed, ShouldEqual, 30)
+			So(balance, ShouldEqual, 0)
+
+			reversed, balance = pricing.ReverseSalePoints(100, 0, 50)
+			So(reversed, ShouldEqual, 50)
+			So(balance, ShouldEqual, 50)
+		})
+
+		Convey("A return does the same with its share of the sale's points", func() {
+			// Half of a 100000 sale that spent 300 points and earned 50 is returned
+			restored := pricing.ReversePoints(300, 100000, 0, 50000, 0)
+			earned := pricing.ReversePoints(50, 100000, 0, 50000, 0)
+			reversed, balance := pricing.ReverseSalePoints(0, restored, earned)
+			So(restored, ShouldEqual, 150)
+			So(reversed, ShouldEqual, 25)
+			So(balance, ShouldEqual, 125)
+		})
+
+		Convey("Each point transaction moves the balance by its points in its type's direction", func() {
+			So((&model.MemberPoint{Type: model.PointTypeEarned, Points: 40}).Delta(), ShouldEqual, 40)
+			So((&model.MemberPoint{Type: model.PointTypeRestored, Points: 40}).Delta(), ShouldEqual, 40)
+			So((&model.MemberPoint{Type: model.PointTypeRedeemed, Points: 40}).Delta(), ShouldEqual, -40)
+			So((&model.MemberPoint{Type: model.PointTypeReversed, Points: 40}).Delta(), ShouldEqual, -40)
+		})
+
+		Convey("All four point types are valid and nothing else is", func() {
+			So(model.PointTypeEarned.IsValid(), ShouldBeTrue)
+			So(model.PointTypeRedeemed.IsValid(), ShouldBeTrue)
+			So(model.PointTypeReversed.IsValid(), ShouldBeTrue)
+			So(model.PointTypeRestored.IsValid(), ShouldBeTrue)
+			So(model.PointType("earned").IsValid(), ShouldBeFalse)
+			So(model.PointType("").IsValid(), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestPriceBasket checks that sales are priced from the catalogue rather than the client
+func TestPriceBasket(t *testing.T) {
+	prices := map[int]int{1: 5000, 2: 12500}
+
+	newBasket := func() *model.SalesBasket {
+		return &model.SalesBasket{Items: []model.SalesItem{
+			{ItemID: 1, Qty: 2},
+			{ItemID: 2, Qty: 1},
+		}}
+	}
+
+	Convey("Subject: Server-side pricing\n", t, func() {
+		Convey("Line totals and the basket total are computed from the prices", func() {
+			basket := newBasket()
+			So(pricing.PriceBasket(basket, prices, nil, nil, true), ShouldBeNil)
+			So(basket.Items[0].UnitPrice, ShouldEqual, 5000)
+			So(basket.Items[0].TotalAmount, ShouldEqual, 10000)
+			So(basket.Total, ShouldEqual, 22500)
+		})
+
+		Convey("A mismatched client line total is rejected in strict mode", func() {
+			basket := newBasket()
+			basket.Items[0].TotalAmount = 1
+			err := pricing.PriceBasket(basket, prices, nil, nil, true)
+			So(err, ShouldNotBeNil)
+			So(err, ShouldHaveSameTypeAs, &pricing.MismatchError{})
+		})
+
+		Convey("A mismatched client basket total is rejected in strict mode", func() {
+			basket := newBasket()
+			basket.Total = 100
+			So(pricing.PriceBasket(basket, prices, nil, nil, true), ShouldNotBeNil)
+		})
+
+		Convey("Client totals are replaced when not strict", func() {
+			basket := newBasket()
+			basket.Items[0].TotalAmount = 1
+			basket.Total = 100
+			So(pricing.PriceBasket(basket, prices, nil, nil, false), ShouldBeNil)
+			So(basket.Items[0].TotalAmount, ShouldEqual, 10000)
+			So(basket.Total, ShouldEqual, 22500)
+		})
+
+		Convey("Unknown items and empty quantities are refused", func() {
+			basket := &model.SalesBasket{Items: []model.SalesItem{{ItemID: 9, Qty: 1}}}
+			So(pricing.PriceBasket(basket, prices, nil, nil, false), ShouldNotBeNil)
+
+			basket = &model.SalesBasket{Items: []model.SalesItem{{ItemID: 1, Qty: 0}}}
+			So(pricing.PriceBasket(basket, prices, nil, nil, false), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestPromotions checks how running promotions discount sales lines and baskets
+func TestPromotions(t *testing.T) {
+	Convey("Subject: Promotions\n", t, func() {
+		noon := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local)
+		lines := func() []model.SalesItem {
+			return []model.SalesItem{
+				{ID: 1, ItemID: 10, Qty: 5, UnitPrice: 10000},
+				{ID: 2, ItemID: 20, Qty: 2, UnitPrice: 25000},
+			}
+		}
+
+		Convey("Each line gets the item or category promotion that saves the most", func() {
+			offer := &pricing.Offer{
+				Promotions: []model.Promotion{
+					{ID: 1, Type: model.PromotionPercent, Scope: model.PromotionScopeItem, TargetID: 10, Value: 10, Active: true},
+					{ID: 2, Type: model.PromotionBuyXGetY, Scope: model.PromotionScopeCategory, TargetID: 3, BuyQty: 2, GetQty: 1, Active: true},
+				},
+				Categories: map[int]int{10: 3, 20: 4},
+				At:         noon,
+			}
+			items := lines()
+			total := pricing.ApplyPromotions(items, offer)
+
+			// Buy 2 get 1 on 5 units gives one free unit, more than 10% off
+			So(items[0].Discount, ShouldEqual, 10000)
+			So(items[0].TotalAmount, ShouldEqual, 40000)
+			So(items[0].Promotions, ShouldResemble, []model.SalesItemPromotion{{SalesItemID: 1, PromotionID: 2, Discount: 10000}})
+			So(items[1].Discount, ShouldEqual, 0)
+			So(total, ShouldEqual, 90000)
+		})
+
+		Convey("A basket promotion is spread over the lines once its minimum spend is reached", func() {
+			basketOffer := model.Promotion{ID: 3, Type: model.PromotionFixed, Scope: model.PromotionScopeBasket, Value: 10000, MinSpend: 100000, Active: true}
+			offer := &pricing.Offer{Promotions: []model.Promotion{basketOffer}, At: noon}
+
+			items := lines()
+			total := pricing.ApplyPromotions(items, offer)
+			So(total, ShouldEqual, 90000)
+			So(items[0].Discount+items[1].Discount, ShouldEqual, 10000)
+			So(items[0].TotalAmount+items[1].TotalAmount, ShouldEqual, total)
+
+			basketOffer.MinSpend = 100001
+			offer.Promotions = []model.Promotion{basketOffer}
+			items = lines()
+			So(pricing.ApplyPromotions(items, offer), ShouldEqual, 100000)
+		})
+
+		Convey("Promotions only run inside their dates and daily window", func() {
+			happyHour := model.Promotion{Type: model.PromotionFixed, Scope: model.PromotionScopeItem, TargetID: 10, Value: 1000, DailyFrom: "17:00", DailyTo: "19:00", Active: true}
+			So(happyHour.IsActiveAt(noon), ShouldBeFalse)
+			So(happyHour.IsActiveAt(noon.Add(5*time.Hour+30*time.Minute)), ShouldBeTrue)
+
+			overnight := model.Promotion{Active: true, DailyFrom: "22:00", DailyTo: "02:00"}
+			So(overnight.IsActiveAt(noon.Add(13*time.Hour)), ShouldBeTrue)
+			So(overnight.IsActiveAt(noon), ShouldBeFalse)
+
+			ended := model.Promotion{Active: true, EndsAt: noon}
+			So(ended.IsActiveAt(noon), ShouldBeFalse)
+
+			switchedOff := model.Promotion{StartsAt: noon.AddDate(0, 0, -1)}
+			So(switchedOff.IsActiveAt(noon), ShouldBeFalse)
+		})
+
+		Convey("Discounts never exceed the line", func() {
+			offer := &pricing.Offer{
+				Promotions: []model.Promotion{{ID: 4, Type: model.PromotionFixed, Scope: model.PromotionScopeItem, TargetID: 20, Value: 30000, Active: true}},
+				At:         noon,
+			}
+			items := lines()
+			pricing.ApplyPromotions(items, offer)
+			So(items[1].Discount, ShouldEqual, 50000)
+			So(items[1].TotalAmount, ShouldEqual, 0)
+		})
+
+		Convey("Incomplete promotions are rejected", func() {
+			buyGet := model.Promotion{Name: "Basket freebie", Type: model.PromotionBuyXGetY, Scope: model.PromotionScopeBasket, BuyQty: 1, GetQty: 1}
+			So(buyGet.Validate(), ShouldNotBeNil)
+
+			untargeted := model.Promotion{Name: "10% off", Type: model.PromotionPercent, Scope: model.PromotionScopeItem, Value: 10}
+			So(untargeted.Validate(), ShouldNotBeNil)
+
+			halfWindow := model.Promotion{Name: "Happy hour", Type: model.PromotionPercent, Scope: model.PromotionScopeBasket, Value: 10, DailyFrom: "17:00"}
+			So(halfWindow.Validate(), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go-pos/model"
+	"go-pos/receipt"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestReceipt checks how a paid sale is laid out on receipts
+func TestReceipt(t *testing.T) {
+	Convey("Subject: Receipt rendering\n", t, func() {
+		basket := &model.SalesBasket{
+			ID:        42,
+			InvoiceNo: "TM-R1-20260302-0007",
+			SalesDate: int(time.Date(2026, 3, 2, 14, 5, 0, 0, time.Local).Unix()),
+			Register:  "R1",
+			Total:     45000,
+			TaxAmount: 4459,
+			TaxMode:   model.TaxInclusive,
+			Items: []model.SalesItem{
+				{ItemID: 1, Qty: 2, UnitPrice: 12500, TotalAmount: 25000},
+				{ItemID: 2, Qty: 1, UnitPrice: 25000, Discount: 5000, TotalAmount: 20000},
+			},
+			Payments: []model.SalesPayment{{Method: model.PaymentMethodCash, Amount: 45000, Tendered: 50000, Change: 5000}},
+		}
+		names := map[int]string{1: "Teh Botol", 2: "Kopi Bubuk 250g"}
+		member := &model.Member{Name: "Siti", Points: 120}
+		store := receipt.Store{Name: "Toko Maju", Address: "Jl. Merdeka 1", Footer: "Terima kasih"}
+
+		r := receipt.New(store, basket, names, "Budi", member, 45)
+
+		Convey("Text receipts fit the paper and carry every section", func() {
+			for _, columns := range []int{receipt.Columns58mm, receipt.Columns80mm} {
+				text := receipt.Text(r, columns)
+				for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+					So(len([]rune(line)), ShouldBeLessThanOrEqualTo, columns)
+				}
+				So(text, ShouldContainSubstring, "Toko Maju")
+				So(text, ShouldContainSubstring, "Kopi Bubuk 250g")
+				So(text, ShouldContainSubstring, "45.000")
+				So(text, ShouldContainSubstring, "-5.000")
+				So(text, ShouldContainSubstring, "Budi")
+				So(text, ShouldContainSubstring, "TM-R1-20260302-0007")
+				So(text, ShouldContainSubstring, "Points balance")
+				So(text, ShouldNotContainSubstring, "COPY")
+			}
+		})
+
+		Convey("Reprints are flagged as copies", func() {
+			r.Print, r.Copy = 2, true
+			So(receipt.Text(r, receipt.Columns80mm), ShouldContainSubstring, "COPY (reprint 1)")
+		})
+
+		Convey("ESC/POS streams reset the printer and end with a cut", func() {
+			stream := receipt.ESCPOS(r, receipt.Columns58mm)
+			So(bytes.HasPrefix(stream, []byte{0x1b, '@'}), ShouldBeTrue)
+			So(bytes.HasSuffix(stream, []byte{0x1d, 'V', 1}), ShouldBeTrue)
+			So(bytes.Contains(stream, []byte("Teh Botol")), ShouldBeTrue)
+		})
+
+		Convey("PDF receipts are complete documents", func() {
+			document := receipt.PDF(r, receipt.Columns80mm)
+			So(bytes.HasPrefix(document, []byte("%PDF-1.4")), ShouldBeTrue)
+			So(bytes.HasSuffix(document, []byte("%%EOF\n")), ShouldBeTrue)
+			So(bytes.Contains(document, []byte("Jl. Merdeka 1) Tj")), ShouldBeTrue)
+		})
+
+		Convey("Amounts are grouped in thousands", func() {
+			So(receipt.Amount(1250000), ShouldEqual, "1.250.000")
+			So(receipt.Amount(-500), ShouldEqual, "-500")
+			So(receipt.Amount(0), ShouldEqual, "0")
+		})
+	})
+}
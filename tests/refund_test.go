@@ -0,0 +1,129 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/inventory"
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestRefunds checks how returned lines are priced, restocked and reverse member points
+func TestRefunds(t *testing.T) {
+	line := model.SalesItem{ID: 7, ItemID: 3, Qty: 3, UnitPrice: 333, TotalAmount: 1000}
+
+	Convey("Subject: Returns and refunds\n", t, func() {
+		Convey("Partial returns of a line add up to exactly its total", func() {
+			first, err := pricing.RefundLine(line, 0, 1)
+			So(err, ShouldBeNil)
+			second, err := pricing.RefundLine(line, 1, 1)
+			So(err, ShouldBeNil)
+			third, err := pricing.RefundLine(line, 2, 1)
+			So(err, ShouldBeNil)
+
+			So(first.SalesItemID, ShouldEqual, 7)
+			So(first.ItemID, ShouldEqual, 3)
+			So(first.UnitPrice, ShouldEqual, 333)
+			So(first.TotalRefund+second.TotalRefund+third.TotalRefund, ShouldEqual, 1000)
+		})
+
+		Convey("More than is left to return is rejected", func() {
+			_, err := pricing.RefundLine(line, 2, 2)
+			overErr, ok := err.(*pricing.OverReturnError)
+			So(ok, ShouldBeTrue)
+			So(overErr.Returnable, ShouldEqual, 1)
+		})
+
+		Convey("A zero quantity is rejected", func() {
+			_, err := pricing.RefundLine(line, 0, 0)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Points are reversed in proportion and a full refund reverses them all", func() {
+			So(pricing.ReversePoints(10, 1000, 0, 250, 0), ShouldEqual, 2)
+			So(pricing.ReversePoints(10, 1000, 250, 750, 2), ShouldEqual, 8)
+			So(pricing.ReversePoints(10, 1000, 0, 1000, 0), ShouldEqual, 10)
+			So(pricing.ReversePoints(0, 1000, 0, 1000, 0), ShouldEqual, 0)
+		})
+
+		Convey("Stock goes back to the batches it was drawn from, latest first", func() {
+			drawn := []model.SalesItemBatch{
+				{SalesItemID: 7, BatchID: 1, Qty: 2},
+				{SalesItemID: 7, BatchID: 2, Qty: 1},
+			}
+
+			batches := inventory.Restock(drawn, map[int]int{}, 2)
+			So(len(batches), ShouldEqual, 2)
+			So(batches[0].BatchID, ShouldEqual, 2)
+			So(batches[0].Qty, ShouldEqual, 1)
+			So(batches[1].BatchID, ShouldEqual, 1)
+			So(batches[1].Qty, ShouldEqual, 1)
+
+			Convey("Earlier returns are not restocked twice", func() {
+				batches := inventory.Restock(drawn, map[int]int{2: 1, 1: 1}, 1)
+				So(len(batches), ShouldEqual, 1)
+				So(batches[0].BatchID, ShouldEqual, 1)
+				So(batches[0].Qty, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Units without a batch allocation are not restocked", func() {
+			So(inventory.Restock(nil, map[int]int{}, 2), ShouldBeEmpty)
+		})
+
+		Convey("A refund is split over the sale's tenders in proportion to what each paid", func() {
+			tenders := []model.SalesPayment{
+				{Method: model.PaymentMethodDebit, Amount: 60000, Tendered: 60000},
+				{Method: model.PaymentMethodCash, Amount: 30000, Tendered: 50000, Change: 20000},
+				{Method: model.PaymentMethodPoints, Amount: 10000, Points: 100},
+			}
+
+			payments := pricing.SplitRefund(tenders, map[model.PaymentMethod]int{}, 45000, model.PaymentMethodDebit)
+			So(payments, ShouldResemble, []model.SalesReturnPayment{
+				{Method: model.PaymentMethodDebit, Amount: 30000},
+				{Method: model.PaymentMethodCash, Amount: 15000},
+			})
+
+			Convey("Later returns give back what each method has left", func() {
+				before := map[model.PaymentMethod]int{model.PaymentMethodDebit: 30000, model.PaymentMethodCash: 15000}
+				payments := pricing.SplitRefund(tenders, before, 45000, model.PaymentMethodDebit)
+				So(payments, ShouldResemble, []model.SalesReturnPayment{
+					{Method: model.PaymentMethodDebit, Amount: 30000},
+					{Method: model.PaymentMethodCash, Amount: 15000},
+				})
+			})
+
+			Convey("Rounding never refunds a method more than it paid", func() {
+				small := []model.SalesPayment{
+					{Method: model.PaymentMethodDebit, Amount: 2},
+					{Method: model.PaymentMethodCash, Amount: 1},
+				}
+				first := pricing.SplitRefund(small, map[model.PaymentMethod]int{}, 1, model.PaymentMethodDebit)
+				So(first, ShouldResemble, []model.SalesReturnPayment{{Method: model.PaymentMethodDebit, Amount: 1}})
+
+				rest := pricing.SplitRefund(small, map[model.PaymentMethod]int{model.PaymentMethodDebit: 1}, 2, model.PaymentMethodDebit)
+				So(rest, ShouldResemble, []model.SalesReturnPayment{
+					{Method: model.PaymentMethodDebit, Amount: 1},
+					{Method: model.PaymentMethodCash, Amount: 1},
+				})
+			})
+
+			Convey("Refunds booked to one method before the split are caught up on", func() {
+				before := map[model.PaymentMethod]int{model.PaymentMethodDebit: 45000}
+				payments := pricing.SplitRefund(tenders, before, 45000, model.PaymentMethodDebit)
+				So(payments, ShouldResemble, []model.SalesReturnPayment{
+					{Method: model.PaymentMethodDebit, Amount: 15000},
+					{Method: model.PaymentMethodCash, Amount: 30000},
+				})
+			})
+
+			Convey("A sale without money tenders is refunded with the fallback method", func() {
+				payments := pricing.SplitRefund(nil, map[model.PaymentMethod]int{}, 5000, model.PaymentMethodCash)
+				So(payments, ShouldResemble, []model.SalesReturnPayment{{Method: model.PaymentMethodCash, Amount: 5000}})
+				So(pricing.SplitRefund(tenders, map[model.PaymentMethod]int{}, 0, model.PaymentMethodCash), ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/dto"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestSalesStatus checks the sale lifecycle states and how they are exposed
+func TestSalesStatus(t *testing.T) {
+	Convey("Subject: Sale lifecycle\n", t, func() {
+		Convey("Only the known statuses are valid", func() {
+			for _, status := range []model.SalesStatus{"OPEN", "HELD", "COMPLETED", "VOIDED", "REFUNDED"} {
+				So(status.IsValid(), ShouldBeTrue)
+			}
+			So(model.SalesStatus("PAID").IsValid(), ShouldBeFalse)
+			So(model.SalesStatus("").IsValid(), ShouldBeFalse)
+		})
+
+		Convey("Open and held baskets are drafts, everything else is final", func() {
+			So(model.SalesStatusOpen.IsDraft(), ShouldBeTrue)
+			So(model.SalesStatusHeld.IsDraft(), ShouldBeTrue)
+			So(model.SalesStatusCompleted.IsDraft(), ShouldBeFalse)
+			So(model.SalesStatusVoided.IsDraft(), ShouldBeFalse)
+			So(model.SalesStatusRefunded.IsDraft(), ShouldBeFalse)
+		})
+
+		Convey("Void details are only shown once a sale is voided", func() {
+			open := dto.NewSalesBasketResponse(&model.SalesBasket{ID: 1, Status: model.SalesStatusOpen})
+			So(open.VoidedAt, ShouldBeNil)
+
+			voided := dto.NewSalesBasketResponse(&model.SalesBasket{
+				ID:             2,
+				Status:         model.SalesStatusVoided,
+				VoidReason:     "Wrong customer",
+				VoidedBy:       3,
+				VoidApprovedBy: 4,
+				VoidedAt:       time.Now(),
+			})
+			So(voided.Status, ShouldEqual, model.SalesStatusVoided)
+			So(voided.VoidReason, ShouldEqual, "Wrong customer")
+			So(voided.VoidApprovedBy, ShouldEqual, 4)
+			So(voided.VoidedAt, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestShiftReport checks how a shift's drawer is reconciled in the X and Z reports
+func TestShiftReport(t *testing.T) {
+	Convey("Subject: Shift X and Z reports\n", t, func() {
+		now := time.Date(2026, 3, 2, 22, 0, 0, 0, time.Local)
+		shift := &model.Shift{ID: 4, UserID: 2, Status: model.ShiftStatusOpen, OpeningFloat: 200000}
+		takings := []model.PaymentTotal{
+			{Method: model.PaymentMethodCash, Count: 12, Amount: 850000},
+			{Method: model.PaymentMethodDebit, Count: 5, Amount: 1200000},
+			{Method: model.PaymentMethodPoints, Count: 1, Amount: 20000},
+		}
+		refunds := []model.PaymentTotal{{Method: model.PaymentMethodCash, Count: 1, Amount: 45000}}
+
+		Convey("The drawer should hold the float plus cash taken less cash refunded", func() {
+			report := model.NewShiftReport(model.ShiftReportX, shift, takings, refunds, nil, now)
+			So(report.CashSales, ShouldEqual, 850000)
+			So(report.CashRefunds, ShouldEqual, 45000)
+			So(report.ExpectedCash, ShouldEqual, 1005000)
+			So(report.Difference, ShouldEqual, 0)
+		})
+
+		Convey("A Z report compares the counted cash with what was expected", func() {
+			shift.Status = model.ShiftStatusClosed
+			shift.CountedCash = 1000000
+			report := model.NewShiftReport(model.ShiftReportZ, shift, takings, refunds, nil, now)
+			So(report.CountedCash, ShouldEqual, 1000000)
+			So(report.Difference, ShouldEqual, -5000)
+			So(report.Shift.ExpectedCash, ShouldEqual, 1005000)
+		})
+
+		Convey("Cash paid in adds to the drawer; pay-outs and safe drops take it out", func() {
+			movements := []model.DrawerMovementTotal{
+				{Type: model.DrawerPayIn, Reason: model.DrawerReasonChangeTopUp, Count: 1, Amount: 100000},
+				{Type: model.DrawerPayOut, Reason: model.DrawerReasonPettyCash, Count: 2, Amount: 35000},
+				{Type: model.DrawerDrop, Reason: model.DrawerReasonSafeDrop, Count: 1, Amount: 500000},
+			}
+			report := model.NewShiftReport(model.ShiftReportX, shift, takings, refunds, movements, now)
+			So(report.PayIns, ShouldEqual, 100000)
+			So(report.PayOuts, ShouldEqual, 35000)
+			So(report.Drops, ShouldEqual, 500000)
+			So(report.ExpectedCash, ShouldEqual, 570000)
+		})
+
+		Convey("A shift without sales expects its float back", func() {
+			report := model.NewShiftReport(model.ShiftReportX, shift, nil, nil, nil, now)
+			So(report.ExpectedCash, ShouldEqual, 200000)
+		})
+	})
+}
+
+// TestDrawerMovement checks the reason codes and amounts a drawer movement accepts
+func TestDrawerMovement(t *testing.T) {
+	Convey("Subject: Cash drawer movements\n", t, func() {
+		Convey("Each type accepts its own reason codes", func() {
+			drop := &model.DrawerMovement{Type: model.DrawerDrop, Reason: model.DrawerReasonSafeDrop, Amount: 500000}
+			So(drop.Validate(), ShouldBeNil)
+
+			drop.Reason = model.DrawerReasonPettyCash
+			So(drop.Validate(), ShouldNotBeNil)
+
+			unknown := &model.DrawerMovement{Type: "REFILL", Reason: model.DrawerReasonChangeTopUp, Amount: 1000}
+			So(unknown.Validate(), ShouldNotBeNil)
+		})
+
+		Convey("Reason OTHER needs a note and amounts must be positive", func() {
+			payOut := &model.DrawerMovement{Type: model.DrawerPayOut, Reason: model.DrawerReasonOther, Amount: 20000}
+			So(payOut.Validate(), ShouldNotBeNil)
+
+			payOut.Note = "Parking fee for delivery"
+			So(payOut.Validate(), ShouldBeNil)
+
+			payOut.Amount = 0
+			So(payOut.Validate(), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"go-pos/inventory"
+	"go-pos/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestStockAllocation checks the batch order and quantity allocation used at checkout
+func TestStockAllocation(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	newBatches := func() []model.ItemBatch {
+		return []model.ItemBatch{
+			{ID: 1, DateIn: day(1), ExpiryDate: day(30), Qty: 5},
+			{ID: 2, DateIn: day(2), ExpiryDate: day(10), Qty: 3},
+			{ID: 3, DateIn: day(3), Qty: 10},
+		}
+	}
+
+	Convey("Subject: Stock allocation\n", t, func() {
+		Convey("FEFO consumes the earliest expiry first and undated batches last", func() {
+			batches := newBatches()
+			inventory.SortBatches(batches, inventory.FEFO)
+			So(batches[0].ID, ShouldEqual, 2)
+			So(batches[1].ID, ShouldEqual, 1)
+			So(batches[2].ID, ShouldEqual, 3)
+		})
+
+		Convey("FIFO consumes the earliest received first", func() {
+			batches := newBatches()
+			inventory.SortBatches(batches, inventory.FIFO)
+			So(batches[0].ID, ShouldEqual, 1)
+			So(batches[1].ID, ShouldEqual, 2)
+		})
+
+		Convey("A sale spanning batches is split between them", func() {
+			batches := newBatches()
+			inventory.SortBatches(batches, inventory.FEFO)
+			allocations, err := inventory.Allocate(7, batches, 6, false)
+			So(err, ShouldBeNil)
+			So(allocations, ShouldResemble, []model.SalesItemBatch{{BatchID: 2, Qty: 3}, {BatchID: 1, Qty: 3}})
+		})
+
+		Convey("Insufficient stock fails unless negative stock is allowed", func() {
+			batches := newBatches()
+			_, err := inventory.Allocate(7, batches, 20, false)
+			So(err, ShouldHaveSameTypeAs, &inventory.InsufficientStockError{})
+			So(err.(*inventory.InsufficientStockError).Available, ShouldEqual, 18)
+
+			allocations, err := inventory.Allocate(7, batches, 20, true)
+			So(err, ShouldBeNil)
+			So(allocations[len(allocations)-1], ShouldResemble, model.SalesItemBatch{BatchID: 3, Qty: 12})
+		})
+
+		Convey("An item without batches cannot be sold", func() {
+			_, err := inventory.Allocate(7, nil, 1, true)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestTax checks how sales lines are taxed with inclusive and exclusive prices
+func TestTax(t *testing.T) {
+	Convey("Subject: Tax calculation\n", t, func() {
+		ppn := model.TaxRate{ID: 1, Name: "PPN", Rate: 1100}
+		prices := map[int]int{1: 11100, 2: 5000}
+		basket := func() *model.SalesBasket {
+			return &model.SalesBasket{Items: []model.SalesItem{
+				{ItemID: 1, Qty: 2},
+				{ItemID: 2, Qty: 1},
+			}}
+		}
+
+		Convey("Inclusive prices carry the tax inside the line total", func() {
+			sale := basket()
+			taxes := &pricing.Taxes{Mode: model.TaxInclusive, Rates: map[int]model.TaxRate{1: ppn}}
+			So(pricing.PriceBasket(sale, prices, nil, taxes, true), ShouldBeNil)
+
+			So(sale.Items[0].TotalAmount, ShouldEqual, 22200)
+			So(sale.Items[0].TaxAmount, ShouldEqual, 2200)
+			So(sale.Items[0].TaxRate, ShouldEqual, 1100)
+			So(sale.Items[1].TaxAmount, ShouldEqual, 0)
+			So(sale.Total, ShouldEqual, 27200)
+			So(sale.TaxAmount, ShouldEqual, 2200)
+			So(sale.TaxMode, ShouldEqual, model.TaxInclusive)
+		})
+
+		Convey("Exclusive prices get the tax added on top", func() {
+			sale := basket()
+			taxes := &pricing.Taxes{Mode: model.TaxExclusive, Rates: map[int]model.TaxRate{1: ppn, 2: ppn}}
+			So(pricing.PriceBasket(sale, prices, nil, taxes, true), ShouldBeNil)
+
+			So(sale.Items[0].TaxAmount, ShouldEqual, 2442)
+			So(sale.Items[0].TotalAmount, ShouldEqual, 24642)
+			So(sale.Items[1].TaxAmount, ShouldEqual, 550)
+			So(sale.Total, ShouldEqual, 24642+5550)
+			So(sale.TaxAmount, ShouldEqual, 2992)
+		})
+
+		Convey("Tax is charged on the discounted line", func() {
+			sale := basket()
+			offer := &pricing.Offer{Promotions: []model.Promotion{
+				{ID: 1, Type: model.PromotionFixed, Scope: model.PromotionScopeItem, TargetID: 2, Value: 1000, Active: true},
+			}}
+			taxes := &pricing.Taxes{Mode: model.TaxExclusive, Rates: map[int]model.TaxRate{2: ppn}}
+			So(pricing.PriceBasket(sale, prices, offer, taxes, false), ShouldBeNil)
+			So(sale.Items[1].TaxAmount, ShouldEqual, 440)
+			So(sale.Items[1].TotalAmount, ShouldEqual, 4440)
+		})
+
+		Convey("Partial returns refund the tax in shares adding up to the line tax", func() {
+			line := model.SalesItem{ID: 7, ItemID: 1, Qty: 3, UnitPrice: 11100, TaxRate: 1100, TaxAmount: 3300, TotalAmount: 33300}
+
+			first, err := pricing.RefundLine(line, 0, 1)
+			So(err, ShouldBeNil)
+			second, err := pricing.RefundLine(line, 1, 2)
+			So(err, ShouldBeNil)
+			So(first.TaxRefund+second.TaxRefund, ShouldEqual, 3300)
+			So(first.TotalRefund+second.TotalRefund, ShouldEqual, 33300)
+		})
+
+		Convey("Tax rates stay within 0 to 100 percent", func() {
+			So((&model.TaxRate{Name: "PPN", Rate: 1100}).Validate(), ShouldBeNil)
+			So((&model.TaxRate{Name: "Bad", Rate: 10001}).Validate(), ShouldNotBeNil)
+			So((&model.TaxRate{Rate: 1100}).Validate(), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"go-pos/model"
+	"go-pos/pricing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestSplitTenders checks how payments settle a sale total and what change is due
+func TestSplitTenders(t *testing.T) {
+	Convey("Subject: Split tenders\n", t, func() {
+		Convey("Part debit and part cash settle the total with change from the cash", func() {
+			tenders := []model.SalesPayment{
+				{Method: model.PaymentMethodDebit, Tendered: 60000, Reference: "A1B2"},
+				{Method: model.PaymentMethodCash, Tendered: 50000},
+			}
+			So(pricing.SettleTenders(95000, tenders), ShouldBeNil)
+			So(tenders[0].Amount, ShouldEqual, 60000)
+			So(tenders[0].Change, ShouldEqual, 0)
+			So(tenders[1].Amount, ShouldEqual, 35000)
+			So(tenders[1].Change, ShouldEqual, 15000)
+			So(pricing.PrimaryMethod(tenders), ShouldEqual, model.PaymentMethodDebit)
+		})
+
+		Convey("Tenders short of the total are rejected", func() {
+			tenders := []model.SalesPayment{{Method: model.PaymentMethodCash, Tendered: 40000}}
+			err := pricing.SettleTenders(50000, tenders)
+			shortErr, ok := err.(*pricing.ShortTenderError)
+			So(ok, ShouldBeTrue)
+			So(shortErr.Due, ShouldEqual, 10000)
+		})
+
+		Convey("Cards cannot be overpaid or given without a reference", func() {
+			over := []model.SalesPayment{{Method: model.PaymentMethodCredit, Tendered: 60000, Reference: "X"}}
+			So(pricing.SettleTenders(50000, over), ShouldNotBeNil)
+
+			unreferenced := []model.SalesPayment{{Method: model.PaymentMethodDebit, Tendered: 50000}}
+			So(pricing.SettleTenders(50000, unreferenced), ShouldNotBeNil)
+		})
+
+		Convey("Tenders after the total is covered are rejected", func() {
+			tenders := []model.SalesPayment{
+				{Method: model.PaymentMethodCash, Tendered: 50000},
+				{Method: model.PaymentMethodCash, Tendered: 1000},
+			}
+			So(pricing.SettleTenders(50000, tenders), ShouldNotBeNil)
+		})
+
+		Convey("Unknown methods and empty tenders are rejected", func() {
+			So(pricing.SettleTenders(100, []model.SalesPayment{{Method: "CHEQUE", Tendered: 100}}), ShouldNotBeNil)
+			So(pricing.SettleTenders(100, []model.SalesPayment{{Method: model.PaymentMethodCash}}), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-pos/security"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// rfcSecret is the RFC 6238 test key "12345678901234567890" encoded as base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestTOTP checks code generation against the RFC 6238 test vectors and the verification window
+func TestTOTP(t *testing.T) {
+	Convey("Subject: TOTP codes\n", t, func() {
+		Convey("Codes match the RFC 6238 test vectors", func() {
+			vectors := map[int64]string{
+				59:         "287082",
+				1111111109: "081804",
+				1234567890: "005924",
+				2000000000: "279037",
+			}
+			for unix, expected := range vectors {
+				code, err := security.TOTPCode(rfcSecret, security.TOTPStep(time.Unix(unix, 0)))
+				So(err, ShouldBeNil)
+				So(code, ShouldEqual, expected)
+			}
+		})
+
+		Convey("Verification tolerates one step of clock drift only", func() {
+			now := time.Unix(1111111109, 0)
+			code, _ := security.TOTPCode(rfcSecret, security.TOTPStep(now)-1)
+
+			step, ok := security.VerifyTOTP(rfcSecret, code, now, 1)
+			So(ok, ShouldBeTrue)
+			So(step, ShouldEqual, security.TOTPStep(now)-1)
+
+			_, ok = security.VerifyTOTP(rfcSecret, code, now.Add(2*security.TOTPPeriod*time.Second), 1)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Malformed codes are rejected", func() {
+			_, ok := security.VerifyTOTP(rfcSecret, "12345", time.Now(), 1)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("The otpauth URI carries the secret and issuer", func() {
+			uri := security.TOTPURI("go-pos", "Admin", rfcSecret)
+			So(strings.HasPrefix(uri, "otpauth://totp/go-pos:Admin?"), ShouldBeTrue)
+			So(uri, ShouldContainSubstring, "secret="+rfcSecret)
+			So(uri, ShouldContainSubstring, "issuer=go-pos")
+		})
+	})
+}